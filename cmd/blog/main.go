@@ -6,13 +6,19 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gera9/blog/internal/auth"
 	"github.com/gera9/blog/internal/controllers"
 	"github.com/gera9/blog/internal/repositories"
 	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/cursor"
 	"github.com/gera9/blog/pkg/middlewares"
 	"github.com/gera9/blog/pkg/postgres"
 	"github.com/gera9/blog/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func main() {
@@ -22,17 +28,121 @@ func main() {
 		log.Fatal(err)
 	}
 
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+
+	cursorSecret := os.Getenv("CURSOR_SECRET")
+	if cursorSecret == "" {
+		log.Fatal("CURSOR_SECRET must be set")
+	}
+
+	maxPageSize := 100
+	if value := os.Getenv("MAX_PAGE_SIZE"); value != "" {
+		maxPageSize, err = strconv.Atoi(value)
+		if err != nil || maxPageSize < 1 {
+			log.Fatalf("invalid MAX_PAGE_SIZE: %q", value)
+		}
+	}
+
+	bcryptCost := bcrypt.DefaultCost
+	if value := os.Getenv("BCRYPT_COST"); value != "" {
+		bcryptCost, err = strconv.Atoi(value)
+		if err != nil || bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			log.Fatalf("invalid BCRYPT_COST: %q", value)
+		}
+	}
+
+	// Only meant for databases still holding passwords stored before they
+	// were hashed, until every such user has logged in once.
+	acceptPlaintextPasswords := false
+	if value := os.Getenv("ACCEPT_PLAINTEXT_PASSWORDS"); value != "" {
+		acceptPlaintextPasswords, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("invalid ACCEPT_PLAINTEXT_PASSWORDS: %q", value)
+		}
+	}
+
+	accessTokenTTL, err := durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	refreshTokenTTL, err := durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	schedulerInterval, err := durationFromEnv("SCHEDULER_INTERVAL", time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	postsRepo := repositories.NewPostsRepository(postgresConn, utils.RealClock{})
 	usersRepo := repositories.NewUsersRepository(postgresConn, utils.RealClock{})
+	refreshTokensRepo := repositories.NewRefreshTokensRepository(postgresConn, utils.RealClock{})
+	tagsRepo := repositories.NewTagsRepository(postgresConn, utils.RealClock{})
+	categoriesRepo := repositories.NewCategoriesRepository(postgresConn, utils.RealClock{})
+	commentsRepo := repositories.NewCommentsRepository(postgresConn, utils.RealClock{})
+
+	policy := services.NewPolicy(services.DefaultGrants)
+
+	postsServ := services.NewPostsService(postsRepo, policy, utils.RealClock{})
+	tagsServ := services.NewTagsService(tagsRepo)
+	categoriesServ := services.NewCategoriesService(categoriesRepo)
+	spamScorer := services.NewSpamScorer(1,
+		services.NewLinkDensityRule(0.2),
+		services.NewBlocklistRule(strings.Split(os.Getenv("SPAM_BLOCKLIST"), ",")),
+		services.NewPostingRateRule(commentsRepo, 5, 10*time.Minute, utils.RealClock{}),
+		services.NewDuplicateRule(commentsRepo, 24*time.Hour, utils.RealClock{}),
+	)
+	commentsServ := services.NewCommentsService(commentsRepo, postsRepo, spamScorer, policy)
+	usersServ := services.NewUsersService(usersRepo, services.NewBcryptHasher(bcryptCost, acceptPlaintextPasswords), policy)
+	tokensServ := services.NewTokenService(services.TokenConfig{
+		Secret:          []byte(jwtSecret),
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}, utils.RealClock{})
+	authServ := services.NewAuthService(usersServ, usersRepo, refreshTokensRepo, tokensServ, utils.RealClock{})
 
-	postsServ := services.NewPostsService(postsRepo)
-	usersServ := services.NewUsersService(usersRepo)
+	cursors := cursor.NewSigner([]byte(cursorSecret))
+	mm := middlewares.NewMiddlewareManager(middlewares.ListConfig{
+		MaxLimit: maxPageSize,
+		Cursors:  cursors,
+	})
+	am := auth.NewMiddleware(authServ, policy)
 
-	mm := &middlewares.MiddlewareManager{}
+	scheduler := services.NewPostScheduler(postsRepo, utils.RealClock{}, schedulerInterval)
+	go scheduler.Run(context.Background())
+
+	go func() {
+		rendered, err := postsServ.RenderPendingPosts(context.Background())
+		if err != nil {
+			log.Println("rendering posts:", err)
+		}
+		if rendered > 0 {
+			log.Printf("rendered %d posts", rendered)
+		}
+	}()
 
 	addr := fmt.Sprintf(":%s", os.Getenv("APP_PORT"))
 
 	log.Println("Listening on addr:", addr)
 
-	http.ListenAndServe(addr, controllers.BuildRoutes(mm, usersServ, postsServ))
+	http.ListenAndServe(addr, controllers.BuildRoutes(mm, am, cursors, utils.RealClock{}, authServ, usersServ, postsServ, tagsServ, categoriesServ, commentsServ))
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return d, nil
 }
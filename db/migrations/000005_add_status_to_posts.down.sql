@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_status_published_at_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS published_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS status;
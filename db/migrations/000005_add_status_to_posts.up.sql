@@ -0,0 +1,10 @@
+-- Posts created before the workflow existed were already public.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE posts SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
+
+ALTER TABLE posts ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS posts_status_published_at_idx ON posts (status, published_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_scheduled_at_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS scheduled_at;
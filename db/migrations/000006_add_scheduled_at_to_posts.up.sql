@@ -0,0 +1,3 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS posts_scheduled_at_idx ON posts (scheduled_at) WHERE scheduled_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS post_slug_history;
+DROP INDEX IF EXISTS posts_author_id_slug_key;
+ALTER TABLE posts DROP COLUMN IF EXISTS slug;
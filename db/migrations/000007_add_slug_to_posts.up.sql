@@ -0,0 +1,28 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- Existing posts get an ASCII slug from their title; numbering keeps them
+-- unique per author. New slugs are generated by the application.
+WITH generated AS (
+    SELECT id, author_id,
+        COALESCE(NULLIF(trim(BOTH '-' FROM lower(regexp_replace(title, '[^a-zA-Z0-9]+', '-', 'g'))), ''), 'post') AS base
+    FROM posts
+), numbered AS (
+    SELECT id, base, row_number() OVER (PARTITION BY author_id, base ORDER BY id) AS n
+    FROM generated
+)
+UPDATE posts SET slug = CASE WHEN numbered.n = 1 THEN numbered.base ELSE numbered.base || '-' || numbered.n END
+FROM numbered WHERE posts.id = numbered.id AND posts.slug IS NULL;
+
+ALTER TABLE posts ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS posts_author_id_slug_key ON posts (author_id, slug);
+
+CREATE TABLE IF NOT EXISTS post_slug_history (
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(255) NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (author_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS post_slug_history_post_id_idx ON post_slug_history (post_id);
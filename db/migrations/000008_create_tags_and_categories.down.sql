@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS posts_category_id_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL CHECK (revision > 0),
+    title VARCHAR(255) NOT NULL,
+    extract TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (post_id, revision)
+);
+
+-- The current state of every existing post becomes its first revision.
+INSERT INTO post_revisions (post_id, revision, title, extract, content, created_at)
+SELECT id, 1, title, extract, content, updated_at FROM posts
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- NULL marks posts whose Markdown has not been rendered yet. The application
+-- renders them in the background on startup.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html TEXT;
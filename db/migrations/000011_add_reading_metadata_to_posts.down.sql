@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS toc;
+ALTER TABLE posts DROP COLUMN IF EXISTS reading_minutes;
+ALTER TABLE posts DROP COLUMN IF EXISTS word_count;
@@ -0,0 +1,6 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS word_count INT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reading_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS toc JSONB NOT NULL DEFAULT '[]';
+
+-- Have the application render every post again to fill the new columns in.
+UPDATE posts SET content_html = NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 10000),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    UNIQUE (post_id, id),
+    -- Replies belong to the post of the comment they answer and go away with it.
+    CONSTRAINT comments_parent_id_fkey FOREIGN KEY (post_id, parent_id) REFERENCES comments (post_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_created_at_idx ON comments (post_id, created_at);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS comments_author_id_idx ON comments (author_id);
@@ -0,0 +1,9 @@
+CREATE INDEX IF NOT EXISTS comments_author_id_idx ON comments (author_id);
+DROP INDEX IF EXISTS comments_author_id_created_at_idx;
+DROP INDEX IF EXISTS comments_status_created_at_idx;
+ALTER TABLE comments DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_reasons;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_score;
+ALTER TABLE comments DROP COLUMN IF EXISTS status;
+ALTER TABLE posts DROP COLUMN IF EXISTS comment_mode;
@@ -0,0 +1,13 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_mode VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (comment_mode IN ('open', 'moderated', 'closed'));
+
+-- Comments written before moderation existed stay visible.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved' CHECK (status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE comments ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_reasons TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS comments_status_created_at_idx ON comments (status, created_at);
+CREATE INDEX IF NOT EXISTS comments_author_id_created_at_idx ON comments (author_id, created_at);
+DROP INDEX IF EXISTS comments_author_id_idx;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS post_reactions_count_trigger ON post_reactions;
+DROP FUNCTION IF EXISTS count_post_reactions();
+DROP TABLE IF EXISTS post_reaction_counts;
+DROP TABLE IF EXISTS post_reactions;
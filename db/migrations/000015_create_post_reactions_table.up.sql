@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('like', 'love', 'insightful', 'funny')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_reactions_user_id_idx ON post_reactions (user_id);
+
+-- post_reaction_counts keeps the number of reactions of every kind on a post
+-- so listings read them without counting post_reactions.
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (post_id, kind)
+);
+
+-- The counts are maintained by a trigger rather than by the application so
+-- that reactions removed by cascades, such as when a user is deleted, are
+-- discounted too.
+CREATE OR REPLACE FUNCTION count_post_reactions() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE post_reaction_counts SET count = count - 1
+        WHERE post_id = OLD.post_id AND kind = OLD.kind;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO post_reaction_counts (post_id, kind, count) VALUES (NEW.post_id, NEW.kind, 1)
+        ON CONFLICT (post_id, kind) DO UPDATE SET count = post_reaction_counts.count + 1;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_reactions_count_trigger
+AFTER INSERT OR UPDATE OF kind OR DELETE ON post_reactions
+FOR EACH ROW EXECUTE FUNCTION count_post_reactions();
@@ -0,0 +1,6 @@
+-- Plain text of the rendered content, which search snippets are built from
+-- so they carry no Markdown.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_text TEXT;
+
+-- Have the application render every post again to fill the new column in.
+UPDATE posts SET content_html = NULL;
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type contextKey struct {
+	name string
+}
+
+func (ck contextKey) String() string {
+	return "blog context key value " + ck.name
+}
+
+var (
+	ContextKeyPrincipal = &contextKey{"principal"}
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (models.Principal, error)
+}
+
+type Authorizer interface {
+	Can(principal models.Principal, permission string) bool
+}
+
+// Middleware authenticates requests and guards routes by the permissions of
+// their caller. It is kept apart from middlewares.MiddlewareManager so that
+// pkg does not depend on internal; routes receive both.
+type Middleware struct {
+	authenticator Authenticator
+	authorizer    Authorizer
+}
+
+func NewMiddleware(authenticator Authenticator, authorizer Authorizer) *Middleware {
+	return &Middleware{authenticator: authenticator, authorizer: authorizer}
+}
+
+// PrincipalFromContext returns the caller stored by Authenticate, if any.
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	principal, ok := ctx.Value(ContextKeyPrincipal).(models.Principal)
+	return principal, ok
+}
+
+// Authenticate validates the bearer token of the request, when present, and
+// stores the caller's principal in the request context. Requests without an
+// Authorization header are passed through anonymously.
+func (m Middleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			unauthorized(w, r, "malformed Authorization header")
+			return
+		}
+
+		principal, err := m.authenticator.Authenticate(r.Context(), token)
+		if errors.Is(err, domain.ErrUnauthorized) {
+			unauthorized(w, r, "invalid or expired access token")
+			return
+		}
+		if err != nil {
+			log.Printf("[%s] authenticating request: %v", middleware.GetReqID(r.Context()), err)
+			problem.Write(w, r, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ContextKeyPrincipal, principal)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireAuth rejects requests that were not authenticated by Authenticate.
+func (m Middleware) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := PrincipalFromContext(r.Context()); !ok {
+			unauthorized(w, r, "authentication required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="blog"`)
+	problem.Write(w, r, http.StatusUnauthorized, detail)
+}
+
+// Require rejects requests whose principal does not hold permission. It
+// implies RequireAuth.
+func (m Middleware) Require(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, "authentication required")
+				return
+			}
+
+			if !m.authorizer.Can(principal, permission) {
+				problem.Write(w, r, http.StatusForbidden, "missing permission "+permission)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type authorizerFunc func(principal models.Principal, permission string) bool
+
+func (f authorizerFunc) Can(principal models.Principal, permission string) bool {
+	return f(principal, permission)
+}
+
+type authenticatorFunc func(ctx context.Context, accessToken string) (models.Principal, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, accessToken string) (models.Principal, error) {
+	return f(ctx, accessToken)
+}
+
+var alice = models.Principal{
+	UserId:   uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+	Username: "alice_s",
+}
+
+func TestMiddleware_Authenticate(t *testing.T) {
+	m := auth.NewMiddleware(authenticatorFunc(func(ctx context.Context, accessToken string) (models.Principal, error) {
+		switch accessToken {
+		case "valid":
+			return alice, nil
+		case "broken":
+			return models.Principal{}, errors.New("connection refused")
+		}
+		return models.Principal{}, domain.ErrUnauthorized
+	}), nil)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantPrincipal bool
+	}{
+		{
+			name:       "Should pass anonymous requests through",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "Should store the principal of a valid token",
+			authorization: "Bearer valid",
+			wantStatus:    http.StatusOK,
+			wantPrincipal: true,
+		},
+		{
+			name:          "Should reject an invalid token",
+			authorization: "Bearer invalid",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Should report authenticator failures as server errors",
+			authorization: "Bearer broken",
+			wantStatus:    http.StatusInternalServerError,
+		},
+		{
+			name:          "Should reject a non bearer scheme",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotPrincipal models.Principal
+				gotOk        bool
+			)
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPrincipal, gotOk = auth.PrincipalFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantPrincipal, gotOk)
+			if tt.wantPrincipal {
+				assert.Equal(t, alice, gotPrincipal)
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestMiddleware_RequireAuth(t *testing.T) {
+	m := auth.NewMiddleware(nil, nil)
+	handler := m.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyPrincipal, alice))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestMiddleware_Require(t *testing.T) {
+	m := auth.NewMiddleware(nil, authorizerFunc(func(principal models.Principal, permission string) bool {
+		return principal.Role == models.RoleAdmin && permission == "users:manage"
+	}))
+	handler := m.Require("users:manage")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		principal  *models.Principal
+		wantStatus int
+	}{
+		{
+			name:       "Should reject anonymous requests",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Should reject principals without the permission",
+			principal:  &alice,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Should accept principals with the permission",
+			principal:  &models.Principal{UserId: alice.UserId, Role: models.RoleAdmin},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.principal != nil {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyPrincipal, *tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, usernameOrEmail, password string) (models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+type authController struct {
+	authService AuthService
+}
+
+func NewAuthController(authService AuthService) *authController {
+	return &authController{authService}
+}
+
+func (c authController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
+	r := chi.NewMux()
+
+	r.Post("/login", c.Login)
+	r.Post("/refresh", c.Refresh)
+	r.Post("/logout", c.Logout)
+
+	return r
+}
+
+func (c authController) Login(w http.ResponseWriter, r *http.Request) {
+	loginPayload := dtos.Login{}
+	err := json.NewDecoder(r.Body).Decode(&loginPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pair, err := c.authService.Login(r.Context(), loginPayload.Login, loginPayload.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToTokenResponse(pair))
+}
+
+func (c authController) Refresh(w http.ResponseWriter, r *http.Request) {
+	refreshPayload := dtos.RefreshToken{}
+	err := json.NewDecoder(r.Body).Decode(&refreshPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pair, err := c.authService.Refresh(r.Context(), refreshPayload.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToTokenResponse(pair))
+}
+
+func (c authController) Logout(w http.ResponseWriter, r *http.Request) {
+	refreshPayload := dtos.RefreshToken{}
+	err := json.NewDecoder(r.Body).Decode(&refreshPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = c.authService.Logout(r.Context(), refreshPayload.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
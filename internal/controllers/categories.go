@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CategoriesService interface {
+	CreateCategory(ctx context.Context, category models.Category) (uuid.UUID, error)
+	FindAllCategories(ctx context.Context) ([]models.Category, error)
+	FindCategoryById(ctx context.Context, id uuid.UUID) (models.Category, error)
+	UpdateCategoryById(ctx context.Context, id uuid.UUID, category models.Category) error
+	DeleteCategoryById(ctx context.Context, id uuid.UUID) error
+}
+
+type categoriesController struct {
+	categoriesService CategoriesService
+}
+
+func NewCategoriesController(categoriesService CategoriesService) *categoriesController {
+	return &categoriesController{categoriesService}
+}
+
+func (c categoriesController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
+	r := chi.NewMux()
+
+	r.With(am.Require(services.PermTaxonomyManage)).Post("/", c.Create)
+	r.Get("/", c.FindAll)
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", c.FindById)
+		r.With(am.Require(services.PermTaxonomyManage)).Patch("/", c.UpdateById)
+		r.With(am.Require(services.PermTaxonomyManage)).Delete("/", c.DeleteById)
+	})
+
+	return r
+}
+
+func (c categoriesController) Create(w http.ResponseWriter, r *http.Request) {
+	categoryPayload := dtos.CreateCategory{}
+	err := json.NewDecoder(r.Body).Decode(&categoryPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := categoryPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := c.categoriesService.CreateCategory(r.Context(), categoryPayload.ToCategory())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"id": id,
+	})
+}
+
+// FindAll returns the whole category tree, subcategories nested under their
+// parents.
+func (c categoriesController) FindAll(w http.ResponseWriter, r *http.Request) {
+	categories, err := c.categoriesService.FindAllCategories(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToCategoryTree(categories))
+}
+
+func (c categoriesController) FindById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	category, err := c.categoriesService.FindCategoryById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToCategoryResponse(category))
+}
+
+func (c categoriesController) UpdateById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	categoryPayload := dtos.UpdateCategory{}
+	err = json.NewDecoder(r.Body).Decode(&categoryPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := categoryPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.categoriesService.UpdateCategoryById(r.Context(), id, categoryPayload.ToCategory())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c categoriesController) DeleteById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	err = c.categoriesService.DeleteCategoryById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CommentsService interface {
+	CreateComment(ctx context.Context, principal models.Principal, comment models.Comment) (uuid.UUID, models.CommentStatus, error)
+	FindPostComments(ctx context.Context, principal models.Principal, postId uuid.UUID) ([]models.Comment, error)
+	UpdateCommentById(ctx context.Context, principal models.Principal, postId, id uuid.UUID, comment models.Comment) error
+	DeleteCommentById(ctx context.Context, principal models.Principal, postId, id uuid.UUID) error
+	FindCommentsByStatus(ctx context.Context, principal models.Principal, status models.CommentStatus, limit, offset int) ([]models.Comment, error)
+	ApproveComment(ctx context.Context, principal models.Principal, id uuid.UUID) error
+	RejectComment(ctx context.Context, principal models.Principal, id uuid.UUID) error
+}
+
+// commentsController serves the comments of the post whose id is the {id}
+// URL parameter of the route Routes is mounted under, and the moderation
+// queue of every post under ModerationRoutes.
+type commentsController struct {
+	commentsService CommentsService
+}
+
+func NewCommentsController(commentsService CommentsService) *commentsController {
+	return &commentsController{commentsService}
+}
+
+func (c commentsController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
+	r := chi.NewMux()
+
+	r.With(am.RequireAuth).Post("/", c.Create)
+	r.Get("/", c.FindAll)
+	r.Route("/{commentId}", func(r chi.Router) {
+		r.With(am.RequireAuth).Patch("/", c.UpdateById)
+		r.With(am.RequireAuth).Delete("/", c.DeleteById)
+	})
+
+	return r
+}
+
+func (c commentsController) ModerationRoutes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
+	r := chi.NewMux()
+
+	r.Use(am.Require(services.PermCommentsModerate))
+	r.With(mm.List).Get("/", c.FindByStatus)
+	r.Post("/{commentId}/approve", c.moderate(c.commentsService.ApproveComment))
+	r.Post("/{commentId}/reject", c.moderate(c.commentsService.RejectComment))
+
+	return r
+}
+
+func (c commentsController) Create(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	commentPayload := dtos.CreateComment{}
+	err = json.NewDecoder(r.Body).Decode(&commentPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := commentPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, status, err := c.commentsService.CreateComment(r.Context(), principal, commentPayload.ToComment(postId))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":     id,
+		"status": status,
+	})
+}
+
+// FindAll returns the comment threads of the post, replies nested under the
+// comments they answer.
+func (c commentsController) FindAll(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	comments, err := c.commentsService.FindPostComments(r.Context(), principal, postId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToCommentThreads(comments))
+}
+
+func (c commentsController) UpdateById(w http.ResponseWriter, r *http.Request) {
+	postId, id, ok := commentIds(w, r)
+	if !ok {
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	commentPayload := dtos.UpdateComment{}
+	err := json.NewDecoder(r.Body).Decode(&commentPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := commentPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.commentsService.UpdateCommentById(r.Context(), principal, postId, id, commentPayload.ToComment())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c commentsController) DeleteById(w http.ResponseWriter, r *http.Request) {
+	postId, id, ok := commentIds(w, r)
+	if !ok {
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	err := c.commentsService.DeleteCommentById(r.Context(), principal, postId, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// FindByStatus lists the comments of every post in ?status=, pending by
+// default, oldest first.
+func (c commentsController) FindByStatus(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(middlewares.ContextKeyLimit).(int)
+	offset := r.Context().Value(middlewares.ContextKeyOffset).(int)
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	status := models.CommentStatusPending
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status = models.CommentStatus(statusStr)
+		if !status.IsValid() {
+			problem.Write(w, r, http.StatusBadRequest, "status must be pending, approved or rejected")
+			return
+		}
+	}
+
+	comments, err := c.commentsService.FindCommentsByStatus(r.Context(), principal, status, limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]dtos.ModerationCommentResponse, len(comments))
+	for i, comment := range comments {
+		response[i] = dtos.ToModerationCommentResponse(comment)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// moderate returns a handler moving the comment in the URL to another status
+// through action.
+func (c commentsController) moderate(action func(ctx context.Context, principal models.Principal, id uuid.UUID) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "commentId"))
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+			return
+		}
+
+		principal, _ := auth.PrincipalFromContext(r.Context())
+
+		err = action(r.Context(), principal, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// commentIds parses the ids of the post and of the comment in the URL,
+// answering with a problem when either is malformed.
+func commentIds(w http.ResponseWriter, r *http.Request) (postId, id uuid.UUID, ok bool) {
+	postId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err = uuid.Parse(chi.URLParam(r, "commentId"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid comment UUID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return postId, id, true
+}
@@ -3,13 +3,17 @@ package controllers
 import (
 	"net/http"
 
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/pkg/cursor"
 	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/gera9/blog/pkg/utils"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 )
 
-func BuildRoutes(mm *middlewares.MiddlewareManager, usersService UsersService, postsService PostsService) http.Handler {
+func BuildRoutes(mm *middlewares.MiddlewareManager, am *auth.Middleware, cursors *cursor.Signer, timeProvider utils.TimeProvider, authService AuthService, usersService UsersService, postsService PostsService, tagsService TagsService, categoriesService CategoriesService, commentsService CommentsService) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -17,10 +21,23 @@ func BuildRoutes(mm *middlewares.MiddlewareManager, usersService UsersService, p
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.URLFormat)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(am.Authenticate)
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, http.StatusNotFound, "no route matches "+r.URL.Path)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, http.StatusMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+	})
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Mount("/users", NewUsersController(usersService).Routes(mm))
-		r.Mount("/posts", NewPostsController(postsService).Routes(mm))
+		r.Mount("/auth", NewAuthController(authService).Routes(mm, am))
+		r.Mount("/users", NewUsersController(usersService, cursors, timeProvider).Routes(mm, am))
+		r.Mount("/posts", NewPostsController(postsService, cursors).Routes(mm, am))
+		r.Mount("/posts/{id}/comments", NewCommentsController(commentsService).Routes(mm, am))
+		r.Mount("/moderation/comments", NewCommentsController(commentsService).ModerationRoutes(mm, am))
+		r.Mount("/tags", NewTagsController(tagsService).Routes(mm, am))
+		r.Mount("/categories", NewCategoriesController(categoriesService).Routes(mm, am))
 	})
 
 	return r
@@ -0,0 +1,34 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+)
+
+type Login struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+func ToTokenResponse(pair models.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:           pair.AccessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             pair.AccessTokenExpiresAt,
+		RefreshToken:          pair.RefreshToken,
+		RefreshTokenExpiresAt: pair.RefreshTokenExpiresAt,
+	}
+}
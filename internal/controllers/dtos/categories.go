@@ -0,0 +1,104 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/validation"
+	"github.com/google/uuid"
+)
+
+type CreateCategory struct {
+	Name     string     `json:"name"`
+	ParentId *uuid.UUID `json:"parent_id"`
+}
+
+func (cc CreateCategory) Validate() error {
+	v := validation.New()
+
+	checkName(v, "name", cc.Name)
+
+	return validationError(v)
+}
+
+func (cc CreateCategory) ToCategory() models.Category {
+	return models.Category{Name: cc.Name, ParentId: cc.ParentId}
+}
+
+type UpdateCategory struct {
+	Name string `json:"name"`
+	// ParentId moves the category under another parent, or back to the root
+	// when null.
+	ParentId NullableId `json:"parent_id"`
+}
+
+// Validate only checks the fields present in the patch.
+func (uc UpdateCategory) Validate() error {
+	v := validation.New()
+
+	if uc.Name != "" {
+		checkName(v, "name", uc.Name)
+	}
+
+	return validationError(v)
+}
+
+func (uc UpdateCategory) ToCategory() models.Category {
+	return models.Category{Name: uc.Name, ParentId: uc.ParentId.patch()}
+}
+
+type CategoryResponse struct {
+	Id        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	Slug      string             `json:"slug"`
+	ParentId  *uuid.UUID         `json:"parent_id"`
+	Children  []CategoryResponse `json:"children,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+func ToCategoryResponse(category models.Category) CategoryResponse {
+	return CategoryResponse{
+		Id:        category.Id,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentId:  category.ParentId,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
+
+// ToCategoryTree nests categories under their parents and returns the roots.
+// Categories whose parent is not in the list are treated as roots.
+func ToCategoryTree(categories []models.Category) []CategoryResponse {
+	present := make(map[uuid.UUID]bool, len(categories))
+	children := make(map[uuid.UUID][]models.Category, len(categories))
+	for _, category := range categories {
+		present[category.Id] = true
+	}
+
+	roots := make([]models.Category, 0)
+	for _, category := range categories {
+		if category.ParentId == nil || !present[*category.ParentId] {
+			roots = append(roots, category)
+			continue
+		}
+
+		children[*category.ParentId] = append(children[*category.ParentId], category)
+	}
+
+	var build func(categories []models.Category) []CategoryResponse
+	build = func(categories []models.Category) []CategoryResponse {
+		response := make([]CategoryResponse, len(categories))
+		for i, category := range categories {
+			response[i] = ToCategoryResponse(category)
+			if len(children[category.Id]) > 0 {
+				response[i].Children = build(children[category.Id])
+			}
+		}
+
+		return response
+	}
+
+	return build(roots)
+}
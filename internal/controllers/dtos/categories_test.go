@@ -0,0 +1,39 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCategoryTree(t *testing.T) {
+	programmingId := uuid.MustParse("6f1d7b2e-0c1a-4b8e-9a53-1f2d3c4b5a60")
+	goId := uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")
+	lifeId := uuid.MustParse("d4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12")
+	orphanParentId := uuid.MustParse("0b7f6c1e-5a4d-4c3b-8a29-1e0f9d8c7b6a")
+
+	categories := []models.Category{
+		{Id: lifeId, Name: "Life", Slug: "life"},
+		{Id: programmingId, Name: "Programming", Slug: "programming"},
+		{Id: goId, Name: "Go", Slug: "go", ParentId: &programmingId},
+		{Id: uuid.MustParse("3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b"), Name: "Orphan", Slug: "orphan", ParentId: &orphanParentId},
+	}
+
+	tree := dtos.ToCategoryTree(categories)
+
+	if assert.Len(t, tree, 3) {
+		assert.Equal(t, "life", tree[0].Slug)
+		assert.Empty(t, tree[0].Children)
+
+		assert.Equal(t, "programming", tree[1].Slug)
+		if assert.Len(t, tree[1].Children, 1) {
+			assert.Equal(t, "go", tree[1].Children[0].Slug)
+			assert.Equal(t, &programmingId, tree[1].Children[0].ParentId)
+		}
+
+		assert.Equal(t, "orphan", tree[2].Slug)
+	}
+}
@@ -0,0 +1,139 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/validation"
+	"github.com/google/uuid"
+)
+
+type CreateComment struct {
+	Body string `json:"body"`
+	// ParentId is the comment replied to, if any.
+	ParentId *uuid.UUID `json:"parent_id"`
+}
+
+func (cc CreateComment) Validate() error {
+	v := validation.New()
+
+	checkCommentBody(v, cc.Body)
+
+	return validationError(v)
+}
+
+func (cc CreateComment) ToComment(postId uuid.UUID) models.Comment {
+	return models.Comment{PostId: postId, ParentId: cc.ParentId, Body: cc.Body}
+}
+
+type UpdateComment struct {
+	Body string `json:"body"`
+}
+
+func (uc UpdateComment) Validate() error {
+	v := validation.New()
+
+	checkCommentBody(v, uc.Body)
+
+	return validationError(v)
+}
+
+func (uc UpdateComment) ToComment() models.Comment {
+	return models.Comment{Body: uc.Body}
+}
+
+type CommentResponse struct {
+	Id        uuid.UUID         `json:"id"`
+	PostId    uuid.UUID         `json:"post_id"`
+	AuthorId  uuid.UUID         `json:"author_id"`
+	ParentId  *uuid.UUID        `json:"parent_id"`
+	Body      string            `json:"body"`
+	Status    string            `json:"status"`
+	Depth     int               `json:"depth"`
+	Replies   []CommentResponse `json:"replies"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func ToCommentResponse(comment models.Comment) CommentResponse {
+	return CommentResponse{
+		Id:        comment.Id,
+		PostId:    comment.PostId,
+		AuthorId:  comment.AuthorId,
+		ParentId:  comment.ParentId,
+		Body:      comment.Body,
+		Status:    string(comment.Status),
+		Depth:     comment.Depth,
+		Replies:   []CommentResponse{},
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+// ToCommentThreads nests comments under the comments they reply to and
+// returns the comments starting a thread, keeping the order of comments
+// among siblings. Replies whose parent is not in the list start a thread.
+func ToCommentThreads(comments []models.Comment) []CommentResponse {
+	present := make(map[uuid.UUID]bool, len(comments))
+	replies := make(map[uuid.UUID][]models.Comment, len(comments))
+	for _, comment := range comments {
+		present[comment.Id] = true
+	}
+
+	threads := make([]models.Comment, 0)
+	for _, comment := range comments {
+		if comment.ParentId == nil || !present[*comment.ParentId] {
+			threads = append(threads, comment)
+			continue
+		}
+
+		replies[*comment.ParentId] = append(replies[*comment.ParentId], comment)
+	}
+
+	var build func(comments []models.Comment) []CommentResponse
+	build = func(comments []models.Comment) []CommentResponse {
+		response := make([]CommentResponse, len(comments))
+		for i, comment := range comments {
+			response[i] = ToCommentResponse(comment)
+			response[i].Replies = build(replies[comment.Id])
+		}
+
+		return response
+	}
+
+	return build(threads)
+}
+
+// ModerationCommentResponse is a comment as moderators see it, along with
+// the outcome of its spam scoring.
+type ModerationCommentResponse struct {
+	Id          uuid.UUID  `json:"id"`
+	PostId      uuid.UUID  `json:"post_id"`
+	AuthorId    uuid.UUID  `json:"author_id"`
+	ParentId    *uuid.UUID `json:"parent_id"`
+	Body        string     `json:"body"`
+	Status      string     `json:"status"`
+	SpamScore   float64    `json:"spam_score"`
+	SpamReasons []string   `json:"spam_reasons"`
+	ModeratedBy *uuid.UUID `json:"moderated_by"`
+	ModeratedAt *time.Time `json:"moderated_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func ToModerationCommentResponse(comment models.Comment) ModerationCommentResponse {
+	return ModerationCommentResponse{
+		Id:          comment.Id,
+		PostId:      comment.PostId,
+		AuthorId:    comment.AuthorId,
+		ParentId:    comment.ParentId,
+		Body:        comment.Body,
+		Status:      string(comment.Status),
+		SpamScore:   comment.SpamScore,
+		SpamReasons: comment.SpamReasons,
+		ModeratedBy: comment.ModeratedBy,
+		ModeratedAt: comment.ModeratedAt,
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
+	}
+}
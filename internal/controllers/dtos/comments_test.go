@@ -0,0 +1,39 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCommentThreads(t *testing.T) {
+	firstId := uuid.MustParse("c0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a70")
+	replyId := uuid.MustParse("c1b2c3d4-e5f6-4a70-9b2c-3d4e5f6a7b81")
+	secondId := uuid.MustParse("7d6c5b4a-3f2e-4d1c-9b0a-8f7e6d5c4b3a")
+
+	comments := []models.Comment{
+		{Id: firstId, Body: "First"},
+		{Id: secondId, Body: "Second"},
+		{Id: replyId, ParentId: &firstId, Body: "Reply", Depth: 1},
+		{Id: uuid.MustParse("5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"), ParentId: &replyId, Body: "Nested", Depth: 2},
+	}
+
+	threads := dtos.ToCommentThreads(comments)
+
+	if assert.Len(t, threads, 2) {
+		assert.Equal(t, "First", threads[0].Body)
+		if assert.Len(t, threads[0].Replies, 1) {
+			assert.Equal(t, "Reply", threads[0].Replies[0].Body)
+			assert.Equal(t, 1, threads[0].Replies[0].Depth)
+			if assert.Len(t, threads[0].Replies[0].Replies, 1) {
+				assert.Equal(t, "Nested", threads[0].Replies[0].Replies[0].Body)
+			}
+		}
+
+		assert.Equal(t, "Second", threads[1].Body)
+		assert.Equal(t, []dtos.CommentResponse{}, threads[1].Replies)
+	}
+}
@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// NullableId is an id in a patch that tells apart being left out, which keeps
+// the current id, from being null, which clears it.
+type NullableId struct {
+	Present bool
+	Id      *uuid.UUID
+}
+
+func (n *NullableId) UnmarshalJSON(data []byte) error {
+	n.Present = true
+	if string(data) == "null" {
+		n.Id = nil
+		return nil
+	}
+
+	return json.Unmarshal(data, &n.Id)
+}
+
+// patch returns the id the way services take it in patches: nil to keep the
+// current one and uuid.Nil to clear it.
+func (n NullableId) patch() *uuid.UUID {
+	if !n.Present {
+		return nil
+	}
+
+	if n.Id == nil {
+		return &uuid.Nil
+	}
+
+	return n.Id
+}
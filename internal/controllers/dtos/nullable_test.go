@@ -0,0 +1,39 @@
+package dtos_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableIdPatches(t *testing.T) {
+	id := uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")
+
+	tests := []struct {
+		name string
+		body string
+		want *uuid.UUID
+	}{
+		{name: "Should keep the id when left out", body: `{}`, want: nil},
+		{name: "Should clear the id when null", body: `{"parent_id": null, "category_id": null}`, want: &uuid.Nil},
+		{name: "Should set the id when given", body: `{"parent_id": "` + id.String() + `", "category_id": "` + id.String() + `"}`, want: &id},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var category dtos.UpdateCategory
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &category))
+			assert.Equal(t, tt.want, category.ToCategory().ParentId)
+
+			var post dtos.UpdatePost
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &post))
+			assert.Equal(t, tt.want, post.ToPost().CategoryId)
+		})
+	}
+
+	var category dtos.UpdateCategory
+	assert.Error(t, json.Unmarshal([]byte(`{"parent_id": "nope"}`), &category))
+}
@@ -0,0 +1,22 @@
+package dtos
+
+// Page is the envelope of paginated listings.
+type Page[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination describes a page. Offset is set for pages read by offset and
+// Cursor, the token the page was read with, for the others. Total is only
+// counted for the former.
+type Pagination struct {
+	Limit  int     `json:"limit"`
+	Offset *int    `json:"offset,omitempty"`
+	Cursor *string `json:"cursor,omitempty"`
+	Total  *int    `json:"total,omitempty"`
+	// Next and Prev are the cursors of the neighbouring pages, to be passed
+	// back as ?cursor=, and are null at the ends of the listing. Sorted
+	// listings are paged by offset and have none.
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
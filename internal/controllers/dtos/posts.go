@@ -4,45 +4,161 @@ import (
 	"time"
 
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/slug"
+	"github.com/gera9/blog/pkg/validation"
 	"github.com/google/uuid"
 )
 
 type CreatePost struct {
-	Title    string    `json:"title"`
-	Extract  string    `json:"extract"`
-	Content  string    `json:"content"`
-	AuthorId uuid.UUID `json:"author_id"`
+	Title string `json:"title"`
+	// Extract is generated from the content when omitted.
+	Extract    string     `json:"extract"`
+	Content    string     `json:"content"`
+	CategoryId *uuid.UUID `json:"category_id"`
+	Tags       []string   `json:"tags"`
+	// CommentMode defaults to open.
+	CommentMode string `json:"comment_mode"`
 }
 
-func (cp CreatePost) ToPost() models.Post {
+func (cp CreatePost) Validate() error {
+	v := validation.New()
+
+	checkTitle(v, cp.Title)
+	v.Check(validation.NotBlank(cp.Content), "content", "must not be blank")
+	checkTags(v, cp.Tags)
+	if cp.CommentMode != "" {
+		checkCommentMode(v, cp.CommentMode)
+	}
+
+	return validationError(v)
+}
+
+func (cp CreatePost) ToPost(authorId uuid.UUID) models.Post {
 	return models.Post{
-		Title:    cp.Title,
-		Extract:  cp.Extract,
-		Content:  cp.Content,
-		AuthorId: cp.AuthorId,
+		Title:       cp.Title,
+		Extract:     cp.Extract,
+		Content:     cp.Content,
+		AuthorId:    authorId,
+		CategoryId:  cp.CategoryId,
+		Tags:        cp.Tags,
+		CommentMode: models.CommentMode(cp.CommentMode),
 	}
 }
 
 type UpdatePost struct {
 	Title   string `json:"title"`
+	Slug    string `json:"slug"`
 	Extract string `json:"extract"`
 	Content string `json:"content"`
+	// CategoryId moves the post to another category, or out of any when
+	// null.
+	CategoryId NullableId `json:"category_id"`
+	// Tags replaces the tags of the post when present, an empty list
+	// removing them all.
+	Tags        []string `json:"tags"`
+	CommentMode string   `json:"comment_mode"`
+}
+
+// Validate only checks the fields present in the patch.
+func (up UpdatePost) Validate() error {
+	v := validation.New()
+
+	if up.Title != "" {
+		checkTitle(v, up.Title)
+	}
+	if up.Slug != "" {
+		v.Check(slug.IsValid(up.Slug), "slug", "must be lowercase words and digits separated by single hyphens")
+	}
+	if up.Content != "" {
+		v.Check(validation.NotBlank(up.Content), "content", "must not be blank")
+	}
+	checkTags(v, up.Tags)
+	if up.CommentMode != "" {
+		checkCommentMode(v, up.CommentMode)
+	}
+
+	return validationError(v)
 }
 
 func (up UpdatePost) ToPost() models.Post {
 	return models.Post{
-		Title:   up.Title,
-		Extract: up.Extract,
-		Content: up.Content,
+		Title:       up.Title,
+		Slug:        up.Slug,
+		Extract:     up.Extract,
+		Content:     up.Content,
+		CategoryId:  up.CategoryId.patch(),
+		Tags:        up.Tags,
+		CommentMode: models.CommentMode(up.CommentMode),
 	}
 }
 
+type SchedulePost struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func (sp SchedulePost) Validate() error {
+	v := validation.New()
+
+	v.Check(!sp.ScheduledAt.IsZero(), "scheduled_at", "is required")
+
+	return validationError(v)
+}
+
 type PostResponse struct {
-	Id        uuid.UUID `json:"id"`
+	Id             uuid.UUID          `json:"id"`
+	Title          string             `json:"title"`
+	Slug           string             `json:"slug"`
+	Extract        string             `json:"extract"`
+	Content        string             `json:"content"`
+	ContentHTML    string             `json:"content_html"`
+	WordCount      int                `json:"word_count"`
+	ReadingMinutes int                `json:"reading_time_minutes"`
+	Toc            []TocEntryResponse `json:"toc"`
+	AuthorId       uuid.UUID          `json:"author_id"`
+	// Author is only embedded when asked for with ?expand=author.
+	Author      *PublicUserResponse `json:"author,omitempty"`
+	CategoryId  *uuid.UUID          `json:"category_id"`
+	Tags        []string            `json:"tags"`
+	CommentMode string              `json:"comment_mode"`
+	Reactions   map[string]int      `json:"reactions"`
+	Status      string              `json:"status"`
+	PublishedAt *time.Time          `json:"published_at"`
+	ScheduledAt *time.Time          `json:"scheduled_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type TocEntryResponse struct {
+	Level int    `json:"level"`
+	Id    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+type PostRevisionResponse struct {
+	PostId    uuid.UUID `json:"post_id"`
+	Revision  int       `json:"revision"`
 	Title     string    `json:"title"`
 	Extract   string    `json:"extract"`
 	Content   string    `json:"content"`
-	AuthorId  uuid.UUID `json:"author_id"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToPostRevisionResponse(revision models.PostRevision) PostRevisionResponse {
+	return PostRevisionResponse{
+		PostId:    revision.PostId,
+		Revision:  revision.Revision,
+		Title:     revision.Title,
+		Extract:   revision.Extract,
+		Content:   revision.Content,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+// PostSearchResultResponse is a post found by a search, along with an HTML
+// snippet highlighting the matches with <mark> elements.
+type PostSearchResultResponse struct {
+	PostResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+	Match   string  `json:"match"`
 }
@@ -0,0 +1,64 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/validation"
+	"github.com/google/uuid"
+)
+
+type CreateTag struct {
+	Name string `json:"name"`
+}
+
+func (ct CreateTag) Validate() error {
+	v := validation.New()
+
+	checkTagName(v, ct.Name)
+
+	return validationError(v)
+}
+
+func (ct CreateTag) ToTag() models.Tag {
+	return models.Tag{Name: ct.Name}
+}
+
+type UpdateTag struct {
+	Name string `json:"name"`
+}
+
+// Validate only checks the fields present in the patch.
+func (ut UpdateTag) Validate() error {
+	v := validation.New()
+
+	if ut.Name != "" {
+		checkTagName(v, ut.Name)
+	}
+
+	return validationError(v)
+}
+
+func (ut UpdateTag) ToTag() models.Tag {
+	return models.Tag{Name: ut.Name}
+}
+
+type TagResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	PostCount int       `json:"post_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToTagResponse(tag models.Tag) TagResponse {
+	return TagResponse{
+		Id:        tag.Id,
+		Name:      tag.Name,
+		Slug:      tag.Slug,
+		PostCount: tag.PostCount,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/validation"
 	"github.com/google/uuid"
 )
 
@@ -16,14 +17,29 @@ type CreateUser struct {
 	BirthDate time.Time `json:"birth_date"`
 }
 
+// Validate checks the user as of now, which the birth date must not be after.
+func (cu CreateUser) Validate(now time.Time) error {
+	v := validation.New()
+
+	checkName(v, "first_name", cu.FirstName)
+	checkName(v, "last_name", cu.LastName)
+	checkEmail(v, cu.Email)
+	checkUsername(v, cu.Username)
+	checkPassword(v, cu.Password)
+	v.Check(!cu.BirthDate.IsZero(), "birth_date", "is required")
+	v.Check(validation.NotAfter(cu.BirthDate, now), "birth_date", "must not be in the future")
+
+	return validationError(v)
+}
+
 func (cu CreateUser) ToUser() models.User {
 	return models.User{
-		FirstName:      cu.FirstName,
-		LastName:       cu.LastName,
-		Email:          cu.Email,
-		Username:       cu.Username,
-		HashedPassword: cu.Password,
-		BirthDate:      cu.BirthDate,
+		FirstName: cu.FirstName,
+		LastName:  cu.LastName,
+		Email:     cu.Email,
+		Username:  cu.Username,
+		Password:  cu.Password,
+		BirthDate: cu.BirthDate,
 	}
 }
 
@@ -35,24 +51,78 @@ type UpdateUser struct {
 	Password  string `json:"password"`
 }
 
+// Validate only checks the fields present in the patch.
+func (uu UpdateUser) Validate() error {
+	v := validation.New()
+
+	if uu.FirstName != "" {
+		checkName(v, "first_name", uu.FirstName)
+	}
+	if uu.LastName != "" {
+		checkName(v, "last_name", uu.LastName)
+	}
+	if uu.Email != "" {
+		checkEmail(v, uu.Email)
+	}
+	if uu.Username != "" {
+		checkUsername(v, uu.Username)
+	}
+	if uu.Password != "" {
+		checkPassword(v, uu.Password)
+	}
+
+	return validationError(v)
+}
+
 func (uu UpdateUser) ToUser() models.User {
 	return models.User{
-		FirstName:      uu.FirstName,
-		LastName:       uu.LastName,
-		Email:          uu.Email,
-		Username:       uu.Username,
-		HashedPassword: uu.Password,
-	}
-}
-
-type UserResponse struct {
-	Id             uuid.UUID `json:"id"`
-	FirstName      string    `json:"first_name"`
-	LastName       string    `json:"last_name"`
-	Email          string    `json:"email"`
-	Username       string    `json:"username"`
-	HashedPassword string    `json:"hashed_password"`
-	BirthDate      time.Time `json:"birth_date"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+		FirstName: uu.FirstName,
+		LastName:  uu.LastName,
+		Email:     uu.Email,
+		Username:  uu.Username,
+		Password:  uu.Password,
+	}
+}
+
+type UpdateUserRole struct {
+	Role string `json:"role"`
+}
+
+// PublicUserResponse is the profile anyone may see.
+type PublicUserResponse struct {
+	Id        uuid.UUID `json:"id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PrivateUserResponse adds the personal fields only the user and admins may
+// see. Credentials are never part of any response.
+type PrivateUserResponse struct {
+	PublicUserResponse
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	BirthDate time.Time `json:"birth_date"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToPublicUserResponse(user models.User) PublicUserResponse {
+	return PublicUserResponse{
+		Id:        user.Id,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func ToPrivateUserResponse(user models.User) PrivateUserResponse {
+	return PrivateUserResponse{
+		PublicUserResponse: ToPublicUserResponse(user),
+		Email:              user.Email,
+		Role:               string(user.Role),
+		BirthDate:          user.BirthDate,
+		UpdatedAt:          user.UpdatedAt,
+	}
 }
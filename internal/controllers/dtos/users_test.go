@@ -0,0 +1,63 @@
+package dtos_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserResponses(t *testing.T) {
+	user := models.User{
+		Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		FirstName:      "Alice",
+		LastName:       "Smith",
+		Email:          "alice@example.com",
+		Username:       "alice_s",
+		HashedPassword: "$2a$12$secret",
+		Password:       "plaintext",
+		Role:           models.RoleAuthor,
+		BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
+		CreatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		response any
+		want     []string
+	}{
+		{
+			name:     "Should only expose the public profile",
+			response: dtos.ToPublicUserResponse(user),
+			want:     []string{"id", "first_name", "last_name", "username", "created_at"},
+		},
+		{
+			name:     "Should add the personal fields to the private view",
+			response: dtos.ToPrivateUserResponse(user),
+			want:     []string{"id", "first_name", "last_name", "username", "created_at", "email", "role", "birth_date", "updated_at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.response)
+			require.NoError(t, err)
+
+			fields := map[string]any{}
+			require.NoError(t, json.Unmarshal(body, &fields))
+
+			got := make([]string, 0, len(fields))
+			for field := range fields {
+				got = append(got, field)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+			assert.NotContains(t, string(body), user.HashedPassword)
+			assert.NotContains(t, string(body), user.Password)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package dtos
+
+import (
+	"regexp"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/slug"
+	"github.com/gera9/blog/pkg/validation"
+)
+
+// Limits mirroring the column sizes in db/migrations.
+const (
+	maxNameChars     = 100
+	maxEmailChars    = 255
+	maxUsernameChars = 100
+	maxTitleChars    = 255
+	maxTagNameChars  = 50
+	maxCommentChars  = 10000
+)
+
+const (
+	minPasswordChars = 8
+	// bcrypt ignores everything past the first 72 bytes.
+	maxPasswordBytes = 72
+)
+
+var usernameRx = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func validationError(v *validation.Validator) error {
+	if v.Valid() {
+		return nil
+	}
+
+	return domain.FieldsErrorf(domain.ErrValidation, v.Errors, "request body has invalid fields")
+}
+
+func checkName(v *validation.Validator, field, name string) {
+	v.Check(validation.NotBlank(name), field, "must not be blank")
+	v.Check(validation.MaxChars(name, maxNameChars), field, "must be at most 100 characters")
+}
+
+func checkEmail(v *validation.Validator, email string) {
+	v.Check(validation.MaxChars(email, maxEmailChars), "email", "must be at most 255 characters")
+	v.Check(validation.Email(email), "email", "must be a valid email address")
+}
+
+func checkUsername(v *validation.Validator, username string) {
+	v.Check(validation.MaxChars(username, maxUsernameChars), "username", "must be at most 100 characters")
+	v.Check(validation.Matches(username, usernameRx), "username", "may only contain letters, digits, '.', '_' and '-'")
+}
+
+func checkPassword(v *validation.Validator, password string) {
+	v.Check(validation.MinChars(password, minPasswordChars), "password", "must be at least 8 characters")
+	v.Check(validation.MaxBytes(password, maxPasswordBytes), "password", "must be at most 72 bytes")
+}
+
+func checkTitle(v *validation.Validator, title string) {
+	v.Check(validation.NotBlank(title), "title", "must not be blank")
+	v.Check(validation.MaxChars(title, maxTitleChars), "title", "must be at most 255 characters")
+}
+
+// checkTags checks that tags only holds tag slugs, which posts refer to tags
+// by.
+func checkTags(v *validation.Validator, tags []string) {
+	for _, tag := range tags {
+		v.Check(slug.IsValid(tag) && validation.MaxChars(tag, maxTagNameChars), "tags", "must only contain tag slugs")
+	}
+}
+
+func checkTagName(v *validation.Validator, name string) {
+	v.Check(validation.NotBlank(name), "name", "must not be blank")
+	v.Check(validation.MaxChars(name, maxTagNameChars), "name", "must be at most 50 characters")
+}
+
+func checkCommentBody(v *validation.Validator, body string) {
+	v.Check(validation.NotBlank(body), "body", "must not be blank")
+	v.Check(validation.MaxChars(body, maxCommentChars), "body", "must be at most 10000 characters")
+}
+
+func checkCommentMode(v *validation.Validator, mode string) {
+	v.Check(models.CommentMode(mode).IsValid(), "comment_mode", "must be open, moderated or closed")
+}
@@ -0,0 +1,116 @@
+package dtos_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUserValidate(t *testing.T) {
+	now := utils.MockClock{}.Now()
+	valid := dtos.CreateUser{
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Email:     "jane.doe@example.com",
+		Username:  "jdoe_90",
+		Password:  "correct horse",
+		BirthDate: time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		user   func(u dtos.CreateUser) dtos.CreateUser
+		fields map[string]string
+	}{
+		{
+			name: "Should accept a valid user",
+			user: func(u dtos.CreateUser) dtos.CreateUser { return u },
+		},
+		{
+			name: "Should report every invalid field at once",
+			user: func(u dtos.CreateUser) dtos.CreateUser {
+				u.FirstName = " "
+				u.Email = "jane"
+				u.Username = strings.Repeat("j", 101)
+				u.Password = "short"
+				u.BirthDate = now.Add(24 * time.Hour)
+				return u
+			},
+			fields: map[string]string{
+				"first_name": "must not be blank",
+				"email":      "must be a valid email address",
+				"username":   "must be at most 100 characters",
+				"password":   "must be at least 8 characters",
+				"birth_date": "must not be in the future",
+			},
+		},
+		{
+			name: "Should require a birth date",
+			user: func(u dtos.CreateUser) dtos.CreateUser {
+				u.BirthDate = time.Time{}
+				return u
+			},
+			fields: map[string]string{"birth_date": "is required"},
+		},
+		{
+			name: "Should accept a birth date of today",
+			user: func(u dtos.CreateUser) dtos.CreateUser {
+				u.BirthDate = now
+				return u
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.user(valid).Validate(now)
+			if tt.fields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, domain.ErrValidation)
+			assert.Equal(t, tt.fields, domain.Fields(err))
+		})
+	}
+}
+
+func TestUpdateUserValidate(t *testing.T) {
+	assert.NoError(t, dtos.UpdateUser{}.Validate())
+
+	err := dtos.UpdateUser{Username: "jane doe", Password: strings.Repeat("ñ", 40)}.Validate()
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{
+		"username": "may only contain letters, digits, '.', '_' and '-'",
+		"password": "must be at most 72 bytes",
+	}, domain.Fields(err))
+}
+
+func TestCreatePostValidate(t *testing.T) {
+	assert.NoError(t, dtos.CreatePost{Title: "Hello", Content: "World"}.Validate())
+
+	err := dtos.CreatePost{Title: strings.Repeat("t", 256)}.Validate()
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{
+		"title":   "must be at most 255 characters",
+		"content": "must not be blank",
+	}, domain.Fields(err))
+}
+
+func TestUpdatePostValidate(t *testing.T) {
+	assert.NoError(t, dtos.UpdatePost{Extract: "Only the extract"}.Validate())
+
+	assert.NoError(t, dtos.UpdatePost{Slug: "crème-2"}.Validate())
+
+	err := dtos.UpdatePost{Title: "   ", Slug: "Not A Slug", Content: "\n"}.Validate()
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{
+		"title":   "must not be blank",
+		"slug":    "must be lowercase words and digits separated by single hyphens",
+		"content": "must not be blank",
+	}, domain.Fields(err))
+}
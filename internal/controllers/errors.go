@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// writeError reports err as a problem with the HTTP status of its domain
+// kind. Errors of an unknown kind are logged and reported as a bare 500 so
+// that no internal detail reaches the client.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+
+	if status == http.StatusInternalServerError {
+		log.Printf("[%s] %s %s: %v", middleware.GetReqID(r.Context()), r.Method, r.URL.Path, err)
+		problem.Write(w, r, status, http.StatusText(status))
+		return
+	}
+
+	p := problem.New(r, status, domain.Message(err))
+	p.Errors = domain.Fields(err)
+	p.Write(w)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+)
+
+// etag returns the strong entity tag of a response body, a hash of its
+// bytes. It changes whenever anything the client sees does, including
+// fields such as reaction counts that leave updated_at untouched.
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagListed reports whether header, a comma separated list of entity tags,
+// holds tag or is *. Weak tags only match under the weak comparison of
+// If-None-Match.
+func etagListed(header, tag string, weak bool) bool {
+	for _, listed := range strings.Split(header, ",") {
+		listed = strings.TrimSpace(listed)
+		if weak {
+			listed = strings.TrimPrefix(listed, "W/")
+		}
+
+		if listed == "*" || listed == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeTagged responds with response along with its ETag, or with a bare
+// 304 Not Modified when If-None-Match shows the client already holds it. As
+// responses may depend on the caller, they vary with Authorization, so that
+// shared caches do not hand them to someone else.
+func writeTagged(w http.ResponseWriter, r *http.Request, response any) {
+	body, _ := json.Marshal(response)
+	tag := etag(body)
+
+	w.Header().Set("ETag", tag)
+	w.Header().Add("Vary", "Authorization")
+	if etagListed(r.Header.Get("If-None-Match"), tag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(append(body, '\n'))
+}
+
+// ifMatchVersion evaluates the If-Match header of r, if any, against the
+// current response for a resource and its update time, both returned by
+// find. It returns that update time for the write to be conditioned on, so
+// that the resource cannot change between the check and the write, or
+// domain.ErrPreconditionFailed when the client holds a stale version.
+// Without If-Match the write is unconditional and the version nil.
+func ifMatchVersion(r *http.Request, find func() (response any, updatedAt time.Time, err error)) (*time.Time, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil, nil
+	}
+
+	response, updatedAt, err := find()
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := json.Marshal(response)
+	if !etagListed(header, etag(body), false) {
+		return nil, domain.Errorf(domain.ErrPreconditionFailed, "resource was modified in the meantime")
+	}
+
+	return &updatedAt, nil
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeTagged(t *testing.T) {
+	response := map[string]string{"title": "My First Post"}
+
+	rec := httptest.NewRecorder()
+	writeTagged(rec, httptest.NewRequest(http.MethodGet, "/", nil), response)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"title":"My First Post"}`, rec.Body.String())
+	tag := rec.Header().Get("ETag")
+	require.Regexp(t, `^"[0-9a-f]{32}"$`, tag)
+	assert.Equal(t, "Authorization", rec.Header().Get("Vary"))
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "Should not modify a held tag", ifNoneMatch: tag, wantStatus: http.StatusNotModified},
+		{name: "Should compare weakly", ifNoneMatch: `"other", W/` + tag, wantStatus: http.StatusNotModified},
+		{name: "Should match any tag with a star", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "Should respond to other tags", ifNoneMatch: `"other"`, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			rec := httptest.NewRecorder()
+
+			writeTagged(rec, req, response)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tag, rec.Header().Get("ETag"))
+			assert.Equal(t, "Authorization", rec.Header().Get("Vary"))
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_ifMatchVersion(t *testing.T) {
+	updatedAt := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	response := map[string]string{"title": "My First Post"}
+
+	rec := httptest.NewRecorder()
+	writeTagged(rec, httptest.NewRequest(http.MethodGet, "/", nil), response)
+	tag := rec.Header().Get("ETag")
+
+	find := func() (any, time.Time, error) { return response, updatedAt, nil }
+	lost := errors.New("connection lost")
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		find        func() (any, time.Time, error)
+		wantVersion *time.Time
+		wantErr     error
+	}{
+		{name: "Should leave writes without If-Match unconditional", find: find},
+		{name: "Should return the version of a matching tag", ifMatch: `"other", ` + tag, find: find, wantVersion: &updatedAt},
+		{name: "Should match any tag with a star", ifMatch: "*", find: find, wantVersion: &updatedAt},
+		{name: "Should compare strongly", ifMatch: "W/" + tag, find: find, wantErr: domain.ErrPreconditionFailed},
+		{name: "Should reject stale tags", ifMatch: `"other"`, find: find, wantErr: domain.ErrPreconditionFailed},
+		{
+			name:    "Should report failures to find the resource",
+			ifMatch: tag,
+			find:    func() (any, time.Time, error) { return nil, time.Time{}, lost },
+			wantErr: lost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, err := ifMatchVersion(req, tt.find)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/cursor"
+	"github.com/gera9/blog/pkg/middlewares"
+)
+
+// pageFromRequest returns the page of a listing asked for by a request that
+// went through mm.KeysetList.
+func pageFromRequest(r *http.Request) models.Page {
+	page := models.Page{
+		Limit:  r.Context().Value(middlewares.ContextKeyLimit).(int),
+		Offset: r.Context().Value(middlewares.ContextKeyOffset).(int),
+	}
+
+	page.Sort = middlewares.QueryFromContext(r.Context()).Sort
+
+	if c, ok := middlewares.CursorFromContext(r.Context()); ok {
+		keyset := &models.Keyset{CreatedAt: c.CreatedAt, Id: c.Id}
+		if c.Backward {
+			page.Before = keyset
+		} else {
+			page.After = keyset
+		}
+	}
+
+	return page
+}
+
+// writePage responds with page of a keyset listing in the dtos.Page envelope,
+// along with Link headers (RFC 8288) to the first, previous and next pages.
+// keyset locates a row in the listing and toResponse renders it. Sorted
+// listings only follow the default order through cursors, so their links
+// move by offset instead.
+func writePage[T, R any](w http.ResponseWriter, r *http.Request, cursors *cursor.Signer, page models.Page, info models.PageInfo, rows []T, keyset func(T) models.Keyset, toResponse func(T) R) {
+	response := dtos.Page[R]{
+		Data:       make([]R, len(rows)),
+		Pagination: dtos.Pagination{Limit: page.Limit, Total: info.Total},
+	}
+	for i, row := range rows {
+		response.Data[i] = toResponse(row)
+	}
+
+	if page.After != nil || page.Before != nil {
+		current := r.URL.Query().Get("cursor")
+		response.Pagination.Cursor = &current
+	} else {
+		response.Pagination.Offset = &page.Offset
+	}
+
+	links := []string{pageLink(r, "first", "", "")}
+
+	if len(page.Sort) > 0 {
+		if info.HasPrev {
+			links = append(links, pageLink(r, "prev", "offset", strconv.Itoa(max(page.Offset-page.Limit, 0))))
+		}
+		if info.HasNext {
+			links = append(links, pageLink(r, "next", "offset", strconv.Itoa(page.Offset+page.Limit)))
+		}
+	} else if info.HasPrev && len(rows) > 0 {
+		first := keyset(rows[0])
+		prev := cursors.Encode(cursor.Cursor{CreatedAt: first.CreatedAt, Id: first.Id, Backward: true})
+		response.Pagination.Prev = &prev
+		links = append(links, pageLink(r, "prev", "cursor", prev))
+	}
+
+	if len(page.Sort) == 0 && info.HasNext && len(rows) > 0 {
+		last := keyset(rows[len(rows)-1])
+		next := cursors.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, Id: last.Id})
+		response.Pagination.Next = &next
+		links = append(links, pageLink(r, "next", "cursor", next))
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// pageLink is a link to the page of the listing requested by r found at the
+// given cursor or offset, named by param, or to the first page when param
+// is empty. The other query parameters, such as filters and the limit, are
+// kept.
+func pageLink(r *http.Request, rel, param, value string) string {
+	q := r.URL.Query()
+	q.Del("offset")
+	q.Del("cursor")
+	if param != "" {
+		q.Set(param, value)
+	}
+
+	target := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+
+	return `<` + target.String() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/cursor"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/query"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writePage(t *testing.T) {
+	cursors := cursor.NewSigner([]byte("secret"))
+	createdAt := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	rows := []models.Keyset{
+		{CreatedAt: createdAt, Id: uuid.New()},
+		{CreatedAt: createdAt, Id: uuid.New()},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?limit=2&offset=2&tag=go", nil)
+	ctx := context.WithValue(req.Context(), middlewares.ContextKeyLimit, 2)
+	ctx = context.WithValue(ctx, middlewares.ContextKeyOffset, 2)
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	page := pageFromRequest(req)
+	info := models.PageInfo{HasNext: true, HasPrev: true, Total: utils.Ptr(7)}
+	writePage(rec, req, cursors, page, info, rows, func(k models.Keyset) models.Keyset { return k }, func(k models.Keyset) uuid.UUID { return k.Id })
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var got dtos.Page[uuid.UUID]
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, []uuid.UUID{rows[0].Id, rows[1].Id}, got.Data)
+	assert.Equal(t, 2, got.Pagination.Limit)
+	assert.Equal(t, utils.Ptr(2), got.Pagination.Offset)
+	assert.Equal(t, utils.Ptr(7), got.Pagination.Total)
+	assert.Nil(t, got.Pagination.Cursor)
+	require.NotNil(t, got.Pagination.Next)
+	require.NotNil(t, got.Pagination.Prev)
+
+	next, err := cursors.Decode(*got.Pagination.Next)
+	require.NoError(t, err)
+	assert.Equal(t, cursor.Cursor{CreatedAt: createdAt, Id: rows[1].Id}, next)
+
+	prev, err := cursors.Decode(*got.Pagination.Prev)
+	require.NoError(t, err)
+	assert.Equal(t, cursor.Cursor{CreatedAt: createdAt, Id: rows[0].Id, Backward: true}, prev)
+
+	link := func(rel, token string) string {
+		q := url.Values{"limit": {"2"}, "tag": {"go"}}
+		if token != "" {
+			q.Set("cursor", token)
+		}
+		return `</api/v1/posts?` + q.Encode() + `>; rel="` + rel + `"`
+	}
+	assert.Equal(t,
+		link("first", "")+", "+link("prev", *got.Pagination.Prev)+", "+link("next", *got.Pagination.Next),
+		rec.Header().Get("Link"),
+	)
+}
+
+func Test_writePageLastPage(t *testing.T) {
+	cursors := cursor.NewSigner([]byte("secret"))
+	token := cursors.Encode(cursor.Cursor{CreatedAt: time.Now().UTC(), Id: uuid.New()})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users?cursor="+token, nil)
+	rec := httptest.NewRecorder()
+
+	page := models.Page{Limit: 10, After: &models.Keyset{}}
+	writePage(rec, req, cursors, page, models.PageInfo{}, []models.Keyset{}, func(k models.Keyset) models.Keyset { return k }, func(k models.Keyset) uuid.UUID { return k.Id })
+
+	var got dtos.Page[uuid.UUID]
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Empty(t, got.Data)
+	assert.Equal(t, &token, got.Pagination.Cursor)
+	assert.Nil(t, got.Pagination.Offset)
+	assert.Nil(t, got.Pagination.Total)
+	assert.Nil(t, got.Pagination.Next)
+	assert.Nil(t, got.Pagination.Prev)
+	assert.Equal(t, `</api/v1/users>; rel="first"`, rec.Header().Get("Link"))
+}
+
+func Test_writePageSorted(t *testing.T) {
+	cursors := cursor.NewSigner([]byte("secret"))
+	rows := []models.Keyset{{CreatedAt: time.Now().UTC(), Id: uuid.New()}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?limit=5&offset=3&sort=title", nil)
+	rec := httptest.NewRecorder()
+
+	page := models.Page{Limit: 5, Offset: 3, Sort: []query.Sort{{Field: "title"}}}
+	info := models.PageInfo{HasNext: true, HasPrev: true, Total: utils.Ptr(20)}
+	writePage(rec, req, cursors, page, info, rows, func(k models.Keyset) models.Keyset { return k }, func(k models.Keyset) uuid.UUID { return k.Id })
+
+	var got dtos.Page[uuid.UUID]
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, utils.Ptr(3), got.Pagination.Offset)
+	assert.Nil(t, got.Pagination.Next)
+	assert.Nil(t, got.Pagination.Prev)
+	assert.Equal(t,
+		`</api/v1/posts?limit=5&sort=title>; rel="first", `+
+			`</api/v1/posts?limit=5&offset=0&sort=title>; rel="prev", `+
+			`</api/v1/posts?limit=5&offset=8&sort=title>; rel="next"`,
+		rec.Header().Get("Link"),
+	)
+}
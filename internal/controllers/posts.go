@@ -3,62 +3,111 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gera9/blog/internal/auth"
 	"github.com/gera9/blog/internal/controllers/dtos"
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/cursor"
 	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/gera9/blog/pkg/utils"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type PostsService interface {
 	CreatePost(ctx context.Context, post models.Post) (uuid.UUID, error)
-	FindAllPosts(ctx context.Context, limit, offset int, authorId uuid.UUID) ([]models.Post, error)
-	FindPostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID) (models.Post, error)
-	UpdatePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, post models.Post) error
-	DeletePostById(ctx context.Context, id uuid.UUID) error
+	FindAllPosts(ctx context.Context, principal models.Principal, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, models.PageInfo, error)
+	SearchPosts(ctx context.Context, principal models.Principal, q string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error)
+	FindPostById(ctx context.Context, principal models.Principal, id uuid.UUID) (models.Post, error)
+	FindPostBySlug(ctx context.Context, principal models.Principal, username, slug string) (models.Post, bool, error)
+	UpdatePostById(ctx context.Context, principal models.Principal, id uuid.UUID, post models.Post, version *time.Time) error
+	DeletePostById(ctx context.Context, principal models.Principal, id uuid.UUID, version *time.Time) error
+	SubmitPostForReview(ctx context.Context, principal models.Principal, id uuid.UUID) error
+	PublishPost(ctx context.Context, principal models.Principal, id uuid.UUID) error
+	UnpublishPost(ctx context.Context, principal models.Principal, id uuid.UUID) error
+	ArchivePost(ctx context.Context, principal models.Principal, id uuid.UUID) error
+	SchedulePost(ctx context.Context, principal models.Principal, id uuid.UUID, at time.Time) error
+	FindPostRevisions(ctx context.Context, principal models.Principal, id uuid.UUID) ([]models.PostRevision, error)
+	DiffPostRevisions(ctx context.Context, principal models.Principal, id uuid.UUID, rev, against int) (string, error)
+	RestorePostRevision(ctx context.Context, principal models.Principal, id uuid.UUID, rev int) error
+	ReactToPost(ctx context.Context, principal models.Principal, id uuid.UUID, kind models.ReactionKind) error
+	RemovePostReaction(ctx context.Context, principal models.Principal, id uuid.UUID, kind models.ReactionKind) error
 }
 
 type postsController struct {
 	postsService PostsService
+	cursors      *cursor.Signer
 }
 
-func NewPostsController(postsService PostsService) *postsController {
-	return &postsController{postsService}
+func NewPostsController(postsService PostsService, cursors *cursor.Signer) *postsController {
+	return &postsController{postsService, cursors}
 }
 
-func (c postsController) Routes(mm *middlewares.MiddlewareManager) *chi.Mux {
+// postsFields and postsRelations list what listings of posts can be trimmed
+// down to with ?fields= and what they can embed with ?expand=.
+var (
+	postsFields = []string{
+		"id", "title", "slug", "extract", "content", "content_html", "word_count", "reading_time_minutes", "toc",
+		"author_id", "category_id", "tags", "comment_mode", "reactions", "status", "published_at", "scheduled_at",
+		"created_at", "updated_at",
+	}
+	postsRelations = []string{"author"}
+)
+
+func (c postsController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
 	r := chi.NewMux()
 
-	r.Post("/", c.Create)
-	r.With(mm.List).Get("/", c.FindAll)
+	r.With(am.Require(services.PermPostsCreate)).Post("/", c.Create)
+	r.With(mm.KeysetList, mm.Query(models.PostQuerySchema), mm.Project(postsFields, postsRelations)).Get("/", c.FindAll)
+	r.With(mm.List).Get("/search", c.Search)
+	r.Get("/by-slug/{author}/{slug}", c.FindBySlug)
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", c.FindById)
-		r.Patch("/", c.UpdateById)
-		r.Delete("/", c.DeleteById)
+		r.With(am.RequireAuth).Patch("/", c.UpdateById)
+		r.With(am.RequireAuth).Delete("/", c.DeleteById)
+		r.With(am.RequireAuth).Post("/submit", c.transition(c.postsService.SubmitPostForReview))
+		r.With(am.RequireAuth).Post("/publish", c.transition(c.postsService.PublishPost))
+		r.With(am.RequireAuth).Post("/unpublish", c.transition(c.postsService.UnpublishPost))
+		r.With(am.RequireAuth).Post("/archive", c.transition(c.postsService.ArchivePost))
+		r.With(am.RequireAuth).Post("/schedule", c.Schedule)
+		r.With(am.RequireAuth).Get("/revisions", c.FindRevisions)
+		r.With(am.RequireAuth).Get("/revisions/{rev}/diff", c.DiffRevision)
+		r.With(am.RequireAuth).Post("/revisions/{rev}/restore", c.RestoreRevision)
+		r.With(am.RequireAuth).Put("/reactions/{kind}", c.React)
+		r.With(am.RequireAuth).Delete("/reactions/{kind}", c.RemoveReaction)
 	})
 
 	return r
 }
 
 func (c postsController) Create(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
 	postPayload := dtos.CreatePost{}
 	err := json.NewDecoder(r.Body).Decode(&postPayload)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	id, err := c.postsService.CreatePost(r.Context(), postPayload.ToPost())
+	if err := postPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := c.postsService.CreatePost(r.Context(), postPayload.ToPost(principal.UserId))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
@@ -69,65 +118,134 @@ func (c postsController) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c postsController) FindAll(w http.ResponseWriter, r *http.Request) {
+	page := pageFromRequest(r)
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	filter, err := postFilterFromQuery(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Where = middlewares.QueryFromContext(r.Context()).Filters
+
+	projection := middlewares.ProjectionFromContext(r.Context())
+
+	posts, info, err := c.postsService.FindAllPosts(r.Context(), principal, page, filter, models.PostProjection{
+		Fields: projection.Fields,
+		Author: slices.Contains(projection.Expand, "author"),
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writePage(w, r, c.cursors, page, info, posts, func(post models.Post) models.Keyset {
+		return models.Keyset{CreatedAt: post.CreatedAt, Id: post.Id}
+	}, sparse(toPostResponse, projection))
+}
+
+// Search serves the posts matching ?q=, best matches first. It accepts the
+// filters of FindAll.
+func (c postsController) Search(w http.ResponseWriter, r *http.Request) {
 	limit := r.Context().Value(middlewares.ContextKeyLimit).(int)
 	offset := r.Context().Value(middlewares.ContextKeyOffset).(int)
 
-	q := r.URL.Query()
-	authorIdStr := q.Get("author_id")
-	authorId, err := uuid.Parse(authorIdStr)
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		problem.Write(w, r, http.StatusBadRequest, "missing q query parameter")
+		return
+	}
+
+	filter, err := postFilterFromQuery(r.URL.Query())
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid author_id UUID format",
-		})
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	posts, err := c.postsService.FindAllPosts(r.Context(), limit, offset, authorId)
+	results, err := c.postsService.SearchPosts(r.Context(), principal, q, limit, offset, filter)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
-	response := make([]dtos.PostResponse, len(posts))
-	for i, post := range posts {
-		response[i] = toPostResponse(post)
+	response := make([]dtos.PostSearchResultResponse, len(results))
+	for i, result := range results {
+		response[i] = dtos.PostSearchResultResponse{
+			PostResponse: toPostResponse(result.Post),
+			Rank:         result.Rank,
+			Snippet:      result.Snippet,
+			Match:        string(result.Match),
+		}
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
+// postFilterFromQuery reads the ?tag=, ?category= and ?author_id= filters
+// of post listings.
+func postFilterFromQuery(query url.Values) (models.PostFilter, error) {
+	filter := models.PostFilter{
+		Tag:      query.Get("tag"),
+		Category: query.Get("category"),
+	}
+	if authorIdStr := query.Get("author_id"); authorIdStr != "" {
+		var err error
+		filter.AuthorId, err = uuid.Parse(authorIdStr)
+		if err != nil {
+			return models.PostFilter{}, errors.New("invalid author_id UUID format")
+		}
+	}
+
+	return filter, nil
+}
+
 func (c postsController) FindById(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid UUID format",
-		})
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
 		return
 	}
 
-	q := r.URL.Query()
-	authorIdStr := q.Get("author_id")
-	authorId, err := uuid.Parse(authorIdStr)
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	post, err := c.postsService.FindPostById(r.Context(), principal, id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid author_id UUID format",
-		})
+		writeError(w, r, err)
 		return
 	}
 
-	post, err := c.postsService.FindPostByIdAndAuthorId(r.Context(), id, authorId)
+	writeTagged(w, r, toPostResponse(post))
+}
+
+// FindBySlug serves the post of the author with the given username under
+// its slug, and permanently redirects former slugs to the current one.
+func (c postsController) FindBySlug(w http.ResponseWriter, r *http.Request) {
+	author, err := url.PathUnescape(chi.URLParam(r, "author"))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		problem.Write(w, r, http.StatusBadRequest, "invalid author")
+		return
+	}
+
+	postSlug, err := url.PathUnescape(chi.URLParam(r, "slug"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid slug")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	post, moved, err := c.postsService.FindPostBySlug(r.Context(), principal, author, postSlug)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if moved {
+		http.Redirect(w, r, slugLocation(r, author, post.Slug), http.StatusMovedPermanently)
 		return
 	}
 
@@ -135,43 +253,44 @@ func (c postsController) FindById(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(toPostResponse(post))
 }
 
+// slugLocation returns the path of the post of author with slug, under the
+// same by-slug route as r. Both are escaped, as the route was matched on
+// their escaped form.
+func slugLocation(r *http.Request, author, slug string) string {
+	base := strings.TrimSuffix(chi.RouteContext(r.Context()).RoutePattern(), "/{author}/{slug}")
+	return base + "/" + url.PathEscape(author) + "/" + url.PathEscape(slug)
+}
+
 func (c postsController) UpdateById(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid UUID format",
-		})
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
 		return
 	}
 
-	q := r.URL.Query()
-	authorIdStr := q.Get("author_id")
-	authorId, err := uuid.Parse(authorIdStr)
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	postPayload := dtos.UpdatePost{}
+	err = json.NewDecoder(r.Body).Decode(&postPayload)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid author_id UUID format",
-		})
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	postPayload := dtos.CreatePost{}
-	err = json.NewDecoder(r.Body).Decode(&postPayload)
+	if err := postPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	version, err := c.ifMatchVersion(r, principal, id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
-	err = c.postsService.UpdatePostByIdAndAuthorId(r.Context(), id, authorId, postPayload.ToPost())
+	err = c.postsService.UpdatePostById(r.Context(), principal, id, postPayload.ToPost(), version)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
@@ -181,25 +300,249 @@ func (c postsController) UpdateById(w http.ResponseWriter, r *http.Request) {
 func (c postsController) DeleteById(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "invalid UUID format",
-		})
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
 		return
 	}
 
-	err = c.postsService.DeletePostById(r.Context(), id)
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	version, err := c.ifMatchVersion(r, principal, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.postsService.DeletePostById(r.Context(), principal, id, version)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+func (c postsController) Schedule(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	schedulePayload := dtos.SchedulePost{}
+	err = json.NewDecoder(r.Body).Decode(&schedulePayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := schedulePayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.postsService.SchedulePost(r.Context(), principal, id, schedulePayload.ScheduledAt)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c postsController) FindRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	revisions, err := c.postsService.FindPostRevisions(r.Context(), principal, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]dtos.PostRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		response[i] = dtos.ToPostRevisionResponse(revision)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DiffRevision writes the unified diff from revision ?against= of the post,
+// by default the one before it, to revision {rev}. against=0 diffs against
+// an empty post.
+func (c postsController) DiffRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	rev, err := strconv.Atoi(chi.URLParam(r, "rev"))
+	if err != nil || rev < 1 {
+		problem.Write(w, r, http.StatusBadRequest, "revision must be a positive integer")
+		return
+	}
+
+	against := rev - 1
+	if againstStr := r.URL.Query().Get("against"); againstStr != "" {
+		against, err = strconv.Atoi(againstStr)
+		if err != nil || against < 0 {
+			problem.Write(w, r, http.StatusBadRequest, "against must be a non-negative integer")
+			return
+		}
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	unified, err := c.postsService.DiffPostRevisions(r.Context(), principal, id, rev, against)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, unified)
+}
+
+func (c postsController) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	rev, err := strconv.Atoi(chi.URLParam(r, "rev"))
+	if err != nil || rev < 1 {
+		problem.Write(w, r, http.StatusBadRequest, "revision must be a positive integer")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	err = c.postsService.RestorePostRevision(r.Context(), principal, id, rev)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// React sets the reaction of the caller to the post, replacing any other
+// reaction of theirs.
+func (c postsController) React(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	err = c.postsService.ReactToPost(r.Context(), principal, id, models.ReactionKind(chi.URLParam(r, "kind")))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveReaction takes back the reaction of the caller to the post.
+func (c postsController) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	err = c.postsService.RemovePostReaction(r.Context(), principal, id, models.ReactionKind(chi.URLParam(r, "kind")))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// transition returns a handler moving the post in the URL through the
+// workflow with action.
+func (c postsController) transition(action func(ctx context.Context, principal models.Principal, id uuid.UUID) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+			return
+		}
+
+		principal, _ := auth.PrincipalFromContext(r.Context())
+
+		err = action(r.Context(), principal, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ifMatchVersion checks the If-Match header of r against the post as
+// FindById would serve it to principal.
+func (c postsController) ifMatchVersion(r *http.Request, principal models.Principal, id uuid.UUID) (*time.Time, error) {
+	return ifMatchVersion(r, func() (any, time.Time, error) {
+		post, err := c.postsService.FindPostById(r.Context(), principal, id)
+		return toPostResponse(post), post.UpdatedAt, err
+	})
+}
+
 func toPostResponse(post models.Post) dtos.PostResponse {
-	return dtos.PostResponse(post)
+	toc := make([]dtos.TocEntryResponse, len(post.Toc))
+	for i, entry := range post.Toc {
+		toc[i] = dtos.TocEntryResponse{Level: entry.Level, Id: entry.Id, Text: entry.Text}
+	}
+
+	reactions := make(map[string]int, len(post.Reactions))
+	for kind, count := range post.Reactions {
+		reactions[string(kind)] = count
+	}
+
+	var author *dtos.PublicUserResponse
+	if post.Author != nil {
+		author = utils.Ptr(dtos.ToPublicUserResponse(*post.Author))
+	}
+
+	return dtos.PostResponse{
+		Id:             post.Id,
+		Title:          post.Title,
+		Slug:           post.Slug,
+		Extract:        post.Extract,
+		Content:        post.Content,
+		ContentHTML:    post.ContentHTML,
+		WordCount:      post.WordCount,
+		ReadingMinutes: post.ReadingMinutes,
+		Toc:            toc,
+		AuthorId:       post.AuthorId,
+		Author:         author,
+		CategoryId:     post.CategoryId,
+		Tags:           post.Tags,
+		CommentMode:    string(post.CommentMode),
+		Reactions:      reactions,
+		Status:         string(post.Status),
+		PublishedAt:    post.PublishedAt,
+		ScheduledAt:    post.ScheduledAt,
+		CreatedAt:      post.CreatedAt,
+		UpdatedAt:      post.UpdatedAt,
+	}
 }
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_slugLocation(t *testing.T) {
+	var got string
+	posts := chi.NewMux()
+	posts.Get("/by-slug/{author}/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		got = slugLocation(r, "jane doe/x", "new slug?")
+	})
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Mount("/posts", posts)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/posts/by-slug/jane%20doe%2Fx/old-slug", nil))
+
+	assert.Equal(t, "/api/v1/posts/by-slug/jane%20doe%2Fx/new%20slug%3F", got)
+}
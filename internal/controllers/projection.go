@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/gera9/blog/pkg/query"
+)
+
+// sparse renders rows with toResponse, trimmed down to the fields asked for
+// by projection along with the relations it expands. Responses go through
+// their JSON encoding so that fields are picked by the names clients know
+// them by. Without ?fields= responses are left whole.
+func sparse[T, R any](toResponse func(T) R, projection query.Projection) func(T) any {
+	if len(projection.Fields) == 0 {
+		return func(row T) any { return toResponse(row) }
+	}
+
+	keep := append(slices.Clone(projection.Fields), projection.Expand...)
+
+	return func(row T) any {
+		encoded, _ := json.Marshal(toResponse(row))
+
+		var fields map[string]json.RawMessage
+		json.Unmarshal(encoded, &fields)
+		for name := range fields {
+			if !slices.Contains(keep, name) {
+				delete(fields, name)
+			}
+		}
+
+		return fields
+	}
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/query"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sparse(t *testing.T) {
+	post := models.Post{
+		Id:      uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+		Title:   "My First Post",
+		Content: "This is the full content of my first post.",
+		Author:  &models.User{Username: "alice_s", Email: "alice@example.com"},
+	}
+
+	tests := []struct {
+		name       string
+		projection query.Projection
+		want       string
+	}{
+		{
+			name:       "Should trim responses down to the fields asked for",
+			projection: query.Projection{Fields: []string{"id", "title"}},
+			want:       `{"id":"91c1538a-518c-4b05-9a1e-180c561a70b3","title":"My First Post"}`,
+		},
+		{
+			name:       "Should keep expanded relations",
+			projection: query.Projection{Fields: []string{"title"}, Expand: []string{"author"}},
+			want:       `{"author":{"id":"00000000-0000-0000-0000-000000000000","first_name":"","last_name":"","username":"alice_s","created_at":"0001-01-01T00:00:00Z"},"title":"My First Post"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(sparse(toPostResponse, tt.projection)(post))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	whole := sparse(toPostResponse, query.Projection{})(post)
+	assert.Equal(t, toPostResponse(post), whole)
+}
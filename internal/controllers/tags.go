@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gera9/blog/internal/auth"
+	"github.com/gera9/blog/internal/controllers/dtos"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type TagsService interface {
+	CreateTag(ctx context.Context, tag models.Tag) (uuid.UUID, error)
+	FindAllTags(ctx context.Context, limit, offset int) ([]models.Tag, error)
+	FindTagById(ctx context.Context, id uuid.UUID) (models.Tag, error)
+	UpdateTagById(ctx context.Context, id uuid.UUID, tag models.Tag) error
+	DeleteTagById(ctx context.Context, id uuid.UUID) error
+}
+
+type tagsController struct {
+	tagsService TagsService
+}
+
+func NewTagsController(tagsService TagsService) *tagsController {
+	return &tagsController{tagsService}
+}
+
+func (c tagsController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
+	r := chi.NewMux()
+
+	r.With(am.Require(services.PermTaxonomyManage)).Post("/", c.Create)
+	r.With(mm.List).Get("/", c.FindAll)
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", c.FindById)
+		r.With(am.Require(services.PermTaxonomyManage)).Patch("/", c.UpdateById)
+		r.With(am.Require(services.PermTaxonomyManage)).Delete("/", c.DeleteById)
+	})
+
+	return r
+}
+
+func (c tagsController) Create(w http.ResponseWriter, r *http.Request) {
+	tagPayload := dtos.CreateTag{}
+	err := json.NewDecoder(r.Body).Decode(&tagPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := tagPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := c.tagsService.CreateTag(r.Context(), tagPayload.ToTag())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"id": id,
+	})
+}
+
+// FindAll lists tags with their number of published posts, most used first,
+// which is what a tag cloud is drawn from.
+func (c tagsController) FindAll(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(middlewares.ContextKeyLimit).(int)
+	offset := r.Context().Value(middlewares.ContextKeyOffset).(int)
+
+	tags, err := c.tagsService.FindAllTags(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]dtos.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = dtos.ToTagResponse(tag)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c tagsController) FindById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	tag, err := c.tagsService.FindTagById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.ToTagResponse(tag))
+}
+
+func (c tagsController) UpdateById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	tagPayload := dtos.UpdateTag{}
+	err = json.NewDecoder(r.Body).Decode(&tagPayload)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := tagPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.tagsService.UpdateTagById(r.Context(), id, tagPayload.ToTag())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c tagsController) DeleteById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid UUID format")
+		return
+	}
+
+	err = c.tagsService.DeleteTagById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
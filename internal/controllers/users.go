@@ -4,39 +4,52 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
+
 	"github.com/google/uuid"
 
+	"github.com/gera9/blog/internal/auth"
 	"github.com/gera9/blog/internal/controllers/dtos"
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/services"
+	"github.com/gera9/blog/pkg/cursor"
 	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/gera9/blog/pkg/query"
+	"github.com/gera9/blog/pkg/utils"
 	"github.com/go-chi/chi/v5"
 )
 
 type UsersService interface {
 	CreateUser(ctx context.Context, user models.User) (uuid.UUID, error)
-	FindAllUsers(ctx context.Context, limit, offset int) ([]models.User, error)
+	FindAllUsers(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, models.PageInfo, error)
 	FindUserById(ctx context.Context, id uuid.UUID) (models.User, error)
-	UpdateUserById(ctx context.Context, id uuid.UUID, user models.User) error
-	DeleteUserById(ctx context.Context, id uuid.UUID) error
+	CanReadPrivateProfile(principal models.Principal, id uuid.UUID) bool
+	UpdateUserById(ctx context.Context, principal models.Principal, id uuid.UUID, user models.User, version *time.Time) error
+	UpdateUserRoleById(ctx context.Context, id uuid.UUID, role models.Role) error
+	DeleteUserById(ctx context.Context, principal models.Principal, id uuid.UUID, version *time.Time) error
 }
 
 type usersController struct {
 	usersService UsersService
+	cursors      *cursor.Signer
+	timeProvider utils.TimeProvider
 }
 
-func NewUsersController(usersService UsersService) *usersController {
-	return &usersController{usersService}
+func NewUsersController(usersService UsersService, cursors *cursor.Signer, timeProvider utils.TimeProvider) *usersController {
+	return &usersController{usersService, cursors, timeProvider}
 }
 
-func (c usersController) Routes(mm *middlewares.MiddlewareManager) *chi.Mux {
+func (c usersController) Routes(mm *middlewares.MiddlewareManager, am *auth.Middleware) *chi.Mux {
 	r := chi.NewMux()
 
 	r.Post("/", c.Create)
-	r.With(mm.List).Get("/", c.FindAll)
+	r.With(mm.KeysetList, mm.Query(models.UserQuerySchema)).Get("/", c.FindAll)
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", c.FindById)
-		r.Patch("/", c.UpdateById)
-		r.Delete("/", c.DeleteById)
+		r.With(am.RequireAuth).Patch("/", c.UpdateById)
+		r.With(am.RequireAuth).Delete("/", c.DeleteById)
+		r.With(am.Require(services.PermUsersManage)).Put("/role", c.UpdateRoleById)
 	})
 
 	return r
@@ -46,19 +59,18 @@ func (c usersController) Create(w http.ResponseWriter, r *http.Request) {
 	userPayload := dtos.CreateUser{}
 	err := json.NewDecoder(r.Body).Decode(&userPayload)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := userPayload.Validate(c.timeProvider.Now()); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	id, err := c.usersService.CreateUser(r.Context(), userPayload.ToUser())
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
@@ -69,73 +81,70 @@ func (c usersController) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c usersController) FindAll(w http.ResponseWriter, r *http.Request) {
-	limit := r.Context().Value(middlewares.ContextKeyLimit).(int)
-	offset := r.Context().Value(middlewares.ContextKeyOffset).(int)
+	page := pageFromRequest(r)
+
+	filters := middlewares.QueryFromContext(r.Context()).Filters
 
-	users, err := c.usersService.FindAllUsers(r.Context(), limit, offset)
+	users, info, err := c.usersService.FindAllUsers(r.Context(), page, filters)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
-	response := make([]dtos.UserResponse, len(users))
-	for i, user := range users {
-		response[i] = toUserResponse(user)
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	writePage(w, r, c.cursors, page, info, users, func(user models.User) models.Keyset {
+		return models.Keyset{CreatedAt: user.CreatedAt, Id: user.Id}
+	}, func(user models.User) any {
+		return c.toUserResponse(r, user)
+	})
 }
 
 func (c usersController) FindById(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid id"})
+		problem.Write(w, r, http.StatusBadRequest, "invalid id")
 		return
 	}
 
 	user, err := c.usersService.FindUserById(r.Context(), id)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(toUserResponse(user))
+	writeTagged(w, r, c.toUserResponse(r, user))
 }
 
 func (c usersController) UpdateById(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid id"})
+		problem.Write(w, r, http.StatusBadRequest, "invalid id")
 		return
 	}
 
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
 	userPayload := dtos.UpdateUser{}
 	if err := json.NewDecoder(r.Body).Decode(&userPayload); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err = c.usersService.UpdateUserById(r.Context(), id, userPayload.ToUser())
+	if err := userPayload.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	version, err := c.ifMatchVersion(r, id)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
+		return
+	}
+
+	err = c.usersService.UpdateUserById(r.Context(), principal, id, userPayload.ToUser(), version)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -146,23 +155,66 @@ func (c usersController) DeleteById(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid id"})
+		problem.Write(w, r, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	version, err := c.ifMatchVersion(r, id)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	err = c.usersService.DeleteUserById(r.Context(), id)
+	err = c.usersService.DeleteUserById(r.Context(), principal, id, version)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
-func toUserResponse(user models.User) dtos.UserResponse {
-	return dtos.UserResponse(user)
+func (c usersController) UpdateRoleById(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	rolePayload := dtos.UpdateUserRole{}
+	if err := json.NewDecoder(r.Body).Decode(&rolePayload); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = c.usersService.UpdateUserRoleById(r.Context(), id, models.Role(rolePayload.Role))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ifMatchVersion checks the If-Match header of r against the user as
+// FindById would serve it.
+func (c usersController) ifMatchVersion(r *http.Request, id uuid.UUID) (*time.Time, error) {
+	return ifMatchVersion(r, func() (any, time.Time, error) {
+		user, err := c.usersService.FindUserById(r.Context(), id)
+		return c.toUserResponse(r, user), user.UpdatedAt, err
+	})
+}
+
+// toUserResponse picks the view of user that the caller of r is allowed to
+// see.
+func (c usersController) toUserResponse(r *http.Request, user models.User) any {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	if c.usersService.CanReadPrivateProfile(principal, user.Id) {
+		return dtos.ToPrivateUserResponse(user)
+	}
+
+	return dtos.ToPublicUserResponse(user)
 }
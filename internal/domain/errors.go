@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds shared by every layer. Repositories translate driver errors
+// into them, services return them and controllers map them to HTTP statuses.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrPreconditionFailed reports a write aimed at a version of a resource
+	// that is no longer the current one.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// ErrModified reports a write that lost the race against another write to the
+// same resource. It is a conflict, which services turn into
+// ErrPreconditionFailed when the client aimed the write at a version.
+var ErrModified = Errorf(ErrConflict, "resource was modified in the meantime")
+
+// Error is a domain error of a given kind carrying a message that is safe to
+// show to clients.
+type Error struct {
+	kind    error
+	message string
+	fields  map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// Errorf returns an error of the given kind whose message is built from
+// format and args. The resulting error matches kind with errors.Is.
+func Errorf(kind error, format string, args ...any) error {
+	return &Error{kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+// FieldsErrorf is like Errorf but also attaches a message per offending
+// field, keyed by the field name clients know it by.
+func FieldsErrorf(kind error, fields map[string]string, format string, args ...any) error {
+	return &Error{kind: kind, message: fmt.Sprintf(format, args...), fields: fields}
+}
+
+// Fields returns the per-field messages attached to err, if any.
+func Fields(err error) map[string]string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.fields
+	}
+
+	return nil
+}
+
+// Message returns the client safe message of err, falling back to the text of
+// its kind, or an empty string when err is not a domain error.
+func Message(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.message
+	}
+
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrValidation, ErrForbidden, ErrUnauthorized, ErrPreconditionFailed} {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	err := domain.Errorf(domain.ErrConflict, "%s already exists", "email")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, "email already exists", err.Error())
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Should return the message of a domain error",
+			err:  fmt.Errorf("loading user: %w", domain.Errorf(domain.ErrNotFound, "user not found")),
+			want: "user not found",
+		},
+		{
+			name: "Should fall back to the kind",
+			err:  fmt.Errorf("loading user: %w", domain.ErrForbidden),
+			want: "forbidden",
+		},
+		{
+			name: "Should hide unknown errors",
+			err:  errors.New(`pq: relation "users" does not exist`),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.Message(tt.err))
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	err := fmt.Errorf("creating user: %w", domain.FieldsErrorf(domain.ErrConflict, map[string]string{"email": "already exists"}, "email already exists"))
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Equal(t, map[string]string{"email": "already exists"}, domain.Fields(err))
+	assert.Nil(t, domain.Fields(domain.Errorf(domain.ErrNotFound, "user not found")))
+	assert.Nil(t, domain.Fields(errors.New("boom")))
+}
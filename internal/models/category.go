@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a node of the category tree. Root categories have no parent.
+type Category struct {
+	Id        uuid.UUID
+	Name      string
+	Slug      string
+	ParentId  *uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a comment on a post, or a reply to another comment of the same
+// post when it has a parent.
+type Comment struct {
+	Id       uuid.UUID
+	PostId   uuid.UUID
+	AuthorId uuid.UUID
+	ParentId *uuid.UUID
+	Body     string
+	Status   CommentStatus
+	// SpamScore is how much the comment looked like spam when it was posted,
+	// and SpamReasons the rules that scored it.
+	SpamScore   float64
+	SpamReasons []string
+	// ModeratedBy is the last user to approve or reject the comment, if any.
+	ModeratedBy *uuid.UUID
+	ModeratedAt *time.Time
+	// Depth is how many comments the comment is nested under. It is only
+	// filled in by thread listings.
+	Depth     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CommentStatus tells whether a comment is shown. Pending comments wait for
+// a moderator and are only shown to their authors.
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+func (s CommentStatus) IsValid() bool {
+	switch s {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected:
+		return true
+	}
+
+	return false
+}
+
+// CommentMode is chosen by the author of a post to decide how its comments
+// are accepted.
+type CommentMode string
+
+const (
+	// CommentModeOpen shows comments right away, except those of first-time
+	// commenters and those that look like spam.
+	CommentModeOpen CommentMode = "open"
+	// CommentModeModerated holds every comment for moderation.
+	CommentModeModerated CommentMode = "moderated"
+	// CommentModeClosed accepts no new comments.
+	CommentModeClosed CommentMode = "closed"
+)
+
+func (m CommentMode) IsValid() bool {
+	switch m {
+	case CommentModeOpen, CommentModeModerated, CommentModeClosed:
+		return true
+	}
+
+	return false
+}
+
+// SpamVerdict is the outcome of scoring a comment for spam.
+type SpamVerdict struct {
+	Score   float64
+	Reasons []string
+	// Spam reports that the score reached the threshold of the scorer.
+	Spam bool
+}
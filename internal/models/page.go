@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gera9/blog/pkg/query"
+	"github.com/google/uuid"
+)
+
+// Keyset locates a row in listings ordered newest first, the id breaking ties
+// between rows created at the same time.
+type Keyset struct {
+	CreatedAt time.Time
+	Id        uuid.UUID
+}
+
+// Page selects a slice of a listing. When After or Before is set, the page
+// holds the rows right after or right before that keyset, and Offset is
+// ignored.
+type Page struct {
+	Limit  int
+	Offset int
+	After  *Keyset
+	Before *Keyset
+	// Sort orders the listing ahead of its default order, which is left to
+	// break ties. Keysets only follow the default order, so sorted listings
+	// are paged by offset.
+	Sort []query.Sort
+}
+
+// PageInfo tells whether a listing goes on past either end of a page.
+type PageInfo struct {
+	HasNext bool
+	HasPrev bool
+	// Total is the size of the whole listing, left nil when it was not
+	// counted.
+	Total *int
+}
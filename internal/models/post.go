@@ -3,15 +3,89 @@ package models
 import (
 	"time"
 
+	"github.com/gera9/blog/pkg/query"
 	"github.com/google/uuid"
 )
 
 type Post struct {
-	Id        uuid.UUID
-	Title     string
-	Extract   string
-	Content   string
-	AuthorId  uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id      uuid.UUID
+	Title   string
+	Slug    string
+	Extract string
+	Content string
+	// ContentHTML is Content rendered from Markdown and sanitized.
+	ContentHTML string
+	// ContentText is the text of Content without markup or code. It is only
+	// stored, for search snippets, and never loaded.
+	ContentText string
+	// WordCount, ReadingMinutes and Toc are derived from Content whenever
+	// it is rendered.
+	WordCount      int
+	ReadingMinutes int
+	Toc            []TocEntry
+	AuthorId       uuid.UUID
+	// Author holds the public profile of the author when a listing was asked
+	// to load it, and is nil otherwise.
+	Author     *User
+	CategoryId *uuid.UUID
+	// Tags holds the slugs of the tags of the post.
+	Tags        []string
+	CommentMode CommentMode
+	// Reactions counts the reactions to the post by kind, leaving out the
+	// kinds nobody used.
+	Reactions   map[ReactionKind]int
+	Status      PostStatus
+	PublishedAt *time.Time
+	// ScheduledAt is when an unpublished post will be published automatically.
+	ScheduledAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TocEntry is a heading of a post. Id is the fragment linking to it in
+// ContentHTML.
+type TocEntry struct {
+	Level int    `json:"level"`
+	Id    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// PostQuerySchema lists the fields posts can be sorted and filtered on with
+// ?sort= and ?filter[field][op]=. Repositories map these same fields to their
+// columns.
+var PostQuerySchema = query.Schema{
+	"title":                {Type: query.TypeText, Sortable: true, Filterable: true},
+	"slug":                 {Type: query.TypeEnum, Filterable: true},
+	"status":               {Type: query.TypeEnum, Filterable: true},
+	"word_count":           {Type: query.TypeInt, Sortable: true, Filterable: true},
+	"reading_time_minutes": {Type: query.TypeInt, Sortable: true, Filterable: true},
+	"published_at":         {Type: query.TypeTime, Sortable: true, Filterable: true},
+	"created_at":           {Type: query.TypeTime, Sortable: true, Filterable: true},
+	"updated_at":           {Type: query.TypeTime, Sortable: true, Filterable: true},
+}
+
+// PostFilter narrows down post listings. Zero valued fields do not filter.
+type PostFilter struct {
+	AuthorId uuid.UUID
+	Statuses []PostStatus
+	// Tag is the slug of a tag the posts must carry.
+	Tag string
+	// Category is the slug of a category the posts must belong to, directly
+	// or through one of its subcategories.
+	Category string
+	// Where holds the filters clients asked for in the query language of
+	// listings.
+	Where []query.Filter
+}
+
+// PostProjection decides what post listings load. The zero value loads every
+// field of the posts and nothing else.
+type PostProjection struct {
+	// Fields names the fields to load as responses name them, every field
+	// being loaded when empty. The id and creation time, which locate posts
+	// in listings, are always loaded.
+	Fields []string
+	// Author loads the public profile of the author of each post into
+	// Post.Author.
+	Author bool
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostRevision is an immutable snapshot of the text of a post, taken every
+// time the text changes. Revisions are numbered from 1 per post.
+type PostRevision struct {
+	PostId    uuid.UUID
+	Revision  int
+	Title     string
+	Extract   string
+	Content   string
+	CreatedAt time.Time
+}
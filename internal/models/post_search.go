@@ -0,0 +1,24 @@
+package models
+
+// PostMatch tells how a search result matched the query.
+type PostMatch string
+
+const (
+	// PostMatchFullText is a match of the words of the query in the title,
+	// extract or content of the post.
+	PostMatchFullText PostMatch = "full_text"
+	// PostMatchSimilarTitle is a title resembling the query, found when no
+	// post contains its words, as happens with typos.
+	PostMatchSimilarTitle PostMatch = "similar_title"
+)
+
+type PostSearchResult struct {
+	Post Post
+	// Rank orders the results, higher ranks first. It compares results of
+	// the same search only.
+	Rank float64
+	// Snippet is an HTML excerpt of the post with the matching words wrapped
+	// in <mark> elements.
+	Snippet string
+	Match   PostMatch
+}
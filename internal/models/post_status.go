@@ -0,0 +1,35 @@
+package models
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusInReview  PostStatus = "in_review"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// postTransitions lists the statuses each status can move to. Posts go from
+// draft through review to published and end up archived, and any of them can
+// be sent back to draft.
+var postTransitions = map[PostStatus][]PostStatus{
+	PostStatusDraft:     {PostStatusInReview, PostStatusPublished},
+	PostStatusInReview:  {PostStatusDraft, PostStatusPublished},
+	PostStatusPublished: {PostStatusDraft, PostStatusArchived},
+	PostStatusArchived:  {PostStatusDraft},
+}
+
+func (s PostStatus) IsValid() bool {
+	_, ok := postTransitions[s]
+	return ok
+}
+
+func (s PostStatus) CanTransitionTo(to PostStatus) bool {
+	for _, allowed := range postTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
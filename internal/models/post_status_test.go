@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from models.PostStatus
+		to   models.PostStatus
+		want bool
+	}{
+		{from: models.PostStatusDraft, to: models.PostStatusInReview, want: true},
+		{from: models.PostStatusDraft, to: models.PostStatusPublished, want: true},
+		{from: models.PostStatusDraft, to: models.PostStatusArchived, want: false},
+		{from: models.PostStatusInReview, to: models.PostStatusPublished, want: true},
+		{from: models.PostStatusPublished, to: models.PostStatusArchived, want: true},
+		{from: models.PostStatusPublished, to: models.PostStatusInReview, want: false},
+		{from: models.PostStatusArchived, to: models.PostStatusPublished, want: false},
+		{from: models.PostStatusArchived, to: models.PostStatusDraft, want: true},
+		{from: models.PostStatus("deleted"), to: models.PostStatusDraft, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
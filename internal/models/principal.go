@@ -0,0 +1,10 @@
+package models
+
+import "github.com/google/uuid"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserId   uuid.UUID
+	Username string
+	Role     Role
+}
@@ -0,0 +1,21 @@
+package models
+
+// ReactionKind is how a user reacted to a post. Users have at most one
+// reaction per post.
+type ReactionKind string
+
+const (
+	ReactionKindLike       ReactionKind = "like"
+	ReactionKindLove       ReactionKind = "love"
+	ReactionKindInsightful ReactionKind = "insightful"
+	ReactionKindFunny      ReactionKind = "funny"
+)
+
+func (k ReactionKind) IsValid() bool {
+	switch k {
+	case ReactionKindLike, ReactionKindLove, ReactionKindInsightful, ReactionKindFunny:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,18 @@
+package models
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleEditor, RoleAuthor:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	Id   uuid.UUID
+	Name string
+	Slug string
+	// PostCount is the number of published posts carrying the tag. It is
+	// only filled in when reading tags.
+	PostCount int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
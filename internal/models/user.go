@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/gera9/blog/pkg/query"
 	"github.com/google/uuid"
 )
 
@@ -13,7 +14,22 @@ type User struct {
 	Email          string
 	Username       string
 	HashedPassword string
+	Role           Role
 	BirthDate      time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+
+	// Password is the plaintext password received from a client. It is never
+	// persisted; services hash it into HashedPassword.
+	Password string
+}
+
+// UserQuerySchema lists the fields users can be sorted and filtered on with
+// ?sort= and ?filter[field][op]=, which are all public. Repositories map
+// these same fields to their columns.
+var UserQuerySchema = query.Schema{
+	"username":   {Type: query.TypeText, Sortable: true, Filterable: true},
+	"first_name": {Type: query.TypeText, Sortable: true, Filterable: true},
+	"last_name":  {Type: query.TypeText, Sortable: true, Filterable: true},
+	"created_at": {Type: query.TypeTime, Sortable: true, Filterable: true},
 }
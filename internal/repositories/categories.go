@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/postgres"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const categoryColumns = `id, name, slug, parent_id, created_at, updated_at`
+
+type CategoriesRepository struct {
+	conn         *postgres.Postgres
+	timeProvider utils.TimeProvider
+	tableName    string
+}
+
+func NewCategoriesRepository(conn *postgres.Postgres, timeProvider utils.TimeProvider) *CategoriesRepository {
+	return &CategoriesRepository{
+		conn:         conn,
+		timeProvider: timeProvider,
+		tableName:    "categories",
+	}
+}
+
+func (r CategoriesRepository) CreateCategory(ctx context.Context, category models.Category) (uuid.UUID, error) {
+	now := r.timeProvider.Now().UTC()
+	if category.CreatedAt.IsZero() {
+		category.CreatedAt = now
+	}
+	if category.UpdatedAt.IsZero() {
+		category.UpdatedAt = now
+	}
+
+	sql := `INSERT INTO ` + r.tableName + ` (
+		name, slug, parent_id, created_at, updated_at
+	) VALUES ($1,$2,$3,$4,$5) RETURNING id`
+
+	var returnedID uuid.UUID
+	err := r.conn.Pool().QueryRow(ctx, sql,
+		category.Name,
+		category.Slug,
+		category.ParentId,
+		category.CreatedAt,
+		category.UpdatedAt,
+	).Scan(&returnedID)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return returnedID, nil
+}
+
+// FindAllCategories returns every category, parents before their children,
+// so that callers can assemble the tree in a single pass.
+func (r CategoriesRepository) FindAllCategories(ctx context.Context) ([]models.Category, error) {
+	sql := `WITH RECURSIVE tree AS (
+		SELECT ` + categoryColumns + `, 0 AS depth FROM ` + r.tableName + ` WHERE parent_id IS NULL
+		UNION ALL
+		SELECT c.id, c.name, c.slug, c.parent_id, c.created_at, c.updated_at, tree.depth + 1
+		FROM ` + r.tableName + ` c JOIN tree ON c.parent_id = tree.id
+	)
+	SELECT ` + categoryColumns + ` FROM tree ORDER BY depth, name`
+
+	rows, err := r.conn.Pool().Query(ctx, sql)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	categories := make([]models.Category, 0)
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return categories, nil
+}
+
+func (r CategoriesRepository) FindCategoryById(ctx context.Context, id uuid.UUID) (models.Category, error) {
+	sql := `SELECT ` + categoryColumns + `
+	FROM ` + r.tableName + ` WHERE id = $1`
+
+	return scanCategory(r.conn.Pool().QueryRow(ctx, sql, id))
+}
+
+// FindCategoryAncestorIds returns the ids of every category above id, from
+// its parent up to the root.
+func (r CategoriesRepository) FindCategoryAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	sql := `WITH RECURSIVE ancestors AS (
+		SELECT parent_id AS id, 1 AS depth FROM ` + r.tableName + ` WHERE id = $1 AND parent_id IS NOT NULL
+		UNION ALL
+		SELECT c.parent_id, ancestors.depth + 1
+		FROM ` + r.tableName + ` c JOIN ancestors ON c.id = ancestors.id
+		WHERE c.parent_id IS NOT NULL
+	)
+	SELECT id FROM ancestors ORDER BY depth`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return ids, nil
+}
+
+func (r CategoriesRepository) CountCategoryChildren(ctx context.Context, id uuid.UUID) (int, error) {
+	sql := `SELECT count(*) FROM ` + r.tableName + ` WHERE parent_id = $1`
+
+	var count int
+	err := r.conn.Pool().QueryRow(ctx, sql, id).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
+}
+
+func (r CategoriesRepository) UpdateCategoryById(ctx context.Context, id uuid.UUID, category models.Category) error {
+	sql := `UPDATE ` + r.tableName + ` SET
+		name = $1,
+		slug = $2,
+		parent_id = $3,
+		updated_at = $4
+	WHERE id = $5`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql,
+		category.Name,
+		category.Slug,
+		category.ParentId,
+		r.timeProvider.Now().UTC(),
+		id,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r CategoriesRepository) DeleteCategoryById(ctx context.Context, id uuid.UUID) error {
+	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql, id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanCategory(row pgx.Row) (models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.Id,
+		&category.Name,
+		&category.Slug,
+		&category.ParentId,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return models.Category{}, translateError(err)
+	}
+
+	category.CreatedAt = category.CreatedAt.UTC()
+	category.UpdatedAt = category.UpdatedAt.UTC()
+
+	return category, nil
+}
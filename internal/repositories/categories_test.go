@@ -0,0 +1,120 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	programmingCategoryId = uuid.MustParse("6f1d7b2e-0c1a-4b8e-9a53-1f2d3c4b5a60")
+	goCategoryId          = uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")
+	lifeCategoryId        = uuid.MustParse("d4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12")
+)
+
+type categoriesTestsSuite struct {
+	suite.Suite
+	categoriesRepo *repositories.CategoriesRepository
+}
+
+// This will run before running the suite
+func (s *categoriesTestsSuite) SetupSuite() {
+	s.categoriesRepo = repositories.NewCategoriesRepository(PostgresConn, utils.MockClock{})
+}
+
+// This will run after each test
+func (s *categoriesTestsSuite) TearDownTest() {
+	err := PostgresContainer.Restore(context.TODO())
+	require.NoError(s.T(), err)
+	PostgresConn.Pool().Reset()
+}
+
+func TestCategoriesRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(categoriesTestsSuite))
+}
+
+func (s *categoriesTestsSuite) TestCreateCategory() {
+	t := s.T()
+
+	id, err := s.categoriesRepo.CreateCategory(context.TODO(), models.Category{Name: "Generics", Slug: "generics", ParentId: &goCategoryId})
+	require.NoError(t, err)
+
+	got, err := s.categoriesRepo.FindCategoryById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, &goCategoryId, got.ParentId)
+
+	_, err = s.categoriesRepo.CreateCategory(context.TODO(), models.Category{Name: "Orphan", Slug: "orphan", ParentId: utils.Ptr(uuid.MustParse("00000000-0000-0000-0000-000000000001"))})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{"parent_id": "does not exist"}, domain.Fields(err))
+}
+
+func (s *categoriesTestsSuite) TestFindAllCategories() {
+	t := s.T()
+
+	categories, err := s.categoriesRepo.FindAllCategories(context.TODO())
+	require.NoError(t, err)
+
+	got := make([]string, len(categories))
+	for i, category := range categories {
+		got[i] = category.Slug
+	}
+	// Roots come first so that parents always precede their children.
+	assert.Equal(t, []string{"life", "programming", "go"}, got)
+}
+
+func (s *categoriesTestsSuite) TestFindCategoryAncestorIds() {
+	t := s.T()
+
+	ids, err := s.categoriesRepo.FindCategoryAncestorIds(context.TODO(), goCategoryId)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{programmingCategoryId}, ids)
+
+	ids, err = s.categoriesRepo.FindCategoryAncestorIds(context.TODO(), programmingCategoryId)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func (s *categoriesTestsSuite) TestCountCategoryChildren() {
+	t := s.T()
+
+	count, err := s.categoriesRepo.CountCategoryChildren(context.TODO(), programmingCategoryId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = s.categoriesRepo.CountCategoryChildren(context.TODO(), goCategoryId)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func (s *categoriesTestsSuite) TestUpdateCategoryById() {
+	t := s.T()
+
+	err := s.categoriesRepo.UpdateCategoryById(context.TODO(), goCategoryId, models.Category{Name: "Golang", Slug: "golang", ParentId: &lifeCategoryId})
+	require.NoError(t, err)
+
+	got, err := s.categoriesRepo.FindCategoryById(context.TODO(), goCategoryId)
+	require.NoError(t, err)
+	assert.Equal(t, "golang", got.Slug)
+	assert.Equal(t, &lifeCategoryId, got.ParentId)
+}
+
+func (s *categoriesTestsSuite) TestDeleteCategoryById() {
+	t := s.T()
+
+	// Posts in a deleted category are left without one.
+	require.NoError(t, s.categoriesRepo.DeleteCategoryById(context.TODO(), goCategoryId))
+
+	_, err := s.categoriesRepo.FindCategoryById(context.TODO(), goCategoryId)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = s.categoriesRepo.DeleteCategoryById(context.TODO(), goCategoryId)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,300 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/postgres"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const commentColumns = `id, post_id, author_id, parent_id, body, status, spam_score, spam_reasons, moderated_by, moderated_at, created_at, updated_at`
+
+type CommentsRepository struct {
+	conn         *postgres.Postgres
+	timeProvider utils.TimeProvider
+	tableName    string
+}
+
+func NewCommentsRepository(conn *postgres.Postgres, timeProvider utils.TimeProvider) *CommentsRepository {
+	return &CommentsRepository{
+		conn:         conn,
+		timeProvider: timeProvider,
+		tableName:    "comments",
+	}
+}
+
+// CreateComment stores comment, failing with a validation error on parent_id
+// when its parent is not a comment of the same post.
+func (r CommentsRepository) CreateComment(ctx context.Context, comment models.Comment) (uuid.UUID, error) {
+	now := r.timeProvider.Now().UTC()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = now
+	}
+	if comment.UpdatedAt.IsZero() {
+		comment.UpdatedAt = now
+	}
+	if comment.Status == "" {
+		comment.Status = models.CommentStatusPending
+	}
+	if comment.SpamReasons == nil {
+		comment.SpamReasons = []string{}
+	}
+
+	sql := `INSERT INTO ` + r.tableName + ` (
+		post_id, author_id, parent_id, body, status, spam_score, spam_reasons, created_at, updated_at
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`
+
+	var returnedID uuid.UUID
+	err := r.conn.Pool().QueryRow(ctx, sql,
+		comment.PostId,
+		comment.AuthorId,
+		comment.ParentId,
+		comment.Body,
+		comment.Status,
+		comment.SpamScore,
+		comment.SpamReasons,
+		comment.CreatedAt,
+		comment.UpdatedAt,
+	).Scan(&returnedID)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return returnedID, nil
+}
+
+// FindPostComments returns the approved comments of the post, along with
+// those of viewerId whatever their status, with their depths. Parents come
+// before their replies, so that callers can assemble the threads in a single
+// pass, and replies to hidden comments are hidden with them.
+func (r CommentsRepository) FindPostComments(ctx context.Context, postId, viewerId uuid.UUID) ([]models.Comment, error) {
+	sql := `WITH RECURSIVE thread AS (
+		SELECT ` + commentColumns + `, 0 AS depth FROM ` + r.tableName + `
+		WHERE post_id = $1 AND parent_id IS NULL AND (status = $2 OR author_id = $3)
+		UNION ALL
+		SELECT c.id, c.post_id, c.author_id, c.parent_id, c.body, c.status, c.spam_score, c.spam_reasons,
+			c.moderated_by, c.moderated_at, c.created_at, c.updated_at, thread.depth + 1
+		FROM ` + r.tableName + ` c JOIN thread ON c.parent_id = thread.id
+		WHERE c.status = $2 OR c.author_id = $3
+	)
+	SELECT ` + commentColumns + `, depth FROM thread ORDER BY depth, created_at, id`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, postId, models.CommentStatusApproved, viewerId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		var depth int
+		comment, err := scanComment(rows, &depth)
+		if err != nil {
+			return nil, err
+		}
+
+		comment.Depth = depth
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return comments, nil
+}
+
+// FindCommentsByStatus returns the comments in status, oldest first, which
+// is the order moderators work through them.
+func (r CommentsRepository) FindCommentsByStatus(ctx context.Context, status models.CommentStatus, limit, offset int) ([]models.Comment, error) {
+	sql := `SELECT ` + commentColumns + `
+	FROM ` + r.tableName + ` WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, status, limit, offset)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return comments, nil
+}
+
+// CountApprovedCommentsByAuthor tells first-time commenters, who have no
+// approved comment yet, apart.
+func (r CommentsRepository) CountApprovedCommentsByAuthor(ctx context.Context, authorId uuid.UUID) (int, error) {
+	sql := `SELECT count(*) FROM ` + r.tableName + ` WHERE author_id = $1 AND status = $2`
+
+	var count int
+	err := r.conn.Pool().QueryRow(ctx, sql, authorId, models.CommentStatusApproved).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
+}
+
+// CountCommentsByAuthorSince counts the comments authorId posted at or after
+// since, whatever their status, but for the one with excludeId.
+func (r CommentsRepository) CountCommentsByAuthorSince(ctx context.Context, authorId, excludeId uuid.UUID, since time.Time) (int, error) {
+	sql := `SELECT count(*) FROM ` + r.tableName + ` WHERE author_id = $1 AND created_at >= $2 AND id <> $3`
+
+	var count int
+	err := r.conn.Pool().QueryRow(ctx, sql, authorId, since, excludeId).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
+}
+
+// CountDuplicateComments counts the comments posted at or after since, by
+// anyone, whose bodies equal body but for case and surrounding spaces, but for
+// the one with excludeId.
+func (r CommentsRepository) CountDuplicateComments(ctx context.Context, body string, excludeId uuid.UUID, since time.Time) (int, error) {
+	sql := `SELECT count(*) FROM ` + r.tableName + ` WHERE created_at >= $1 AND lower(btrim(body)) = lower(btrim($2)) AND id <> $3`
+
+	var count int
+	err := r.conn.Pool().QueryRow(ctx, sql, since, body, excludeId).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
+}
+
+func (r CommentsRepository) FindCommentById(ctx context.Context, id uuid.UUID) (models.Comment, error) {
+	sql := `SELECT ` + commentColumns + `
+	FROM ` + r.tableName + ` WHERE id = $1`
+
+	return scanComment(r.conn.Pool().QueryRow(ctx, sql, id))
+}
+
+// UpdateCommentById stores the body of the comment along with its spam score
+// and status, which depend on the body.
+func (r CommentsRepository) UpdateCommentById(ctx context.Context, id uuid.UUID, comment models.Comment) error {
+	if comment.SpamReasons == nil {
+		comment.SpamReasons = []string{}
+	}
+
+	sql := `UPDATE ` + r.tableName + ` SET
+		body = $1,
+		status = $2,
+		spam_score = $3,
+		spam_reasons = $4,
+		updated_at = $5
+	WHERE id = $6`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql,
+		comment.Body,
+		comment.Status,
+		comment.SpamScore,
+		comment.SpamReasons,
+		r.timeProvider.Now().UTC(),
+		id,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateCommentStatusById moves the comment to comment.Status on behalf of
+// comment.ModeratedBy, but only while it is still in status from. It returns
+// domain.ErrNotFound otherwise, so concurrent moderators cannot both succeed.
+func (r CommentsRepository) UpdateCommentStatusById(ctx context.Context, id uuid.UUID, from models.CommentStatus, comment models.Comment) error {
+	sql := `UPDATE ` + r.tableName + ` SET
+		status = $1,
+		moderated_by = $2,
+		moderated_at = $3
+	WHERE id = $4 AND status = $5`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql,
+		comment.Status,
+		comment.ModeratedBy,
+		r.timeProvider.Now().UTC(),
+		id,
+		from,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCommentById deletes the comment along with every reply below it.
+func (r CommentsRepository) DeleteCommentById(ctx context.Context, id uuid.UUID) error {
+	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql, id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// scanComment scans a row of commentColumns, followed by the columns scanned
+// into extra, if any.
+func scanComment(row pgx.Row, extra ...any) (models.Comment, error) {
+	var comment models.Comment
+	dest := []any{
+		&comment.Id,
+		&comment.PostId,
+		&comment.AuthorId,
+		&comment.ParentId,
+		&comment.Body,
+		&comment.Status,
+		&comment.SpamScore,
+		&comment.SpamReasons,
+		&comment.ModeratedBy,
+		&comment.ModeratedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return models.Comment{}, translateError(err)
+	}
+
+	comment.CreatedAt = comment.CreatedAt.UTC()
+	comment.UpdatedAt = comment.UpdatedAt.UTC()
+	if comment.ModeratedAt != nil {
+		comment.ModeratedAt = utils.Ptr(comment.ModeratedAt.UTC())
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,246 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	firstPostId    = uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	bobCommentId   = uuid.MustParse("c0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a70")
+	aliceReplyId   = uuid.MustParse("c1b2c3d4-e5f6-4a70-9b2c-3d4e5f6a7b81")
+	aliceId        = uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+	bobId          = uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db")
+	helloWorldId   = uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+	charlieId      = uuid.MustParse("2cdc1c8f-9985-4b6c-b007-038a5bef22b5")
+	pendingId      = uuid.MustParse("c2c3d4e5-f6a7-4b80-8c3d-4e5f6a7b8c92")
+	unknownComment = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+)
+
+type commentsTestsSuite struct {
+	suite.Suite
+	commentsRepo *repositories.CommentsRepository
+}
+
+// This will run before running the suite
+func (s *commentsTestsSuite) SetupSuite() {
+	s.commentsRepo = repositories.NewCommentsRepository(PostgresConn, utils.MockClock{})
+}
+
+// This will run after each test
+func (s *commentsTestsSuite) TearDownTest() {
+	err := PostgresContainer.Restore(context.TODO())
+	require.NoError(s.T(), err)
+	PostgresConn.Pool().Reset()
+}
+
+func TestCommentsRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(commentsTestsSuite))
+}
+
+func (s *commentsTestsSuite) TestCreateComment() {
+	t := s.T()
+
+	id, err := s.commentsRepo.CreateComment(context.TODO(), models.Comment{
+		PostId:   firstPostId,
+		AuthorId: bobId,
+		ParentId: &aliceReplyId,
+		Body:     "You're welcome!",
+	})
+	require.NoError(t, err)
+
+	comment, err := s.commentsRepo.FindCommentById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, &aliceReplyId, comment.ParentId)
+	assert.Equal(t, "You're welcome!", comment.Body)
+	assert.Equal(t, models.CommentStatusPending, comment.Status)
+	assert.Equal(t, []string{}, comment.SpamReasons)
+
+	// Replies must stay on the post of their parent.
+	_, err = s.commentsRepo.CreateComment(context.TODO(), models.Comment{
+		PostId:   helloWorldId,
+		AuthorId: bobId,
+		ParentId: &bobCommentId,
+		Body:     "Wrong thread",
+	})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{"parent_id": "does not exist"}, domain.Fields(err))
+
+	_, err = s.commentsRepo.CreateComment(context.TODO(), models.Comment{
+		PostId:   firstPostId,
+		AuthorId: bobId,
+		Body:     "",
+	})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func (s *commentsTestsSuite) TestFindPostComments() {
+	t := s.T()
+
+	replyId, err := s.commentsRepo.CreateComment(context.TODO(), models.Comment{
+		PostId:   firstPostId,
+		AuthorId: bobId,
+		ParentId: &aliceReplyId,
+		Body:     "You're welcome!",
+		Status:   models.CommentStatusApproved,
+	})
+	require.NoError(t, err)
+
+	comments, err := s.commentsRepo.FindPostComments(context.TODO(), firstPostId, uuid.Nil)
+	require.NoError(t, err)
+	if assert.Len(t, comments, 3) {
+		assert.Equal(t, bobCommentId, comments[0].Id)
+		assert.Equal(t, 0, comments[0].Depth)
+		assert.Equal(t, aliceReplyId, comments[1].Id)
+		assert.Equal(t, 1, comments[1].Depth)
+		assert.Equal(t, replyId, comments[2].Id)
+		assert.Equal(t, 2, comments[2].Depth)
+		assert.Equal(t, time.Date(2006, time.January, 2, 0, 1, 0, 0, time.UTC), comments[1].CreatedAt)
+	}
+
+	// Charlie sees his pending comment, which nobody else does.
+	comments, err = s.commentsRepo.FindPostComments(context.TODO(), firstPostId, charlieId)
+	require.NoError(t, err)
+	if assert.Len(t, comments, 4) {
+		assert.Equal(t, pendingId, comments[1].Id)
+		assert.Equal(t, models.CommentStatusPending, comments[1].Status)
+	}
+
+	comments, err = s.commentsRepo.FindPostComments(context.TODO(), helloWorldId, uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func (s *commentsTestsSuite) TestModeration() {
+	t := s.T()
+
+	comments, err := s.commentsRepo.FindCommentsByStatus(context.TODO(), models.CommentStatusPending, 10, 0)
+	require.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, pendingId, comments[0].Id)
+	}
+
+	// Rejecting a comment hides its replies along with it.
+	err = s.commentsRepo.UpdateCommentStatusById(context.TODO(), bobCommentId, models.CommentStatusApproved, models.Comment{
+		Status:      models.CommentStatusRejected,
+		ModeratedBy: &aliceId,
+	})
+	require.NoError(t, err)
+
+	comment, err := s.commentsRepo.FindCommentById(context.TODO(), bobCommentId)
+	require.NoError(t, err)
+	assert.Equal(t, models.CommentStatusRejected, comment.Status)
+	assert.Equal(t, &aliceId, comment.ModeratedBy)
+	assert.NotNil(t, comment.ModeratedAt)
+
+	comments, err = s.commentsRepo.FindPostComments(context.TODO(), firstPostId, uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+
+	// The comment is no longer approved.
+	err = s.commentsRepo.UpdateCommentStatusById(context.TODO(), bobCommentId, models.CommentStatusApproved, models.Comment{
+		Status: models.CommentStatusRejected,
+	})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *commentsTestsSuite) TestCommentHistory() {
+	t := s.T()
+
+	count, err := s.commentsRepo.CountApprovedCommentsByAuthor(context.TODO(), bobId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = s.commentsRepo.CountApprovedCommentsByAuthor(context.TODO(), charlieId)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = s.commentsRepo.CountCommentsByAuthorSince(context.TODO(), aliceId, uuid.Nil, time.Date(2006, time.January, 2, 0, 1, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = s.commentsRepo.CountCommentsByAuthorSince(context.TODO(), bobId, uuid.Nil, time.Date(2006, time.January, 2, 0, 1, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = s.commentsRepo.CountDuplicateComments(context.TODO(), "  great POST! ", uuid.Nil, time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// An edited comment does not count against itself.
+	count, err = s.commentsRepo.CountCommentsByAuthorSince(context.TODO(), bobId, bobCommentId, time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = s.commentsRepo.CountDuplicateComments(context.TODO(), "Great post!", bobCommentId, time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func (s *commentsTestsSuite) TestUpdateCommentById() {
+	t := s.T()
+
+	err := s.commentsRepo.UpdateCommentById(context.TODO(), bobCommentId, models.Comment{
+		Body:        "Great post, thanks! https://example.com",
+		Status:      models.CommentStatusPending,
+		SpamScore:   0.5,
+		SpamReasons: []string{"link_density"},
+	})
+	require.NoError(t, err)
+
+	comment, err := s.commentsRepo.FindCommentById(context.TODO(), bobCommentId)
+	require.NoError(t, err)
+	assert.Equal(t, "Great post, thanks! https://example.com", comment.Body)
+	assert.Equal(t, models.CommentStatusPending, comment.Status)
+	assert.Equal(t, 0.5, comment.SpamScore)
+	assert.Equal(t, []string{"link_density"}, comment.SpamReasons)
+
+	err = s.commentsRepo.UpdateCommentById(context.TODO(), unknownComment, models.Comment{Body: "Nope", Status: models.CommentStatusApproved})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *commentsTestsSuite) TestDeleteCommentById() {
+	t := s.T()
+
+	err := s.commentsRepo.DeleteCommentById(context.TODO(), bobCommentId)
+	require.NoError(t, err)
+
+	// Replies go away with the comment they answer.
+	_, err = s.commentsRepo.FindCommentById(context.TODO(), aliceReplyId)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = s.commentsRepo.DeleteCommentById(context.TODO(), bobCommentId)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *commentsTestsSuite) TestCascades() {
+	t := s.T()
+
+	_, err := PostgresConn.Pool().Exec(context.TODO(), `DELETE FROM users WHERE id = $1`, bobId)
+	require.NoError(t, err)
+
+	// Deleting Bob takes his comment and the reply of Alice to it along.
+	comments, err := s.commentsRepo.FindPostComments(context.TODO(), firstPostId, aliceId)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+
+	_, err = s.commentsRepo.CreateComment(context.TODO(), models.Comment{PostId: firstPostId, AuthorId: aliceId, Body: "Anyone?", Status: models.CommentStatusApproved})
+	require.NoError(t, err)
+
+	_, err = PostgresConn.Pool().Exec(context.TODO(), `DELETE FROM posts WHERE id = $1`, firstPostId)
+	require.NoError(t, err)
+
+	comments, err = s.commentsRepo.FindPostComments(context.TODO(), firstPostId, aliceId)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgStringTooLong       = "22001"
+	pgInvalidText         = "22P02"
+)
+
+// translateError converts pgx and Postgres errors into domain errors so that
+// no SQL details leak past the repositories. Unknown errors are returned as is.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		field := constraintField(pgErr)
+		return domain.FieldsErrorf(domain.ErrConflict, map[string]string{field: "already exists"}, "%s already exists", field)
+	case pgForeignKeyViolation:
+		field := constraintField(pgErr)
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{field: "does not exist"}, "referenced %s does not exist", field)
+	case pgCheckViolation, pgNotNullViolation:
+		field := constraintField(pgErr)
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{field: "is invalid"}, "invalid %s", field)
+	case pgStringTooLong:
+		return domain.Errorf(domain.ErrValidation, "value too long")
+	case pgInvalidText:
+		return domain.Errorf(domain.ErrValidation, "invalid value")
+	}
+
+	return err
+}
+
+// constraintField guesses the column behind a constraint from its name, which
+// follows the Postgres default of <table>_<column>_<suffix>.
+func constraintField(pgErr *pgconn.PgError) string {
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+
+	name := strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_")
+	for _, suffix := range []string{"_key", "_fkey", "_check", "_idx"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+
+	if name == "" {
+		return "value"
+	}
+
+	return name
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gera9/blog/internal/models"
+)
+
+// pageClauses returns the condition, if any, and the trailing ORDER BY and
+// LIMIT clauses reading page of a listing ordered newest first, along with
+// their arguments numbered from firstArg. Keyset pages compare on
+// (created_at, id), which the listed tables index. The rows of pages before a
+// keyset come oldest first and must go through orderPage. The sorts of the
+// page are looked up in columns.
+func pageClauses(page models.Page, columns fieldColumns, firstArg int) (condition, tail string, args []any, err error) {
+	switch {
+	case len(page.Sort) > 0:
+		terms, err := columns.orderBy(page.Sort)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		tail = fmt.Sprintf(" ORDER BY %s, created_at DESC, id DESC LIMIT $%d OFFSET $%d", strings.Join(terms, ", "), firstArg, firstArg+1)
+		return "", tail, []any{page.Limit, page.Offset}, nil
+	case page.After != nil:
+		condition = fmt.Sprintf("(created_at, id) < ($%d, $%d)", firstArg, firstArg+1)
+		tail = fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", firstArg+2)
+		return condition, tail, []any{page.After.CreatedAt, page.After.Id, page.Limit}, nil
+	case page.Before != nil:
+		condition = fmt.Sprintf("(created_at, id) > ($%d, $%d)", firstArg, firstArg+1)
+		tail = fmt.Sprintf(" ORDER BY created_at ASC, id ASC LIMIT $%d", firstArg+2)
+		return condition, tail, []any{page.Before.CreatedAt, page.Before.Id, page.Limit}, nil
+	}
+
+	tail = fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", firstArg, firstArg+1)
+	return "", tail, []any{page.Limit, page.Offset}, nil
+}
+
+// orderPage puts the rows read with pageClauses newest first.
+func orderPage[T any](page models.Page, rows []T) []T {
+	if page.Before != nil && len(page.Sort) == 0 {
+		slices.Reverse(rows)
+	}
+
+	return rows
+}
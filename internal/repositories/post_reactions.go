@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// SetPostReaction makes kind the reaction of the user to the post, replacing
+// any other reaction of theirs. The reaction counts are kept up to date by
+// the database.
+func (r PostsRepository) SetPostReaction(ctx context.Context, postId, userId uuid.UUID, kind models.ReactionKind) error {
+	sql := `INSERT INTO ` + r.reactionsTableName + ` (post_id, user_id, kind, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (post_id, user_id) DO UPDATE SET kind = EXCLUDED.kind, created_at = EXCLUDED.created_at
+	WHERE ` + r.reactionsTableName + `.kind <> EXCLUDED.kind`
+
+	_, err := r.conn.Pool().Exec(ctx, sql, postId, userId, kind, r.timeProvider.Now().UTC())
+	return translateError(err)
+}
+
+// DeletePostReaction removes the reaction of the user to the post, which is
+// reported as not found unless it is of the given kind.
+func (r PostsRepository) DeletePostReaction(ctx context.Context, postId, userId uuid.UUID, kind models.ReactionKind) error {
+	sql := `DELETE FROM ` + r.reactionsTableName + ` WHERE post_id = $1 AND user_id = $2 AND kind = $3`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql, postId, userId, kind)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Postgres highlights matches in snippets with these private use characters,
+// which are turned into <mark> elements once the rest of the snippet has been
+// escaped.
+const (
+	snippetStartSel = "\uE000"
+	snippetStopSel  = "\uE001"
+)
+
+var snippetOptions = fmt.Sprintf(
+	`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`,
+	snippetStartSel, snippetStopSel,
+)
+
+// SearchPosts returns the posts matching filter whose title, extract or
+// content match tsQuery, a query in the syntax of to_tsquery, best matches
+// first.
+func (r PostsRepository) SearchPosts(ctx context.Context, tsQuery string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error) {
+	conditions, args, err := postFilterConditions(filter, 5)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append([]string{"search_vector @@ query"}, conditions...)
+
+	sql := `SELECT ` + postColumns + `,
+		ts_rank_cd(search_vector, query)::float8 AS rank,
+		ts_headline('english', COALESCE(content_text, ''), query, $4)
+	FROM ` + r.tableName + `, to_tsquery('english', $3) AS query
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY rank DESC, created_at DESC, id LIMIT $1 OFFSET $2`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, append([]any{limit, offset, tsQuery, snippetOptions}, args...)...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return scanPostSearchResults(rows, models.PostMatchFullText)
+}
+
+// FindPostsBySimilarTitle returns the posts matching filter whose titles
+// contain words resembling those of text, most similar first. Their extracts
+// serve as snippets.
+func (r PostsRepository) FindPostsBySimilarTitle(ctx context.Context, text string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error) {
+	conditions, args, err := postFilterConditions(filter, 4)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append([]string{"$3 <% title"}, conditions...)
+
+	sql := `SELECT ` + postColumns + `,
+		word_similarity($3, title)::float8 AS rank,
+		extract
+	FROM ` + r.tableName + `
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY rank DESC, created_at DESC, id LIMIT $1 OFFSET $2`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, append([]any{limit, offset, text}, args...)...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return scanPostSearchResults(rows, models.PostMatchSimilarTitle)
+}
+
+func scanPostSearchResults(rows pgx.Rows, match models.PostMatch) ([]models.PostSearchResult, error) {
+	defer rows.Close()
+
+	results := make([]models.PostSearchResult, 0)
+	for rows.Next() {
+		result := models.PostSearchResult{Match: match}
+
+		var err error
+		result.Post, err = scanPost(rows, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Snippet = snippetHTML(result.Snippet)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return results, nil
+}
+
+// snippetHTML escapes snippet and turns its highlights into <mark> elements.
+func snippetHTML(snippet string) string {
+	return strings.NewReplacer(
+		snippetStartSel, "<mark>",
+		snippetStopSel, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
@@ -2,24 +2,78 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
 	"github.com/gera9/blog/pkg/postgres"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
+// postField is a field of posts, named as responses name it, along with the
+// SQL expression selecting it and where it is scanned.
+type postField struct {
+	name   string
+	column string
+	dest   func(post *models.Post) any
+}
+
+// postFields select a post along with the slugs of its tags and its reaction
+// counts, the latter read from the counters the database maintains.
+var postFields = []postField{
+	{"id", "id", func(p *models.Post) any { return &p.Id }},
+	{"title", "title", func(p *models.Post) any { return &p.Title }},
+	{"slug", "slug", func(p *models.Post) any { return &p.Slug }},
+	{"extract", "extract", func(p *models.Post) any { return &p.Extract }},
+	{"content", "content", func(p *models.Post) any { return &p.Content }},
+	{"content_html", "COALESCE(content_html, '')", func(p *models.Post) any { return &p.ContentHTML }},
+	{"word_count", "word_count", func(p *models.Post) any { return &p.WordCount }},
+	{"reading_time_minutes", "reading_minutes", func(p *models.Post) any { return &p.ReadingMinutes }},
+	{"toc", "toc", func(p *models.Post) any { return &p.Toc }},
+	{"author_id", "author_id", func(p *models.Post) any { return &p.AuthorId }},
+	{"category_id", "category_id", func(p *models.Post) any { return &p.CategoryId }},
+	{"tags", "(SELECT COALESCE(array_agg(t.slug ORDER BY t.slug), '{}') FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = posts.id) AS tags", func(p *models.Post) any { return &p.Tags }},
+	{"comment_mode", "comment_mode", func(p *models.Post) any { return &p.CommentMode }},
+	{"reactions", "(SELECT COALESCE(jsonb_object_agg(rc.kind, rc.count), '{}') FROM post_reaction_counts rc WHERE rc.post_id = posts.id AND rc.count > 0) AS reactions", func(p *models.Post) any { return &p.Reactions }},
+	{"status", "status", func(p *models.Post) any { return &p.Status }},
+	{"published_at", "published_at", func(p *models.Post) any { return &p.PublishedAt }},
+	{"scheduled_at", "scheduled_at", func(p *models.Post) any { return &p.ScheduledAt }},
+	{"created_at", "created_at", func(p *models.Post) any { return &p.CreatedAt }},
+	{"updated_at", "updated_at", func(p *models.Post) any { return &p.UpdatedAt }},
+}
+
+var postColumns = joinPostColumns(postFields)
+
+// postAuthorJoin joins the public profile of the author of each post under
+// column names that cannot clash with those of posts.
+const postAuthorJoin = ` JOIN (SELECT id, first_name, last_name, username, created_at FROM users)
+	AS author (author_user_id, author_first_name, author_last_name, author_username, author_created_at)
+	ON author.author_user_id = posts.author_id`
+
+const postAuthorColumns = `author_user_id, author_first_name, author_last_name, author_username, author_created_at`
+
 type PostsRepository struct {
-	conn         *postgres.Postgres
-	timeProvider utils.TimeProvider
-	tableName    string
+	conn                 *postgres.Postgres
+	timeProvider         utils.TimeProvider
+	tableName            string
+	slugHistoryTableName string
+	revisionsTableName   string
+	reactionsTableName   string
 }
 
 func NewPostsRepository(conn *postgres.Postgres, timeProvider utils.TimeProvider) *PostsRepository {
 	return &PostsRepository{
-		conn:         conn,
-		timeProvider: timeProvider,
-		tableName:    "posts",
+		conn:                 conn,
+		timeProvider:         timeProvider,
+		tableName:            "posts",
+		slugHistoryTableName: "post_slug_history",
+		revisionsTableName:   "post_revisions",
+		reactionsTableName:   "post_reactions",
 	}
 }
 
@@ -31,122 +85,701 @@ func (r PostsRepository) CreatePost(ctx context.Context, post models.Post) (uuid
 	if post.UpdatedAt.IsZero() {
 		post.UpdatedAt = now
 	}
+	if post.Status == "" {
+		post.Status = models.PostStatusDraft
+	}
+	if post.CommentMode == "" {
+		post.CommentMode = models.CommentModeOpen
+	}
+
+	tx, err := r.conn.Pool().Begin(ctx)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+	defer tx.Rollback(ctx)
 
 	sql := `INSERT INTO ` + r.tableName + ` (
-		title, extract, content, author_id, created_at, updated_at
-	) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`
+		title, slug, extract, content, content_html, content_text, word_count, reading_minutes, toc,
+		author_id, category_id, comment_mode, status, published_at, scheduled_at, created_at, updated_at
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17) RETURNING id`
 
 	var returnedID uuid.UUID
-	err := r.conn.Pool().QueryRow(ctx, sql,
+	err = tx.QueryRow(ctx, sql,
 		post.Title,
+		post.Slug,
 		post.Extract,
 		post.Content,
+		post.ContentHTML,
+		post.ContentText,
+		post.WordCount,
+		post.ReadingMinutes,
+		tocValue(post.Toc),
 		post.AuthorId,
+		post.CategoryId,
+		post.CommentMode,
+		post.Status,
+		post.PublishedAt,
+		post.ScheduledAt,
 		post.CreatedAt,
 		post.UpdatedAt,
 	).Scan(&returnedID)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	err = setPostTags(ctx, tx, returnedID, post.Tags)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	return returnedID, nil
+	err = r.addRevision(ctx, tx, returnedID, post, post.CreatedAt)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return returnedID, translateError(tx.Commit(ctx))
 }
 
-func (r PostsRepository) FindAllPosts(ctx context.Context, limit, offset int, authorId uuid.UUID) ([]models.Post, error) {
-	sql := `SELECT id, title, extract, content, author_id, created_at, updated_at
-	FROM ` + r.tableName + ` WHERE author_id = $3 ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+// FindAllPosts lists the posts matching filter, loading what projection
+// asks for.
+func (r PostsRepository) FindAllPosts(ctx context.Context, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, error) {
+	fields, err := selectPostFields(projection.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, args, err := postFilterConditions(filter, 1)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.conn.Pool().Query(ctx, sql, limit, offset, authorId)
+	condition, tail, pageArgs, err := pageClauses(page, postFieldColumns, len(args)+1)
 	if err != nil {
 		return nil, err
 	}
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, pageArgs...)
+
+	columns, from := joinPostColumns(fields), r.tableName
+	if projection.Author {
+		columns += ", " + postAuthorColumns
+		from += postAuthorJoin
+	}
+
+	sql := `SELECT ` + columns + `
+	FROM ` + from + whereClause(conditions) + tail
+
+	rows, err := r.conn.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	defer rows.Close()
 
 	posts := make([]models.Post, 0)
 	for rows.Next() {
-		var post models.Post
-		err := rows.Scan(
-			&post.Id,
-			&post.Title,
-			&post.Extract,
-			&post.Content,
-			&post.AuthorId,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
+		var author models.User
+		var extra []any
+		if projection.Author {
+			extra = []any{&author.Id, &author.FirstName, &author.LastName, &author.Username, &author.CreatedAt}
+		}
+
+		post, err := scanPostFields(rows, fields, extra...)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err)
 		}
 
-		post.CreatedAt = post.CreatedAt.UTC()
-		post.UpdatedAt = post.UpdatedAt.UTC()
+		if projection.Author {
+			author.CreatedAt = author.CreatedAt.UTC()
+			post.Author = &author
+		}
 
 		posts = append(posts, post)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
-	return posts, nil
+	return orderPage(page, posts), nil
+}
+
+// CountPosts counts the posts matching filter.
+func (r PostsRepository) CountPosts(ctx context.Context, filter models.PostFilter) (int, error) {
+	conditions, args, err := postFilterConditions(filter, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	sql := `SELECT count(*) FROM ` + r.tableName + whereClause(conditions)
+
+	var count int
+	err = r.conn.Pool().QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
+}
+
+func (r PostsRepository) FindPostById(ctx context.Context, id uuid.UUID) (models.Post, error) {
+	sql := `SELECT ` + postColumns + `
+	FROM ` + r.tableName + ` WHERE id = $1`
+
+	return scanPost(r.conn.Pool().QueryRow(ctx, sql, id))
 }
 
 func (r PostsRepository) FindPostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID) (models.Post, error) {
-	sql := `SELECT id, title, extract, content, author_id, created_at, updated_at
+	sql := `SELECT ` + postColumns + `
 	FROM ` + r.tableName + ` WHERE id = $1 AND author_id = $2`
 
-	var post models.Post
-	err := r.conn.Pool().QueryRow(ctx, sql, id, authorId).Scan(
-		&post.Id,
-		&post.Title,
-		&post.Extract,
-		&post.Content,
-		&post.AuthorId,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-	)
+	return scanPost(r.conn.Pool().QueryRow(ctx, sql, id, authorId))
+}
+
+// FindPostBySlug returns the post whose current slug is slug among the posts
+// of the user with the given username.
+func (r PostsRepository) FindPostBySlug(ctx context.Context, username, slug string) (models.Post, error) {
+	sql := `SELECT ` + postColumns + `
+	FROM ` + r.tableName + `
+	WHERE author_id = (SELECT id FROM users WHERE username = $1) AND slug = $2`
+
+	return scanPost(r.conn.Pool().QueryRow(ctx, sql, username, slug))
+}
+
+// FindPostByOldSlug returns the post that used to be reachable through slug
+// among the posts of the user with the given username.
+func (r PostsRepository) FindPostByOldSlug(ctx context.Context, username, slug string) (models.Post, error) {
+	sql := `SELECT ` + postColumns + `
+	FROM ` + r.tableName + `
+	WHERE id = (
+		SELECT h.post_id FROM ` + r.slugHistoryTableName + ` h
+		JOIN users u ON u.id = h.author_id
+		WHERE u.username = $1 AND h.slug = $2
+	)`
+
+	return scanPost(r.conn.Pool().QueryRow(ctx, sql, username, slug))
+}
+
+// FindTakenSlugs returns the current and former slugs of the posts of
+// authorId, other than excludeId, that are base or base followed by a suffix.
+func (r PostsRepository) FindTakenSlugs(ctx context.Context, authorId uuid.UUID, base string, excludeId uuid.UUID) ([]string, error) {
+	sql := `SELECT slug FROM ` + r.tableName + `
+		WHERE author_id = $1 AND id <> $3 AND (slug = $2 OR starts_with(slug, $2 || '-'))
+	UNION
+	SELECT slug FROM ` + r.slugHistoryTableName + `
+		WHERE author_id = $1 AND post_id <> $3 AND (slug = $2 OR starts_with(slug, $2 || '-'))`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, authorId, base, excludeId)
 	if err != nil {
-		return models.Post{}, err
+		return nil, translateError(err)
 	}
 
-	post.CreatedAt = post.CreatedAt.UTC()
-	post.UpdatedAt = post.UpdatedAt.UTC()
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, translateError(err)
+	}
 
-	return post, nil
+	return slugs, nil
 }
 
+// UpdatePostByIdAndAuthorId only touches the post while it still belongs to
+// authorId and returns domain.ErrNotFound otherwise. It also only applies
+// while the post is still at the version it was read at, post.UpdatedAt, so
+// that concurrent updates cannot overwrite each other, and fails with
+// domain.ErrModified otherwise. When the slug changes the previous
+// one is kept in the slug history so that old links still resolve.
 func (r PostsRepository) UpdatePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, post models.Post) error {
+	tx, err := r.conn.Pool().Begin(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var old models.Post
+	err = tx.QueryRow(ctx,
+		`SELECT title, slug, extract, content, updated_at FROM `+r.tableName+` WHERE id = $1 AND author_id = $2 FOR UPDATE`,
+		id, authorId,
+	).Scan(&old.Title, &old.Slug, &old.Extract, &old.Content, &old.UpdatedAt)
+	if err != nil {
+		return translateError(err)
+	}
+
+	// The row is locked until the update commits, so checking its version
+	// here is as good as conditioning the update on it.
+	if !old.UpdatedAt.Equal(post.UpdatedAt) {
+		return domain.ErrModified
+	}
+
+	if post.Slug != old.Slug {
+		_, err = tx.Exec(ctx, `INSERT INTO `+r.slugHistoryTableName+` (author_id, slug, post_id, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (author_id, slug) DO UPDATE SET post_id = EXCLUDED.post_id, created_at = EXCLUDED.created_at`,
+			authorId, old.Slug, id, r.timeProvider.Now().UTC(),
+		)
+		if err != nil {
+			return translateError(err)
+		}
+
+		// A post taking back one of its former slugs no longer redirects it.
+		_, err = tx.Exec(ctx, `DELETE FROM `+r.slugHistoryTableName+` WHERE author_id = $1 AND slug = $2`, authorId, post.Slug)
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
+	now := r.timeProvider.Now().UTC()
+
 	sql := `UPDATE ` + r.tableName + ` SET
 		title = $1,
-		extract = $2,
-		content = $3,
-		author_id = $4,
-		updated_at = $5
-	WHERE id = $6 AND author_id = $7`
+		slug = $2,
+		extract = $3,
+		content = $4,
+		content_html = $5,
+		content_text = $6,
+		word_count = $7,
+		reading_minutes = $8,
+		toc = $9,
+		author_id = $10,
+		category_id = $11,
+		comment_mode = COALESCE(NULLIF($12, ''), comment_mode),
+		updated_at = $13
+	WHERE id = $14 AND author_id = $15`
 
-	_, err := r.conn.Pool().Exec(ctx, sql,
+	tag, err := tx.Exec(ctx, sql,
 		post.Title,
+		post.Slug,
 		post.Extract,
 		post.Content,
+		post.ContentHTML,
+		post.ContentText,
+		post.WordCount,
+		post.ReadingMinutes,
+		tocValue(post.Toc),
 		post.AuthorId,
-		r.timeProvider.Now().UTC(),
+		post.CategoryId,
+		post.CommentMode,
+		now,
 		id,
 		authorId,
 	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	err = setPostTags(ctx, tx, id, post.Tags)
 	if err != nil {
 		return err
 	}
 
+	// Only edits of the text are worth a revision.
+	if post.Title != old.Title || post.Extract != old.Extract || post.Content != old.Content {
+		err = r.addRevision(ctx, tx, id, post, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return translateError(tx.Commit(ctx))
+}
+
+// addRevision records the text of post as the next revision of the post
+// identified by id. Callers must hold a lock on the post so revision numbers
+// cannot be handed out twice.
+func (r PostsRepository) addRevision(ctx context.Context, tx pgx.Tx, id uuid.UUID, post models.Post, at time.Time) error {
+	sql := `INSERT INTO ` + r.revisionsTableName + ` (post_id, revision, title, extract, content, created_at)
+	SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+	FROM ` + r.revisionsTableName + ` WHERE post_id = $1`
+
+	_, err := tx.Exec(ctx, sql, id, post.Title, post.Extract, post.Content, at)
+	return translateError(err)
+}
+
+// FindPostRevisions lists the revisions of the post, newest first.
+func (r PostsRepository) FindPostRevisions(ctx context.Context, postId uuid.UUID) ([]models.PostRevision, error) {
+	sql := `SELECT post_id, revision, title, extract, content, created_at
+	FROM ` + r.revisionsTableName + ` WHERE post_id = $1 ORDER BY revision DESC`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, postId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	revisions := make([]models.PostRevision, 0)
+	for rows.Next() {
+		revision, err := scanPostRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return revisions, nil
+}
+
+func (r PostsRepository) FindPostRevision(ctx context.Context, postId uuid.UUID, revision int) (models.PostRevision, error) {
+	sql := `SELECT post_id, revision, title, extract, content, created_at
+	FROM ` + r.revisionsTableName + ` WHERE post_id = $1 AND revision = $2`
+
+	return scanPostRevision(r.conn.Pool().QueryRow(ctx, sql, postId, revision))
+}
+
+// UpdatePostStatusById moves the post to post.Status and stores its
+// post.PublishedAt and post.ScheduledAt, but only while it is still in status
+// from. It returns domain.ErrNotFound otherwise, so concurrent transitions
+// cannot both succeed.
+func (r PostsRepository) UpdatePostStatusById(ctx context.Context, id uuid.UUID, from models.PostStatus, post models.Post) error {
+	sql := `UPDATE ` + r.tableName + ` SET
+		status = $1,
+		published_at = $2,
+		scheduled_at = $3,
+		updated_at = $4
+	WHERE id = $5 AND status = $6`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql,
+		post.Status,
+		post.PublishedAt,
+		post.ScheduledAt,
+		r.timeProvider.Now().UTC(),
+		id,
+		from,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// PublishDuePosts publishes at most limit unpublished posts scheduled at or
+// before now and returns how many it published. Rows locked by another
+// replica running the same query are skipped rather than waited on, so every
+// due post is published exactly once.
+func (r PostsRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error) {
+	sql := `WITH due AS (
+		SELECT id FROM ` + r.tableName + `
+		WHERE scheduled_at <= $1 AND status IN ($2, $3)
+		ORDER BY scheduled_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE ` + r.tableName + ` AS p SET
+		status = $5,
+		published_at = $1,
+		scheduled_at = NULL,
+		updated_at = $1
+	FROM due WHERE p.id = due.id`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql,
+		now.UTC(),
+		models.PostStatusDraft,
+		models.PostStatusInReview,
+		limit,
+		models.PostStatusPublished,
+	)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// FindUnrenderedPosts returns at most limit posts whose content has never
+// been rendered, such as those written before rendering existed.
+func (r PostsRepository) FindUnrenderedPosts(ctx context.Context, limit int) ([]models.Post, error) {
+	sql := `SELECT ` + postColumns + `
+	FROM ` + r.tableName + ` WHERE content_html IS NULL ORDER BY id LIMIT $1`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, limit)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	posts := make([]models.Post, 0)
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return posts, nil
+}
+
+// UpdatePostRenderingById stores what was derived from the content of the
+// post, extract included. It is not an edit, so updated_at is left alone. The
+// post must still be unrendered and last updated at post.UpdatedAt, so that
+// what was derived from stale content never overwrites a later edit; it is
+// reported as not found otherwise.
+func (r PostsRepository) UpdatePostRenderingById(ctx context.Context, id uuid.UUID, post models.Post) error {
+	sql := `UPDATE ` + r.tableName + ` SET
+		extract = $1,
+		content_html = $2,
+		content_text = $3,
+		word_count = $4,
+		reading_minutes = $5,
+		toc = $6
+	WHERE id = $7 AND content_html IS NULL AND updated_at = $8`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql,
+		post.Extract,
+		post.ContentHTML,
+		post.ContentText,
+		post.WordCount,
+		post.ReadingMinutes,
+		tocValue(post.Toc),
+		id,
+		post.UpdatedAt,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
 	return nil
 }
 
 func (r PostsRepository) DeletePostById(ctx context.Context, id uuid.UUID) error {
 	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1`
 
-	_, err := r.conn.Pool().Exec(ctx, sql, id)
+	tag, err := r.conn.Pool().Exec(ctx, sql, id)
 	if err != nil {
-		return err
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
 	}
 
 	return nil
 }
+
+// DeletePostByIdAndAuthorId deletes the post only while it belongs to authorId
+// and returns domain.ErrNotFound otherwise. When version is given the post
+// must also still be at that version, failing with domain.ErrModified
+// otherwise.
+func (r PostsRepository) DeletePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, version *time.Time) error {
+	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1 AND author_id = $2 AND ($3::timestamptz IS NULL OR updated_at = $3)`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql, id, authorId, version)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		err = r.conn.Pool().QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM `+r.tableName+` WHERE id = $1 AND author_id = $2)`,
+			id, authorId,
+		).Scan(&exists)
+		if err != nil {
+			return translateError(err)
+		}
+
+		if exists {
+			return domain.ErrModified
+		}
+
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// scanPost scans a row of postColumns, followed by the columns scanned into
+// extra, if any.
+func scanPost(row pgx.Row, extra ...any) (models.Post, error) {
+	return scanPostFields(row, postFields, extra...)
+}
+
+// scanPostFields scans a row selecting fields, followed by extra columns.
+func scanPostFields(row pgx.Row, fields []postField, extra ...any) (models.Post, error) {
+	var post models.Post
+	dest := make([]any, 0, len(fields)+len(extra))
+	for _, field := range fields {
+		dest = append(dest, field.dest(&post))
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return models.Post{}, translateError(err)
+	}
+
+	post.CreatedAt = post.CreatedAt.UTC()
+	post.UpdatedAt = post.UpdatedAt.UTC()
+	if post.PublishedAt != nil {
+		post.PublishedAt = utils.Ptr(post.PublishedAt.UTC())
+	}
+	if post.ScheduledAt != nil {
+		post.ScheduledAt = utils.Ptr(post.ScheduledAt.UTC())
+	}
+
+	return post, nil
+}
+
+// selectPostFields returns the fields of posts with the given names, in the
+// order of postFields, along with the id and creation time listings need.
+// No names stands for every field.
+func selectPostFields(names []string) ([]postField, error) {
+	if len(names) == 0 {
+		return postFields, nil
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(postFields, func(field postField) bool { return field.name == name }) {
+			return nil, domain.FieldsErrorf(domain.ErrValidation, map[string]string{name: "cannot be selected"}, "cannot select %s", name)
+		}
+	}
+
+	fields := make([]postField, 0, len(names)+2)
+	for _, field := range postFields {
+		if field.name == "id" || field.name == "created_at" || slices.Contains(names, field.name) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+func joinPostColumns(fields []postField) string {
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// postFieldColumns are the fields posts can be sorted and filtered on.
+var postFieldColumns = columnsOf(models.PostQuerySchema, map[string]string{
+	"reading_time_minutes": "reading_minutes",
+})
+
+// postFilterConditions returns the conditions matching filter, numbering
+// their placeholders from firstArg, along with their arguments.
+func postFilterConditions(filter models.PostFilter, firstArg int) ([]string, []any, error) {
+	var conditions []string
+	var args []any
+
+	if filter.AuthorId != uuid.Nil {
+		args = append(args, filter.AuthorId)
+		conditions = append(conditions, fmt.Sprintf("author_id = $%d", firstArg+len(args)-1))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", firstArg+len(args)-1))
+	}
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.slug = $%d)",
+			firstArg+len(args)-1,
+		))
+	}
+
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conditions = append(conditions, fmt.Sprintf(`category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = $%d
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+			)
+			SELECT id FROM tree
+		)`, firstArg+len(args)-1))
+	}
+
+	where, whereArgs, err := postFieldColumns.conditions(filter.Where, firstArg+len(args))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(conditions, where...), append(args, whereArgs...), nil
+}
+
+// setPostTags replaces the tags of the post with the tags whose slugs are
+// given, failing when any of them does not exist.
+func setPostTags(ctx context.Context, tx pgx.Tx, postId uuid.UUID, tags []string) error {
+	_, err := tx.Exec(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postId)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	unique := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		unique[tag] = struct{}{}
+	}
+
+	inserted, err := tx.Exec(ctx, `INSERT INTO post_tags (post_id, tag_id) SELECT $1, id FROM tags WHERE slug = ANY($2)`, postId, tags)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if inserted.RowsAffected() != int64(len(unique)) {
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{"tags": "contains unknown tags"}, "unknown tags")
+	}
+
+	return nil
+}
+
+// tocValue keeps posts without headings from storing a JSON null.
+func tocValue(toc []models.TocEntry) []models.TocEntry {
+	if toc == nil {
+		return []models.TocEntry{}
+	}
+
+	return toc
+}
+
+func scanPostRevision(row pgx.Row) (models.PostRevision, error) {
+	var revision models.PostRevision
+	err := row.Scan(
+		&revision.PostId,
+		&revision.Revision,
+		&revision.Title,
+		&revision.Extract,
+		&revision.Content,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return models.PostRevision{}, translateError(err)
+	}
+
+	revision.CreatedAt = revision.CreatedAt.UTC()
+
+	return revision, nil
+}
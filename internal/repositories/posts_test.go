@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
 	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/query"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -65,6 +67,7 @@ func (s *postsTestsSuite) TestCreatePost() {
 				ctx: context.TODO(),
 				post: models.Post{
 					Title:    "Test Post",
+					Slug:     "test-post",
 					Extract:  "This is a test post",
 					Content:  "This is the content of the test post",
 					AuthorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
@@ -93,10 +96,9 @@ func (s *postsTestsSuite) TestFindAllPosts() {
 	assertions := assert.New(t)
 
 	type args struct {
-		ctx      context.Context
-		limit    int
-		offset   int
-		authorId uuid.UUID
+		ctx    context.Context
+		page   models.Page
+		filter models.PostFilter
 	}
 	tests := []struct {
 		name    string
@@ -108,38 +110,91 @@ func (s *postsTestsSuite) TestFindAllPosts() {
 		{
 			name: "Should list 10 posts in 1 page (offset 0)",
 			args: args{
-				ctx:      context.TODO(),
-				limit:    10,
-				offset:   0,
-				authorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				ctx:    context.TODO(),
+				page:   models.Page{Limit: 10},
+				filter: models.PostFilter{AuthorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")},
 			},
 			wantErr: false,
 			err:     nil,
 			want: []models.Post{
 				{
-					Id:        uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
-					Title:     "My First Post",
-					Extract:   "This is my first post extract.",
-					Content:   "This is the full content of my first post.",
-					AuthorId:  uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
-					CreatedAt: time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
-					UpdatedAt: time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+					Id:             uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+					Title:          "My First Post",
+					Slug:           "my-first-post",
+					Extract:        "This is my first post extract.",
+					Content:        "This is the full content of my first post.",
+					ContentHTML:    "<p>This is the full content of my first post.</p>\n",
+					WordCount:      9,
+					ReadingMinutes: 1,
+					Toc:            []models.TocEntry{},
+					AuthorId:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					CategoryId:     utils.Ptr(uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")),
+					Tags:           []string{"beginners", "go"},
+					CommentMode:    models.CommentModeOpen,
+					Reactions:      map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1},
+					Status:         models.PostStatusPublished,
+					PublishedAt:    utils.Ptr(time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC)),
+					CreatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+					UpdatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
 				},
 				{
-					Id:        uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476"),
-					Title:     "Another Day in the Life",
-					Extract:   "A short story extract.",
-					Content:   "A longer text describing my second post.",
-					AuthorId:  uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
-					CreatedAt: time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
-					UpdatedAt: time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+					Id:             uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476"),
+					Title:          "Another Day in the Life",
+					Slug:           "another-day-in-the-life",
+					Extract:        "A short story extract.",
+					Content:        "A longer text describing my second post.",
+					ContentHTML:    "<p>A longer text describing my second post.</p>\n",
+					WordCount:      7,
+					ReadingMinutes: 1,
+					Toc:            []models.TocEntry{},
+					AuthorId:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					CategoryId:     utils.Ptr(uuid.MustParse("d4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12")),
+					Tags:           []string{},
+					CommentMode:    models.CommentModeOpen,
+					Reactions:      map[models.ReactionKind]int{},
+					Status:         models.PostStatusDraft,
+					CreatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+					UpdatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "Should only list posts in the given statuses",
+			args: args{
+				ctx:  context.TODO(),
+				page: models.Page{Limit: 10},
+				filter: models.PostFilter{
+					AuthorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					Statuses: []models.PostStatus{models.PostStatusPublished},
+				},
+			},
+			want: []models.Post{
+				{
+					Id:             uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+					Title:          "My First Post",
+					Slug:           "my-first-post",
+					Extract:        "This is my first post extract.",
+					Content:        "This is the full content of my first post.",
+					ContentHTML:    "<p>This is the full content of my first post.</p>\n",
+					WordCount:      9,
+					ReadingMinutes: 1,
+					Toc:            []models.TocEntry{},
+					AuthorId:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					CategoryId:     utils.Ptr(uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")),
+					Tags:           []string{"beginners", "go"},
+					CommentMode:    models.CommentModeOpen,
+					Reactions:      map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1},
+					Status:         models.PostStatusPublished,
+					PublishedAt:    utils.Ptr(time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC)),
+					CreatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
+					UpdatedAt:      time.Date(2006, 01, 02, 0, 0, 0, 0, time.UTC),
 				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := s.postsRepo.FindAllPosts(tt.args.ctx, tt.args.limit, tt.args.offset, tt.args.authorId)
+			got, gotErr := s.postsRepo.FindAllPosts(tt.args.ctx, tt.args.page, tt.args.filter, models.PostProjection{})
 			if tt.wantErr {
 				assertions.Error(gotErr)
 				require.Equal(t, tt.err, gotErr)
@@ -151,6 +206,295 @@ func (s *postsTestsSuite) TestFindAllPosts() {
 	}
 }
 
+func (s *postsTestsSuite) TestFindAllPostsByTaxonomy() {
+	t := s.T()
+
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+	helloWorldId := uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+
+	tests := []struct {
+		name   string
+		filter models.PostFilter
+		want   []uuid.UUID
+	}{
+		{
+			name:   "Should list the posts carrying a tag",
+			filter: models.PostFilter{Tag: "beginners"},
+			want:   []uuid.UUID{firstPostId, helloWorldId},
+		},
+		{
+			name:   "Should list the posts of a category",
+			filter: models.PostFilter{Category: "life"},
+			want:   []uuid.UUID{draftId},
+		},
+		{
+			name:   "Should include the posts of subcategories",
+			filter: models.PostFilter{Category: "programming"},
+			want:   []uuid.UUID{firstPostId},
+		},
+		{
+			name:   "Should combine the filters",
+			filter: models.PostFilter{Tag: "beginners", Category: "programming"},
+			want:   []uuid.UUID{firstPostId},
+		},
+		{
+			name:   "Should list nothing for an unknown tag",
+			filter: models.PostFilter{Tag: "rust"},
+			want:   []uuid.UUID{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := s.postsRepo.FindAllPosts(context.TODO(), models.Page{Limit: 10}, tt.filter, models.PostProjection{})
+			require.NoError(t, err)
+
+			got := make([]uuid.UUID, len(posts))
+			for i, post := range posts {
+				got[i] = post.Id
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func (s *postsTestsSuite) TestFindAllPostsByKeyset() {
+	t := s.T()
+
+	// The seeded posts were all created at once, so their ids order them.
+	createdAt := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	helloWorldId := uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+
+	tests := []struct {
+		name string
+		page models.Page
+		want []uuid.UUID
+	}{
+		{name: "Should list newest first", page: models.Page{Limit: 10}, want: []uuid.UUID{helloWorldId, firstPostId, draftId}},
+		{name: "Should list the posts after a keyset", page: models.Page{Limit: 1, After: &models.Keyset{CreatedAt: createdAt, Id: helloWorldId}}, want: []uuid.UUID{firstPostId}},
+		{name: "Should list the posts right before a keyset", page: models.Page{Limit: 1, Before: &models.Keyset{CreatedAt: createdAt, Id: draftId}}, want: []uuid.UUID{firstPostId}},
+		{name: "Should list the posts before a keyset newest first", page: models.Page{Limit: 10, Before: &models.Keyset{CreatedAt: createdAt, Id: draftId}}, want: []uuid.UUID{helloWorldId, firstPostId}},
+		{name: "Should list nothing past the end", page: models.Page{Limit: 10, After: &models.Keyset{CreatedAt: createdAt, Id: draftId}}, want: []uuid.UUID{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := s.postsRepo.FindAllPosts(context.TODO(), tt.page, models.PostFilter{}, models.PostProjection{})
+			require.NoError(t, err)
+
+			ids := make([]uuid.UUID, len(posts))
+			for i, post := range posts {
+				ids[i] = post.Id
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func (s *postsTestsSuite) TestFindAllPostsQuery() {
+	t := s.T()
+
+	helloWorldId := uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+
+	tests := []struct {
+		name    string
+		sort    []query.Sort
+		where   []query.Filter
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{
+			name: "Should sort by title",
+			sort: []query.Sort{{Field: "title"}},
+			want: []uuid.UUID{draftId, helloWorldId, firstPostId},
+		},
+		{
+			name:  "Should filter on words of the title",
+			where: []query.Filter{{Field: "title", Op: query.OpContains, Value: "FIRST"}},
+			want:  []uuid.UUID{firstPostId},
+		},
+		{
+			name:  "Should not read wildcards in contains",
+			where: []query.Filter{{Field: "title", Op: query.OpContains, Value: "%"}},
+			want:  []uuid.UUID{},
+		},
+		{
+			name:  "Should combine filters and sorts",
+			sort:  []query.Sort{{Field: "word_count", Desc: true}},
+			where: []query.Filter{{Field: "word_count", Op: query.OpGte, Value: 7}, {Field: "status", Op: query.OpNe, Value: "archived"}},
+			want:  []uuid.UUID{firstPostId, draftId},
+		},
+		{
+			name:    "Should reject unknown fields",
+			where:   []query.Filter{{Field: "content", Op: query.OpEq, Value: "x"}},
+			wantErr: domain.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := s.postsRepo.FindAllPosts(context.TODO(), models.Page{Limit: 10, Sort: tt.sort}, models.PostFilter{Where: tt.where}, models.PostProjection{})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			ids := make([]uuid.UUID, len(posts))
+			for i, post := range posts {
+				ids[i] = post.Id
+			}
+			assert.Equal(t, tt.want, ids)
+
+			count, err := s.postsRepo.CountPosts(context.TODO(), models.PostFilter{Where: tt.where})
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+}
+
+func (s *postsTestsSuite) TestFindAllPostsProjection() {
+	t := s.T()
+
+	createdAt := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	page := models.Page{Limit: 1}
+	filter := models.PostFilter{AuthorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"), Statuses: []models.PostStatus{models.PostStatusPublished}}
+
+	posts, err := s.postsRepo.FindAllPosts(context.TODO(), page, filter, models.PostProjection{
+		Fields: []string{"title", "extract"},
+		Author: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Post{{
+		Id:        uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+		Title:     "My First Post",
+		Extract:   "This is my first post extract.",
+		CreatedAt: createdAt,
+		Author: &models.User{
+			Id:        uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+			FirstName: "Alice",
+			LastName:  "Smith",
+			Username:  "alice_s",
+			CreatedAt: createdAt,
+		},
+	}}, posts)
+
+	_, err = s.postsRepo.FindAllPosts(context.TODO(), page, filter, models.PostProjection{Fields: []string{"hashed_password"}})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func (s *postsTestsSuite) TestCountPosts() {
+	t := s.T()
+
+	count, err := s.postsRepo.CountPosts(context.TODO(), models.PostFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = s.postsRepo.CountPosts(context.TODO(), models.PostFilter{
+		AuthorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Statuses: []models.PostStatus{models.PostStatusPublished},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func (s *postsTestsSuite) TestPostTags() {
+	t := s.T()
+
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	_, err := s.postsRepo.CreatePost(context.TODO(), models.Post{
+		Title:    "Tagged Post",
+		Slug:     "tagged-post",
+		Content:  "Content.",
+		AuthorId: aliceId,
+		Tags:     []string{"go", "rust"},
+	})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{"tags": "contains unknown tags"}, domain.Fields(err))
+
+	id, err := s.postsRepo.CreatePost(context.TODO(), models.Post{
+		Title:    "Tagged Post",
+		Slug:     "tagged-post",
+		Content:  "Content.",
+		AuthorId: aliceId,
+		Tags:     []string{"go", "go"},
+	})
+	require.NoError(t, err)
+
+	post, err := s.postsRepo.FindPostById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, post.Tags)
+
+	post.Tags = []string{}
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), id, aliceId, post))
+
+	post, err = s.postsRepo.FindPostById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Empty(t, post.Tags)
+}
+
+func (s *postsTestsSuite) TestFindPostById() {
+	t := s.T()
+
+	assertions := assert.New(t)
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		want    models.Post
+		wantErr bool
+		err     error
+	}{
+		{
+			name: "Should return a post",
+			id:   uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+			want: models.Post{
+				Id:             uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				Title:          "My First Post",
+				Slug:           "my-first-post",
+				Extract:        "This is my first post extract.",
+				Content:        "This is the full content of my first post.",
+				ContentHTML:    "<p>This is the full content of my first post.</p>\n",
+				WordCount:      9,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{},
+				AuthorId:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				CategoryId:     utils.Ptr(uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")),
+				Tags:           []string{"beginners", "go"},
+				CommentMode:    models.CommentModeOpen,
+				Reactions:      map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1},
+				Status:         models.PostStatusPublished,
+				PublishedAt:    utils.Ptr(time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)),
+				CreatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "Should fail when the post does not exist",
+			id:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			wantErr: true,
+			err:     domain.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := s.postsRepo.FindPostById(context.TODO(), tt.id)
+			if tt.wantErr {
+				assertions.ErrorIs(gotErr, tt.err)
+				return
+			}
+
+			if !assertions.NoError(gotErr) {
+				return
+			}
+			assertions.Equal(tt.want, got)
+		})
+	}
+}
+
 func (s *postsTestsSuite) TestFindPostByIdAndAuthorId() {
 	t := s.T()
 
@@ -176,13 +520,24 @@ func (s *postsTestsSuite) TestFindPostByIdAndAuthorId() {
 				authorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
 			},
 			want: models.Post{
-				Id:        uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
-				Title:     "My First Post",
-				Extract:   "This is my first post extract.",
-				Content:   "This is the full content of my first post.",
-				AuthorId:  uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
-				CreatedAt: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
-				UpdatedAt: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+				Id:             uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				Title:          "My First Post",
+				Slug:           "my-first-post",
+				Extract:        "This is my first post extract.",
+				Content:        "This is the full content of my first post.",
+				ContentHTML:    "<p>This is the full content of my first post.</p>\n",
+				WordCount:      9,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{},
+				AuthorId:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				CategoryId:     utils.Ptr(uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01")),
+				Tags:           []string{"beginners", "go"},
+				CommentMode:    models.CommentModeOpen,
+				Reactions:      map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1},
+				Status:         models.PostStatusPublished,
+				PublishedAt:    utils.Ptr(time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)),
+				CreatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:      time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
 			},
 		},
 	}
@@ -227,6 +582,7 @@ func (s *postsTestsSuite) TestUpdatePostByIdAndAuthorId() {
 				post: models.Post{
 					Id:        uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
 					Title:     "New Title",
+					Slug:      "new-title",
 					Extract:   "This is my first post extract.",
 					Content:   "This is the full content of my first post.",
 					AuthorId:  uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
@@ -235,6 +591,34 @@ func (s *postsTestsSuite) TestUpdatePostByIdAndAuthorId() {
 				},
 			},
 		},
+		{
+			name: "Should not update a post modified since it was read",
+			args: args{
+				ctx:      context.TODO(),
+				id:       uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				authorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				post: models.Post{
+					Title:     "Stale Title",
+					Slug:      "stale-title",
+					AuthorId:  uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					UpdatedAt: time.Date(2005, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not update a post of another author",
+			args: args{
+				ctx:      context.TODO(),
+				id:       uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				authorId: uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
+				post: models.Post{
+					Title:    "New Title",
+					AuthorId: uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -246,6 +630,375 @@ func (s *postsTestsSuite) TestUpdatePostByIdAndAuthorId() {
 	}
 }
 
+func (s *postsTestsSuite) TestPostVersions() {
+	t := s.T()
+
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+	postId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+
+	post, err := s.postsRepo.FindPostById(context.TODO(), postId)
+	require.NoError(t, err)
+
+	post.Extract = "A new extract."
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post))
+
+	// The version read above is gone once the update went through.
+	err = s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post)
+	assert.ErrorIs(t, err, domain.ErrModified)
+
+	err = s.postsRepo.DeletePostByIdAndAuthorId(context.TODO(), postId, aliceId, &post.UpdatedAt)
+	assert.ErrorIs(t, err, domain.ErrModified)
+
+	err = s.postsRepo.DeletePostByIdAndAuthorId(context.TODO(), postId, uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"), &post.UpdatedAt)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *postsTestsSuite) TestSlugHistory() {
+	t := s.T()
+
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+	postId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+
+	post, err := s.postsRepo.FindPostBySlug(context.TODO(), "alice_s", "my-first-post")
+	require.NoError(t, err)
+	require.Equal(t, postId, post.Id)
+
+	post.Title = "My Renamed Post"
+	post.Slug = "my-renamed-post"
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post))
+
+	_, err = s.postsRepo.FindPostBySlug(context.TODO(), "alice_s", "my-first-post")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	old, err := s.postsRepo.FindPostByOldSlug(context.TODO(), "alice_s", "my-first-post")
+	require.NoError(t, err)
+	assert.Equal(t, postId, old.Id)
+	assert.Equal(t, "my-renamed-post", old.Slug)
+
+	_, err = s.postsRepo.FindPostByOldSlug(context.TODO(), "bobby_j", "my-first-post")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	taken, err := s.postsRepo.FindTakenSlugs(context.TODO(), aliceId, "my-first-post", uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"my-first-post"}, taken)
+
+	taken, err = s.postsRepo.FindTakenSlugs(context.TODO(), aliceId, "my-first-post", postId)
+	require.NoError(t, err)
+	assert.Empty(t, taken)
+
+	// Taking the old slug back removes it from the history.
+	post, err = s.postsRepo.FindPostById(context.TODO(), postId)
+	require.NoError(t, err)
+	post.Slug = "my-first-post"
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post))
+
+	_, err = s.postsRepo.FindPostBySlug(context.TODO(), "alice_s", "my-first-post")
+	assert.NoError(t, err)
+
+	renamed, err := s.postsRepo.FindPostByOldSlug(context.TODO(), "alice_s", "my-renamed-post")
+	require.NoError(t, err)
+	assert.Equal(t, postId, renamed.Id)
+}
+
+func (s *postsTestsSuite) TestPostRevisions() {
+	t := s.T()
+
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+	postId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+
+	post, err := s.postsRepo.FindPostById(context.TODO(), postId)
+	require.NoError(t, err)
+
+	// Changing only the tags keeps the text, so no revision is recorded.
+	post.Tags = []string{"go"}
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post))
+
+	post, err = s.postsRepo.FindPostById(context.TODO(), postId)
+	require.NoError(t, err)
+	post.Content = "Rewritten content."
+	require.NoError(t, s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), postId, aliceId, post))
+
+	revisions, err := s.postsRepo.FindPostRevisions(context.TODO(), postId)
+	require.NoError(t, err)
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, "Rewritten content.", revisions[0].Content)
+		assert.Equal(t, 1, revisions[1].Revision)
+		assert.Equal(t, "This is the full content of my first post.", revisions[1].Content)
+	}
+
+	first, err := s.postsRepo.FindPostRevision(context.TODO(), postId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "My First Post", first.Title)
+
+	_, err = s.postsRepo.FindPostRevision(context.TODO(), postId, 3)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	id, err := s.postsRepo.CreatePost(context.TODO(), models.Post{
+		Title:    "Fresh Post",
+		Slug:     "fresh-post",
+		Content:  "Content.",
+		AuthorId: aliceId,
+	})
+	require.NoError(t, err)
+
+	revisions, err = s.postsRepo.FindPostRevisions(context.TODO(), id)
+	require.NoError(t, err)
+	if assert.Len(t, revisions, 1) {
+		assert.Equal(t, 1, revisions[0].Revision)
+		assert.Equal(t, "Fresh Post", revisions[0].Title)
+	}
+}
+
+func (s *postsTestsSuite) TestRendering() {
+	t := s.T()
+
+	helloWorldId := uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+
+	posts, err := s.postsRepo.FindUnrenderedPosts(context.TODO(), 10)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, helloWorldId, posts[0].Id)
+	assert.Empty(t, posts[0].ContentHTML)
+
+	rendering := models.Post{
+		Extract:        "My first blog entry.",
+		ContentHTML:    "<h1 id=\"hello\">Hello</h1>\n<p>This is the main content of my post.</p>\n",
+		ContentText:    "Hello\n\nThis is the main content of my post.",
+		WordCount:      9,
+		ReadingMinutes: 1,
+		Toc:            []models.TocEntry{{Level: 1, Id: "hello", Text: "Hello"}},
+		UpdatedAt:      posts[0].UpdatedAt,
+	}
+
+	// The post was edited since it was read.
+	stale := rendering
+	stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+	err = s.postsRepo.UpdatePostRenderingById(context.TODO(), helloWorldId, stale)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = s.postsRepo.UpdatePostRenderingById(context.TODO(), helloWorldId, rendering)
+	require.NoError(t, err)
+
+	// It is rendered already.
+	err = s.postsRepo.UpdatePostRenderingById(context.TODO(), helloWorldId, rendering)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	posts, err = s.postsRepo.FindUnrenderedPosts(context.TODO(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+
+	got, err := s.postsRepo.FindPostById(context.TODO(), helloWorldId)
+	require.NoError(t, err)
+	assert.Equal(t, "<h1 id=\"hello\">Hello</h1>\n<p>This is the main content of my post.</p>\n", got.ContentHTML)
+	assert.Equal(t, 9, got.WordCount)
+	assert.Equal(t, []models.TocEntry{{Level: 1, Id: "hello", Text: "Hello"}}, got.Toc)
+	assert.Equal(t, time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC), got.UpdatedAt)
+
+	// Snippets come from the stored text rather than the Markdown source.
+	results, err := s.postsRepo.SearchPosts(context.TODO(), "'main'", 10, 0, models.PostFilter{})
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Contains(t, results[0].Snippet, "Hello")
+		assert.Contains(t, results[0].Snippet, "<mark>main</mark>")
+	}
+}
+
+func (s *postsTestsSuite) TestSearchPosts() {
+	t := s.T()
+
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+	helloWorldId := uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418")
+
+	tests := []struct {
+		name    string
+		tsQuery string
+		filter  models.PostFilter
+		want    []uuid.UUID
+	}{
+		{
+			name:    "Should rank title matches first",
+			tsQuery: "'first'",
+			want:    []uuid.UUID{firstPostId, helloWorldId},
+		},
+		{
+			name:    "Should match phrases",
+			tsQuery: "'first' <-> 'post'",
+			want:    []uuid.UUID{firstPostId},
+		},
+		{
+			name:    "Should match prefixes",
+			tsQuery: "'descri':*",
+			want:    []uuid.UUID{draftId},
+		},
+		{
+			name:    "Should apply the filter",
+			tsQuery: "'descri':*",
+			filter:  models.PostFilter{Statuses: []models.PostStatus{models.PostStatusPublished}},
+			want:    []uuid.UUID{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.postsRepo.SearchPosts(context.TODO(), tt.tsQuery, 10, 0, tt.filter)
+			require.NoError(t, err)
+
+			got := make([]uuid.UUID, len(results))
+			for i, result := range results {
+				got[i] = result.Post.Id
+				assert.Equal(t, models.PostMatchFullText, result.Match)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	results, err := s.postsRepo.SearchPosts(context.TODO(), "'full'", 10, 0, models.PostFilter{})
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "My First Post", results[0].Post.Title)
+		assert.Positive(t, results[0].Rank)
+		assert.Equal(t, "This is the <mark>full</mark> content of my first post.", results[0].Snippet)
+	}
+}
+
+func (s *postsTestsSuite) TestFindPostsBySimilarTitle() {
+	t := s.T()
+
+	results, err := s.postsRepo.FindPostsBySimilarTitle(context.TODO(), "helo wrld", 10, 0, models.PostFilter{})
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, uuid.MustParse("bbf19b79-cf9c-4a07-8e43-299baf69b418"), results[0].Post.Id)
+		assert.Equal(t, models.PostMatchSimilarTitle, results[0].Match)
+		assert.Equal(t, "My first blog entry.", results[0].Snippet)
+	}
+
+	results, err = s.postsRepo.FindPostsBySimilarTitle(context.TODO(), "quantum", 10, 0, models.PostFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func (s *postsTestsSuite) TestCommentMode() {
+	t := s.T()
+
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	post, err := s.postsRepo.FindPostById(context.TODO(), firstPostId)
+	require.NoError(t, err)
+
+	post.CommentMode = models.CommentModeClosed
+	err = s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), firstPostId, aliceId, post)
+	require.NoError(t, err)
+
+	// Updates without a comment mode keep the current one.
+	post, err = s.postsRepo.FindPostById(context.TODO(), firstPostId)
+	require.NoError(t, err)
+	post.CommentMode = ""
+	err = s.postsRepo.UpdatePostByIdAndAuthorId(context.TODO(), firstPostId, aliceId, post)
+	require.NoError(t, err)
+
+	got, err := s.postsRepo.FindPostById(context.TODO(), firstPostId)
+	require.NoError(t, err)
+	assert.Equal(t, models.CommentModeClosed, got.CommentMode)
+}
+
+func (s *postsTestsSuite) TestPostReactions() {
+	t := s.T()
+
+	firstPostId := uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3")
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+	bobId := uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db")
+	charlieId := uuid.MustParse("2cdc1c8f-9985-4b6c-b007-038a5bef22b5")
+
+	reactions := func() map[models.ReactionKind]int {
+		post, err := s.postsRepo.FindPostById(context.TODO(), firstPostId)
+		require.NoError(t, err)
+		return post.Reactions
+	}
+
+	err := s.postsRepo.SetPostReaction(context.TODO(), firstPostId, aliceId, models.ReactionKindLike)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionKind]int{models.ReactionKindLike: 2, models.ReactionKindLove: 1}, reactions())
+
+	// Reacting again with the same kind changes nothing.
+	err = s.postsRepo.SetPostReaction(context.TODO(), firstPostId, aliceId, models.ReactionKindLike)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionKind]int{models.ReactionKindLike: 2, models.ReactionKindLove: 1}, reactions())
+
+	// Another kind replaces the former reaction.
+	err = s.postsRepo.SetPostReaction(context.TODO(), firstPostId, bobId, models.ReactionKindFunny)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1, models.ReactionKindFunny: 1}, reactions())
+
+	err = s.postsRepo.DeletePostReaction(context.TODO(), firstPostId, bobId, models.ReactionKindLike)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = s.postsRepo.DeletePostReaction(context.TODO(), firstPostId, bobId, models.ReactionKindFunny)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionKind]int{models.ReactionKindLike: 1, models.ReactionKindLove: 1}, reactions())
+
+	// Reactions removed along with their users are discounted as well.
+	_, err = PostgresConn.Pool().Exec(context.TODO(), `DELETE FROM users WHERE id = $1`, charlieId)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ReactionKind]int{models.ReactionKindLike: 1}, reactions())
+
+	err = s.postsRepo.SetPostReaction(context.TODO(), uuid.New(), aliceId, models.ReactionKindLike)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	assert.Equal(t, map[string]string{"post_id": "does not exist"}, domain.Fields(err))
+}
+
+func (s *postsTestsSuite) TestUpdatePostStatusById() {
+	t := s.T()
+
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+	publishedAt := time.Date(2006, time.January, 3, 0, 0, 0, 0, time.UTC)
+
+	err := s.postsRepo.UpdatePostStatusById(context.TODO(), draftId, models.PostStatusDraft, models.Post{
+		Status:      models.PostStatusPublished,
+		PublishedAt: &publishedAt,
+	})
+	require.NoError(t, err)
+
+	got, err := s.postsRepo.FindPostById(context.TODO(), draftId)
+	require.NoError(t, err)
+	assert.Equal(t, models.PostStatusPublished, got.Status)
+	assert.Equal(t, &publishedAt, got.PublishedAt)
+
+	// The post is no longer a draft, so a concurrent transition must fail.
+	err = s.postsRepo.UpdatePostStatusById(context.TODO(), draftId, models.PostStatusDraft, models.Post{
+		Status: models.PostStatusInReview,
+	})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *postsTestsSuite) TestPublishDuePosts() {
+	t := s.T()
+
+	draftId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+	scheduledAt := time.Date(2006, time.January, 3, 12, 0, 0, 0, time.UTC)
+
+	err := s.postsRepo.UpdatePostStatusById(context.TODO(), draftId, models.PostStatusDraft, models.Post{
+		Status:      models.PostStatusDraft,
+		ScheduledAt: &scheduledAt,
+	})
+	require.NoError(t, err)
+
+	published, err := s.postsRepo.PublishDuePosts(context.TODO(), scheduledAt.Add(-time.Second), 10)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	published, err = s.postsRepo.PublishDuePosts(context.TODO(), scheduledAt, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, published)
+
+	got, err := s.postsRepo.FindPostById(context.TODO(), draftId)
+	require.NoError(t, err)
+	assert.Equal(t, models.PostStatusPublished, got.Status)
+	assert.Equal(t, &scheduledAt, got.PublishedAt)
+	assert.Nil(t, got.ScheduledAt)
+}
+
 func (s *postsTestsSuite) TestDeletePostById() {
 	t := s.T()
 
@@ -274,3 +1027,55 @@ func (s *postsTestsSuite) TestDeletePostById() {
 		})
 	}
 }
+
+func (s *postsTestsSuite) TestDeletePostByIdAndAuthorId() {
+	t := s.T()
+
+	type args struct {
+		ctx      context.Context
+		id       uuid.UUID
+		authorId uuid.UUID
+		version  *time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should not delete a post of another author",
+			args: args{
+				ctx:      context.TODO(),
+				id:       uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				authorId: uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not delete a post modified since the given version",
+			args: args{
+				ctx:      context.TODO(),
+				id:       uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				authorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				version:  utils.Ptr(time.Date(2005, time.January, 2, 0, 0, 0, 0, time.UTC)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should delete post of its author",
+			args: args{
+				ctx:      context.TODO(),
+				id:       uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+				authorId: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				version:  utils.Ptr(time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.postsRepo.DeletePostByIdAndAuthorId(tt.args.ctx, tt.args.id, tt.args.authorId, tt.args.version); (err != nil) != tt.wantErr {
+				t.Errorf("Repositories.DeletePostByIdAndAuthorId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
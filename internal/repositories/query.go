@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/pkg/query"
+)
+
+// fieldColumns maps the fields clients may sort and filter a listing on to
+// the SQL expressions behind them, so that only those expressions, and never
+// what clients sent, end up in the SQL. Filter values are always passed as
+// arguments.
+type fieldColumns map[string]string
+
+// columnsOf maps every field of schema to the column of the same name, unless
+// renamed names another one.
+func columnsOf(schema query.Schema, renamed map[string]string) fieldColumns {
+	c := make(fieldColumns, len(schema))
+	for field := range schema {
+		c[field] = field
+		if column, ok := renamed[field]; ok {
+			c[field] = column
+		}
+	}
+
+	return c
+}
+
+var comparisons = map[query.Op]string{
+	query.OpEq:  "=",
+	query.OpNe:  "<>",
+	query.OpGt:  ">",
+	query.OpGte: ">=",
+	query.OpLt:  "<",
+	query.OpLte: "<=",
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// conditions renders filters as conditions, numbering their placeholders
+// from firstArg, along with their arguments.
+func (c fieldColumns) conditions(filters []query.Filter, firstArg int) ([]string, []any, error) {
+	var conditions []string
+	var args []any
+
+	for _, filter := range filters {
+		column, ok := c[filter.Field]
+		if !ok {
+			return nil, nil, domain.FieldsErrorf(domain.ErrValidation, map[string]string{filter.Field: "cannot be filtered on"}, "cannot filter on %s", filter.Field)
+		}
+
+		if filter.Op == query.OpContains {
+			text, _ := filter.Value.(string)
+			args = append(args, likeEscaper.Replace(text))
+			conditions = append(conditions, fmt.Sprintf(`%s ILIKE '%%' || $%d || '%%'`, column, firstArg+len(args)-1))
+			continue
+		}
+
+		comparison, ok := comparisons[filter.Op]
+		if !ok {
+			return nil, nil, domain.FieldsErrorf(domain.ErrValidation, map[string]string{filter.Field: "cannot be filtered with " + string(filter.Op)}, "unknown operator %s", filter.Op)
+		}
+
+		args = append(args, filter.Value)
+		conditions = append(conditions, fmt.Sprintf("%s %s $%d", column, comparison, firstArg+len(args)-1))
+	}
+
+	return conditions, args, nil
+}
+
+// orderBy renders sorts as the terms of an ORDER BY clause.
+func (c fieldColumns) orderBy(sorts []query.Sort) ([]string, error) {
+	terms := make([]string, len(sorts))
+	for i, sort := range sorts {
+		column, ok := c[sort.Field]
+		if !ok {
+			return nil, domain.FieldsErrorf(domain.ErrValidation, map[string]string{sort.Field: "cannot be sorted by"}, "cannot sort by %s", sort.Field)
+		}
+
+		terms[i] = column + " ASC"
+		if sort.Desc {
+			terms[i] = column + " DESC"
+		}
+	}
+
+	return terms, nil
+}
+
+// whereClause joins conditions into a WHERE clause, empty when there are
+// none.
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
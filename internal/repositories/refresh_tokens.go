@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/postgres"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type RefreshTokensRepository struct {
+	conn         *postgres.Postgres
+	timeProvider utils.TimeProvider
+	tableName    string
+}
+
+func NewRefreshTokensRepository(conn *postgres.Postgres, timeProvider utils.TimeProvider) *RefreshTokensRepository {
+	return &RefreshTokensRepository{
+		conn:         conn,
+		timeProvider: timeProvider,
+		tableName:    "refresh_tokens",
+	}
+}
+
+func (r RefreshTokensRepository) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (uuid.UUID, error) {
+	return r.createRefreshToken(ctx, r.conn.Pool(), token)
+}
+
+func (r RefreshTokensRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	sql := `SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+	FROM ` + r.tableName + ` WHERE token_hash = $1`
+
+	var token models.RefreshToken
+	err := r.conn.Pool().QueryRow(ctx, sql, tokenHash).Scan(
+		&token.Id,
+		&token.UserId,
+		&token.FamilyId,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return models.RefreshToken{}, translateError(err)
+	}
+
+	token.ExpiresAt = token.ExpiresAt.UTC()
+	token.CreatedAt = token.CreatedAt.UTC()
+	if token.RevokedAt != nil {
+		token.RevokedAt = utils.Ptr(token.RevokedAt.UTC())
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken revokes the token identified by id and stores newToken in
+// its place within a single transaction. It returns domain.ErrNotFound when id was
+// already revoked, which means a concurrent request consumed it first.
+func (r RefreshTokensRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID, newToken models.RefreshToken) (uuid.UUID, error) {
+	tx, err := r.conn.Pool().Begin(ctx)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE ` + r.tableName + ` SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+
+	tag, err := tx.Exec(ctx, sql, r.timeProvider.Now().UTC(), id)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return uuid.Nil, domain.ErrNotFound
+	}
+
+	newId, err := r.createRefreshToken(ctx, tx, newToken)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return newId, translateError(tx.Commit(ctx))
+}
+
+func (r RefreshTokensRepository) RevokeRefreshTokenFamily(ctx context.Context, familyId uuid.UUID) error {
+	sql := `UPDATE ` + r.tableName + ` SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+
+	_, err := r.conn.Pool().Exec(ctx, sql, r.timeProvider.Now().UTC(), familyId)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r RefreshTokensRepository) createRefreshToken(ctx context.Context, q queryRower, token models.RefreshToken) (uuid.UUID, error) {
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = r.timeProvider.Now().UTC()
+	}
+
+	sql := `INSERT INTO ` + r.tableName + ` (
+		user_id, family_id, token_hash, expires_at, created_at
+	) VALUES ($1,$2,$3,$4,$5) RETURNING id`
+
+	var returnedID uuid.UUID
+	err := q.QueryRow(ctx, sql,
+		token.UserId,
+		token.FamilyId,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&returnedID)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return returnedID, nil
+}
@@ -0,0 +1,104 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type refreshTokensTestsSuite struct {
+	suite.Suite
+	refreshTokensRepo *repositories.RefreshTokensRepository
+}
+
+// This will run before running the suite
+func (s *refreshTokensTestsSuite) SetupSuite() {
+	s.refreshTokensRepo = repositories.NewRefreshTokensRepository(PostgresConn, utils.MockClock{})
+}
+
+// This will run after each test
+func (s *refreshTokensTestsSuite) TearDownTest() {
+	err := PostgresContainer.Restore(context.TODO())
+	require.NoError(s.T(), err)
+	PostgresConn.Pool().Reset()
+}
+
+func TestRefreshTokensRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(refreshTokensTestsSuite))
+}
+
+func newTestRefreshToken(familyId uuid.UUID, tokenHash string) models.RefreshToken {
+	return models.RefreshToken{
+		UserId:    uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		FamilyId:  familyId,
+		TokenHash: tokenHash,
+		ExpiresAt: commonTime.Add(24 * time.Hour),
+	}
+}
+
+func (s *refreshTokensTestsSuite) TestCreateAndFindRefreshToken() {
+	t := s.T()
+
+	familyId := uuid.New()
+	id, err := s.refreshTokensRepo.CreateRefreshToken(context.TODO(), newTestRefreshToken(familyId, "hash-1"))
+	require.NoError(t, err)
+
+	got, err := s.refreshTokensRepo.FindRefreshTokenByHash(context.TODO(), "hash-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, id, got.Id)
+	assert.Equal(t, familyId, got.FamilyId)
+	assert.Equal(t, commonTime.Add(24*time.Hour), got.ExpiresAt)
+	assert.Nil(t, got.RevokedAt)
+
+	_, err = s.refreshTokensRepo.FindRefreshTokenByHash(context.TODO(), "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *refreshTokensTestsSuite) TestRotateRefreshToken() {
+	t := s.T()
+
+	familyId := uuid.New()
+	id, err := s.refreshTokensRepo.CreateRefreshToken(context.TODO(), newTestRefreshToken(familyId, "hash-1"))
+	require.NoError(t, err)
+
+	_, err = s.refreshTokensRepo.RotateRefreshToken(context.TODO(), id, newTestRefreshToken(familyId, "hash-2"))
+	require.NoError(t, err)
+
+	old, err := s.refreshTokensRepo.FindRefreshTokenByHash(context.TODO(), "hash-1")
+	require.NoError(t, err)
+	assert.NotNil(t, old.RevokedAt)
+
+	_, err = s.refreshTokensRepo.RotateRefreshToken(context.TODO(), id, newTestRefreshToken(familyId, "hash-3"))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = s.refreshTokensRepo.FindRefreshTokenByHash(context.TODO(), "hash-3")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *refreshTokensTestsSuite) TestRevokeRefreshTokenFamily() {
+	t := s.T()
+
+	familyId := uuid.New()
+	_, err := s.refreshTokensRepo.CreateRefreshToken(context.TODO(), newTestRefreshToken(familyId, "hash-1"))
+	require.NoError(t, err)
+	_, err = s.refreshTokensRepo.CreateRefreshToken(context.TODO(), newTestRefreshToken(familyId, "hash-2"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.refreshTokensRepo.RevokeRefreshTokenFamily(context.TODO(), familyId))
+
+	for _, hash := range []string{"hash-1", "hash-2"} {
+		got, err := s.refreshTokensRepo.FindRefreshTokenByHash(context.TODO(), hash)
+		require.NoError(t, err)
+		assert.NotNil(t, got.RevokedAt)
+	}
+}
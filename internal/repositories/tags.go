@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/postgres"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// tagColumns selects a tag along with the number of published posts that
+// carry it.
+const tagColumns = `id, name, slug,
+	(SELECT count(*) FROM post_tags pt JOIN posts p ON p.id = pt.post_id WHERE pt.tag_id = tags.id AND p.status = 'published') AS post_count,
+	created_at, updated_at`
+
+type TagsRepository struct {
+	conn         *postgres.Postgres
+	timeProvider utils.TimeProvider
+	tableName    string
+}
+
+func NewTagsRepository(conn *postgres.Postgres, timeProvider utils.TimeProvider) *TagsRepository {
+	return &TagsRepository{
+		conn:         conn,
+		timeProvider: timeProvider,
+		tableName:    "tags",
+	}
+}
+
+func (r TagsRepository) CreateTag(ctx context.Context, tag models.Tag) (uuid.UUID, error) {
+	now := r.timeProvider.Now().UTC()
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = now
+	}
+	if tag.UpdatedAt.IsZero() {
+		tag.UpdatedAt = now
+	}
+
+	sql := `INSERT INTO ` + r.tableName + ` (name, slug, created_at, updated_at) VALUES ($1,$2,$3,$4) RETURNING id`
+
+	var returnedID uuid.UUID
+	err := r.conn.Pool().QueryRow(ctx, sql, tag.Name, tag.Slug, tag.CreatedAt, tag.UpdatedAt).Scan(&returnedID)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return returnedID, nil
+}
+
+// FindAllTags lists tags by descending number of published posts, which is
+// the order a tag cloud wants.
+func (r TagsRepository) FindAllTags(ctx context.Context, limit, offset int) ([]models.Tag, error) {
+	sql := `SELECT ` + tagColumns + `
+	FROM ` + r.tableName + ` ORDER BY post_count DESC, slug LIMIT $1 OFFSET $2`
+
+	rows, err := r.conn.Pool().Query(ctx, sql, limit, offset)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return tags, nil
+}
+
+func (r TagsRepository) FindTagById(ctx context.Context, id uuid.UUID) (models.Tag, error) {
+	sql := `SELECT ` + tagColumns + `
+	FROM ` + r.tableName + ` WHERE id = $1`
+
+	return scanTag(r.conn.Pool().QueryRow(ctx, sql, id))
+}
+
+func (r TagsRepository) UpdateTagById(ctx context.Context, id uuid.UUID, tag models.Tag) error {
+	sql := `UPDATE ` + r.tableName + ` SET name = $1, slug = $2, updated_at = $3 WHERE id = $4`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql, tag.Name, tag.Slug, r.timeProvider.Now().UTC(), id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r TagsRepository) DeleteTagById(ctx context.Context, id uuid.UUID) error {
+	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1`
+
+	cmd, err := r.conn.Pool().Exec(ctx, sql, id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanTag(row pgx.Row) (models.Tag, error) {
+	var tag models.Tag
+	err := row.Scan(
+		&tag.Id,
+		&tag.Name,
+		&tag.Slug,
+		&tag.PostCount,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return models.Tag{}, translateError(err)
+	}
+
+	tag.CreatedAt = tag.CreatedAt.UTC()
+	tag.UpdatedAt = tag.UpdatedAt.UTC()
+
+	return tag, nil
+}
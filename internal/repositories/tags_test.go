@@ -0,0 +1,94 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type tagsTestsSuite struct {
+	suite.Suite
+	tagsRepo *repositories.TagsRepository
+}
+
+// This will run before running the suite
+func (s *tagsTestsSuite) SetupSuite() {
+	s.tagsRepo = repositories.NewTagsRepository(PostgresConn, utils.MockClock{})
+}
+
+// This will run after each test
+func (s *tagsTestsSuite) TearDownTest() {
+	err := PostgresContainer.Restore(context.TODO())
+	require.NoError(s.T(), err)
+	PostgresConn.Pool().Reset()
+}
+
+func TestTagsRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(tagsTestsSuite))
+}
+
+func (s *tagsTestsSuite) TestCreateTag() {
+	t := s.T()
+
+	id, err := s.tagsRepo.CreateTag(context.TODO(), models.Tag{Name: "Rust", Slug: "rust"})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, id)
+
+	_, err = s.tagsRepo.CreateTag(context.TODO(), models.Tag{Name: "Go!", Slug: "go"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Equal(t, map[string]string{"slug": "already exists"}, domain.Fields(err))
+}
+
+func (s *tagsTestsSuite) TestFindAllTags() {
+	t := s.T()
+
+	tags, err := s.tagsRepo.FindAllTags(context.TODO(), 10, 0)
+	require.NoError(t, err)
+
+	// Both posts tagged beginners are published, but only one carries go.
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "beginners", tags[0].Slug)
+		assert.Equal(t, 2, tags[0].PostCount)
+		assert.Equal(t, "go", tags[1].Slug)
+		assert.Equal(t, 1, tags[1].PostCount)
+	}
+}
+
+func (s *tagsTestsSuite) TestUpdateTagById() {
+	t := s.T()
+
+	id := uuid.MustParse("0e9a8b7c-6d5e-4f3a-8b1c-9d8e7f6a5b40")
+
+	err := s.tagsRepo.UpdateTagById(context.TODO(), id, models.Tag{Name: "Golang", Slug: "golang"})
+	require.NoError(t, err)
+
+	tag, err := s.tagsRepo.FindTagById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Golang", tag.Name)
+	assert.Equal(t, "golang", tag.Slug)
+
+	err = s.tagsRepo.UpdateTagById(context.TODO(), uuid.MustParse("00000000-0000-0000-0000-000000000001"), models.Tag{Name: "Nope", Slug: "nope"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *tagsTestsSuite) TestDeleteTagById() {
+	t := s.T()
+
+	id := uuid.MustParse("0e9a8b7c-6d5e-4f3a-8b1c-9d8e7f6a5b40")
+
+	require.NoError(t, s.tagsRepo.DeleteTagById(context.TODO(), id))
+
+	_, err := s.tagsRepo.FindTagById(context.TODO(), id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = s.tagsRepo.DeleteTagById(context.TODO(), id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -1,3 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     first_name VARCHAR(100) NOT NULL,
@@ -5,21 +7,172 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) UNIQUE NOT NULL,
     username VARCHAR(100) UNIQUE NOT NULL,
     hashed_password TEXT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'author' CHECK (role IN ('admin', 'editor', 'author')),
     birth_date DATE NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
 );
 
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
 CREATE TABLE IF NOT EXISTS posts (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
     extract TEXT NOT NULL,
     content TEXT NOT NULL,
+    content_html TEXT,
+    content_text TEXT,
+    word_count INT NOT NULL DEFAULT 0,
+    reading_minutes INT NOT NULL DEFAULT 0,
+    toc JSONB NOT NULL DEFAULT '[]',
     author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    published_at TIMESTAMP WITH TIME ZONE,
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    comment_mode VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (comment_mode IN ('open', 'moderated', 'closed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', extract), 'B') ||
+        setweight(to_tsvector('english', content), 'C')
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS posts_status_published_at_idx ON posts (status, published_at DESC);
+CREATE INDEX IF NOT EXISTS posts_scheduled_at_idx ON posts (scheduled_at) WHERE scheduled_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS posts_author_id_slug_key ON posts (author_id, slug);
+CREATE INDEX IF NOT EXISTS posts_category_id_idx ON posts (category_id);
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS posts_title_trgm_idx ON posts USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS posts_created_at_id_idx ON posts (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS post_slug_history (
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(255) NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (author_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS post_slug_history_post_id_idx ON post_slug_history (post_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    slug VARCHAR(50) UNIQUE NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_tags_tag_id_idx ON post_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL CHECK (revision > 0),
+    title VARCHAR(255) NOT NULL,
+    extract TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (post_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 10000),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    spam_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    spam_reasons TEXT[] NOT NULL DEFAULT '{}',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    UNIQUE (post_id, id),
+    CONSTRAINT comments_parent_id_fkey FOREIGN KEY (post_id, parent_id) REFERENCES comments (post_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_created_at_idx ON comments (post_id, created_at);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS comments_status_created_at_idx ON comments (status, created_at);
+CREATE INDEX IF NOT EXISTS comments_author_id_created_at_idx ON comments (author_id, created_at);
+
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('like', 'love', 'insightful', 'funny')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_reactions_user_id_idx ON post_reactions (user_id);
+
+-- post_reaction_counts keeps the number of reactions of every kind on a post
+-- so listings read them without counting post_reactions.
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (post_id, kind)
+);
+
+-- The counts are maintained by a trigger rather than by the application so
+-- that reactions removed by cascades, such as when a user is deleted, are
+-- discounted too.
+CREATE OR REPLACE FUNCTION count_post_reactions() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE post_reaction_counts SET count = count - 1
+        WHERE post_id = OLD.post_id AND kind = OLD.kind;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO post_reaction_counts (post_id, kind, count) VALUES (NEW.post_id, NEW.kind, 1)
+        ON CONFLICT (post_id, kind) DO UPDATE SET count = post_reaction_counts.count + 1;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_reactions_count_trigger
+AFTER INSERT OR UPDATE OF kind OR DELETE ON post_reactions
+FOR EACH ROW EXECUTE FUNCTION count_post_reactions();
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
 -- Insert users
 INSERT INTO users (id, first_name, last_name, email, username, hashed_password, birth_date, created_at, updated_at)
 VALUES
@@ -28,15 +181,59 @@ VALUES
     ('2cdc1c8f-9985-4b6c-b007-038a5bef22b5', 'Charlie', 'Brown', 'charlie@example.com', 'charlie_b', 'hashed_pwd_3', '1995-02-07', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
 ON CONFLICT (id) DO NOTHING;
 
--- Insert posts for User 1 (2 posts)
-INSERT INTO posts (id, title, extract, content, author_id, created_at, updated_at)
+-- Insert categories
+INSERT INTO categories (id, name, slug, parent_id, created_at, updated_at)
 VALUES
-    ('91c1538a-518c-4b05-9a1e-180c561a70b3', 'My First Post', 'This is my first post extract.', 'This is the full content of my first post.', '0853f607-2422-4631-8526-832edaa479c4', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
-    ('4c09ea12-30ec-4fea-a667-15be9f13e476', 'Another Day in the Life', 'A short story extract.', 'A longer text describing my second post.', '0853f607-2422-4631-8526-832edaa479c4', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
+    ('6f1d7b2e-0c1a-4b8e-9a53-1f2d3c4b5a60', 'Programming', 'programming', NULL, '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
+    ('a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01', 'Go', 'go', '6f1d7b2e-0c1a-4b8e-9a53-1f2d3c4b5a60', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
+    ('d4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12', 'Life', 'life', NULL, '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
 ON CONFLICT (id) DO NOTHING;
 
--- Insert posts for User 2 (1 post)
-INSERT INTO posts (id, title, extract, content, author_id, created_at, updated_at)
+-- Insert tags
+INSERT INTO tags (id, name, slug, created_at, updated_at)
 VALUES
-    ('bbf19b79-cf9c-4a07-8e43-299baf69b418', 'Hello World', 'My first blog entry.', 'This is the main content of my post.', 'b2ccc80d-606e-422f-a9e1-5fd7371163db', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
+    ('0e9a8b7c-6d5e-4f3a-8b1c-9d8e7f6a5b40', 'Go', 'go', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
+    ('1f0b9c8d-7e6f-4a4b-9c2d-0e9f8a7b6c51', 'Beginners', 'beginners', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
 ON CONFLICT (id) DO NOTHING;
+
+-- Insert posts for User 1 (1 published post and 1 draft)
+INSERT INTO posts (id, title, slug, extract, content, content_html, content_text, word_count, reading_minutes, author_id, status, published_at, category_id, created_at, updated_at)
+VALUES
+    ('91c1538a-518c-4b05-9a1e-180c561a70b3', 'My First Post', 'my-first-post', 'This is my first post extract.', 'This is the full content of my first post.', E'<p>This is the full content of my first post.</p>\n', 'This is the full content of my first post.', 9, 1, '0853f607-2422-4631-8526-832edaa479c4', 'published', '2006-01-02 00:00 UTC', 'a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
+    ('4c09ea12-30ec-4fea-a667-15be9f13e476', 'Another Day in the Life', 'another-day-in-the-life', 'A short story extract.', 'A longer text describing my second post.', E'<p>A longer text describing my second post.</p>\n', 'A longer text describing my second post.', 7, 1, '0853f607-2422-4631-8526-832edaa479c4', 'draft', NULL, 'd4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
+ON CONFLICT (id) DO NOTHING;
+
+-- Insert posts for User 2 (1 post, never rendered)
+INSERT INTO posts (id, title, slug, extract, content, author_id, status, published_at, category_id, created_at, updated_at)
+VALUES
+    ('bbf19b79-cf9c-4a07-8e43-299baf69b418', 'Hello World', 'hello-world', 'My first blog entry.', 'This is the main content of my post.', 'b2ccc80d-606e-422f-a9e1-5fd7371163db', 'published', '2006-01-02 00:00 UTC', NULL, '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC')
+ON CONFLICT (id) DO NOTHING;
+
+-- Tag the posts
+INSERT INTO post_tags (post_id, tag_id)
+VALUES
+    ('91c1538a-518c-4b05-9a1e-180c561a70b3', '0e9a8b7c-6d5e-4f3a-8b1c-9d8e7f6a5b40'),
+    ('91c1538a-518c-4b05-9a1e-180c561a70b3', '1f0b9c8d-7e6f-4a4b-9c2d-0e9f8a7b6c51'),
+    ('bbf19b79-cf9c-4a07-8e43-299baf69b418', '1f0b9c8d-7e6f-4a4b-9c2d-0e9f8a7b6c51')
+ON CONFLICT DO NOTHING;
+
+-- Every post starts with its current state as first revision
+INSERT INTO post_revisions (post_id, revision, title, extract, content, created_at)
+SELECT id, 1, title, extract, content, updated_at FROM posts
+ON CONFLICT DO NOTHING;
+
+-- Bob comments on the first post of Alice, who replies, and Charlie waits
+-- for moderation
+INSERT INTO comments (id, post_id, author_id, parent_id, body, status, created_at, updated_at)
+VALUES
+    ('c0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a70', '91c1538a-518c-4b05-9a1e-180c561a70b3', 'b2ccc80d-606e-422f-a9e1-5fd7371163db', NULL, 'Great post!', 'approved', '2006-01-02 00:00 UTC', '2006-01-02 00:00 UTC'),
+    ('c1b2c3d4-e5f6-4a70-9b2c-3d4e5f6a7b81', '91c1538a-518c-4b05-9a1e-180c561a70b3', '0853f607-2422-4631-8526-832edaa479c4', 'c0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a70', 'Thanks, Bob!', 'approved', '2006-01-02 00:01 UTC', '2006-01-02 00:01 UTC'),
+    ('c2c3d4e5-f6a7-4b80-8c3d-4e5f6a7b8c92', '91c1538a-518c-4b05-9a1e-180c561a70b3', '2cdc1c8f-9985-4b6c-b007-038a5bef22b5', NULL, 'First!', 'pending', '2006-01-02 00:02 UTC', '2006-01-02 00:02 UTC')
+ON CONFLICT (id) DO NOTHING;
+
+-- Bob likes the first post of Alice and Charlie loves it
+INSERT INTO post_reactions (post_id, user_id, kind, created_at)
+VALUES
+    ('91c1538a-518c-4b05-9a1e-180c561a70b3', 'b2ccc80d-606e-422f-a9e1-5fd7371163db', 'like', '2006-01-02 00:00 UTC'),
+    ('91c1538a-518c-4b05-9a1e-180c561a70b3', '2cdc1c8f-9985-4b6c-b007-038a5bef22b5', 'love', '2006-01-02 00:00 UTC')
+ON CONFLICT DO NOTHING;
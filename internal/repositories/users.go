@@ -2,11 +2,12 @@ package repositories
 
 import (
 	"context"
-	"errors"
 	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
 	"github.com/gera9/blog/pkg/postgres"
+	"github.com/gera9/blog/pkg/query"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 )
@@ -33,10 +34,13 @@ func (r UsersRepository) CreateUser(ctx context.Context, user models.User) (uuid
 	if user.UpdatedAt.IsZero() {
 		user.UpdatedAt = now
 	}
+	if user.Role == "" {
+		user.Role = models.RoleAuthor
+	}
 
 	sql := `INSERT INTO ` + r.tableName + ` (
-		first_name, last_name, email, username, hashed_password, birth_date, created_at, updated_at
-	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`
+		first_name, last_name, email, username, hashed_password, role, birth_date, created_at, updated_at
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`
 
 	var returnedID uuid.UUID
 	err := r.conn.Pool().QueryRow(ctx, sql,
@@ -45,25 +49,45 @@ func (r UsersRepository) CreateUser(ctx context.Context, user models.User) (uuid
 		user.Email,
 		user.Username,
 		user.HashedPassword,
+		user.Role,
 		user.BirthDate,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&returnedID)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, translateError(err)
 	}
 
 	return returnedID, nil
 }
 
-func (r UsersRepository) FindAllUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
-	sql := `SELECT id, first_name, last_name, email, username, hashed_password, birth_date, created_at, updated_at
-	FROM ` + r.tableName + ` ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+// userFieldColumns are the fields users can be sorted and filtered on. Like
+// models.UserQuerySchema, they leave out the private fields, which would
+// otherwise leak through the results of filters.
+var userFieldColumns = columnsOf(models.UserQuerySchema, nil)
 
-	rows, err := r.conn.Pool().Query(ctx, sql, limit, offset)
+func (r UsersRepository) FindAllUsers(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, error) {
+	conditions, args, err := userFieldColumns.conditions(filters, 1)
 	if err != nil {
 		return nil, err
 	}
+
+	condition, tail, pageArgs, err := pageClauses(page, userFieldColumns, len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, pageArgs...)
+
+	sql := `SELECT id, first_name, last_name, email, username, hashed_password, role, birth_date, created_at, updated_at
+	FROM ` + r.tableName + whereClause(conditions) + tail
+
+	rows, err := r.conn.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	defer rows.Close()
 
 	users := make([]models.User, 0)
@@ -75,13 +99,14 @@ func (r UsersRepository) FindAllUsers(ctx context.Context, limit, offset int) ([
 			email      string
 			username   string
 			hashedPass string
+			role       models.Role
 			birthDate  time.Time
 			createdAt  time.Time
 			updatedAt  time.Time
 		)
 
-		if err := rows.Scan(&id, &firstName, &lastName, &email, &username, &hashedPass, &birthDate, &createdAt, &updatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&id, &firstName, &lastName, &email, &username, &hashedPass, &role, &birthDate, &createdAt, &updatedAt); err != nil {
+			return nil, translateError(err)
 		}
 
 		createdAt = createdAt.UTC()
@@ -94,6 +119,7 @@ func (r UsersRepository) FindAllUsers(ctx context.Context, limit, offset int) ([
 			Email:          email,
 			Username:       username,
 			HashedPassword: hashedPass,
+			Role:           role,
 			BirthDate:      birthDate,
 			CreatedAt:      createdAt,
 			UpdatedAt:      updatedAt,
@@ -101,14 +127,31 @@ func (r UsersRepository) FindAllUsers(ctx context.Context, limit, offset int) ([
 	}
 
 	if rows.Err() != nil {
-		return nil, rows.Err()
+		return nil, translateError(rows.Err())
+	}
+
+	return orderPage(page, users), nil
+}
+
+func (r UsersRepository) CountUsers(ctx context.Context, filters []query.Filter) (int, error) {
+	conditions, args, err := userFieldColumns.conditions(filters, 1)
+	if err != nil {
+		return 0, err
 	}
 
-	return users, nil
+	sql := `SELECT count(*) FROM ` + r.tableName + whereClause(conditions)
+
+	var count int
+	err = r.conn.Pool().QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return count, nil
 }
 
 func (r UsersRepository) FindUserById(ctx context.Context, id uuid.UUID) (models.User, error) {
-	sql := `SELECT id, first_name, last_name, email, username, hashed_password, birth_date, created_at, updated_at
+	sql := `SELECT id, first_name, last_name, email, username, hashed_password, role, birth_date, created_at, updated_at
 	FROM ` + r.tableName + ` WHERE id = $1`
 
 	var (
@@ -118,14 +161,15 @@ func (r UsersRepository) FindUserById(ctx context.Context, id uuid.UUID) (models
 		email      string
 		username   string
 		hashedPass string
+		role       models.Role
 		birthDate  time.Time
 		createdAt  time.Time
 		updatedAt  time.Time
 	)
 
-	err := r.conn.Pool().QueryRow(ctx, sql, id).Scan(&uuid, &firstName, &lastName, &email, &username, &hashedPass, &birthDate, &createdAt, &updatedAt)
+	err := r.conn.Pool().QueryRow(ctx, sql, id).Scan(&uuid, &firstName, &lastName, &email, &username, &hashedPass, &role, &birthDate, &createdAt, &updatedAt)
 	if err != nil {
-		return models.User{}, err
+		return models.User{}, translateError(err)
 	}
 
 	createdAt = createdAt.UTC()
@@ -138,12 +182,43 @@ func (r UsersRepository) FindUserById(ctx context.Context, id uuid.UUID) (models
 		Email:          email,
 		Username:       username,
 		HashedPassword: hashedPass,
+		Role:           role,
 		BirthDate:      birthDate,
 		CreatedAt:      createdAt,
 		UpdatedAt:      updatedAt,
 	}, nil
 }
 
+func (r UsersRepository) FindUserByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (models.User, error) {
+	sql := `SELECT id, first_name, last_name, email, username, hashed_password, role, birth_date, created_at, updated_at
+	FROM ` + r.tableName + ` WHERE username = $1 OR lower(email) = lower($1)`
+
+	var user models.User
+	err := r.conn.Pool().QueryRow(ctx, sql, usernameOrEmail).Scan(
+		&user.Id,
+		&user.FirstName,
+		&user.LastName,
+		&user.Email,
+		&user.Username,
+		&user.HashedPassword,
+		&user.Role,
+		&user.BirthDate,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return models.User{}, translateError(err)
+	}
+
+	user.CreatedAt = user.CreatedAt.UTC()
+	user.UpdatedAt = user.UpdatedAt.UTC()
+
+	return user, nil
+}
+
+// UpdateUserById only applies while the user is still at the version it was
+// read at, user.UpdatedAt, so that concurrent updates cannot overwrite each
+// other, and fails with domain.ErrModified otherwise.
 func (r UsersRepository) UpdateUserById(ctx context.Context, id uuid.UUID, user models.User) error {
 	// update the allowed fields and updated_at
 	now := r.timeProvider.Now().UTC()
@@ -155,7 +230,7 @@ func (r UsersRepository) UpdateUserById(ctx context.Context, id uuid.UUID, user
 		username = $4,
 		hashed_password = $5,
 		updated_at = $6
-	WHERE id = $7`
+	WHERE id = $7 AND updated_at = $8`
 
 	tag, err := r.conn.Pool().Exec(ctx, sql,
 		user.FirstName,
@@ -165,29 +240,78 @@ func (r UsersRepository) UpdateUserById(ctx context.Context, id uuid.UUID, user
 		user.HashedPassword,
 		now,
 		id,
+		user.UpdatedAt,
 	)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	if tag.RowsAffected() == 0 {
-		return errors.New("no rows updated")
+		return r.notFoundOrModified(ctx, id)
 	}
 
 	return nil
 }
 
-func (r UsersRepository) DeleteUserById(ctx context.Context, id uuid.UUID) error {
-	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1`
+func (r UsersRepository) UpdateUserPasswordById(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	sql := `UPDATE ` + r.tableName + ` SET hashed_password = $1 WHERE id = $2`
 
-	tag, err := r.conn.Pool().Exec(ctx, sql, id)
+	tag, err := r.conn.Pool().Exec(ctx, sql, hashedPassword, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	if tag.RowsAffected() == 0 {
-		return errors.New("no rows deleted")
+		return domain.ErrNotFound
 	}
 
 	return nil
 }
+
+func (r UsersRepository) UpdateUserRoleById(ctx context.Context, id uuid.UUID, role models.Role) error {
+	sql := `UPDATE ` + r.tableName + ` SET role = $1, updated_at = $2 WHERE id = $3`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql, role, r.timeProvider.Now().UTC(), id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteUserById deletes the user, provided it is still at version when
+// given and failing with domain.ErrModified otherwise.
+func (r UsersRepository) DeleteUserById(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	sql := `DELETE FROM ` + r.tableName + ` WHERE id = $1 AND ($2::timestamptz IS NULL OR updated_at = $2)`
+
+	tag, err := r.conn.Pool().Exec(ctx, sql, id, version)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return r.notFoundOrModified(ctx, id)
+	}
+
+	return nil
+}
+
+// notFoundOrModified tells why a write conditioned on the version of the user
+// matched no row.
+func (r UsersRepository) notFoundOrModified(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := r.conn.Pool().QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM `+r.tableName+` WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if exists {
+		return domain.ErrModified
+	}
+
+	return domain.ErrNotFound
+}
@@ -6,8 +6,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
 	"github.com/gera9/blog/internal/repositories"
+	"github.com/gera9/blog/pkg/query"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -78,13 +80,29 @@ func (s *usersTestsSuite) TestCreateUser() {
 				},
 			},
 		},
+		{
+			name: "Should report a taken username as a conflict",
+			args: args{
+				ctx: context.TODO(),
+				user: models.User{
+					FirstName:      "Alice",
+					LastName:       "Again",
+					Email:          "alice.again@example.com",
+					Username:       "alice_s",
+					HashedPassword: "hashed_pwd",
+					BirthDate:      time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr: true,
+			err:     domain.ErrConflict,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			insertedId, gotErr := s.usersRepo.CreateUser(tt.args.ctx, tt.args.user)
 			if tt.wantErr {
-				assertions.Error(gotErr)
-				require.Equal(t, tt.err, gotErr)
+				require.ErrorIs(t, gotErr, tt.err)
+				return
 			}
 
 			assertions.NoError(gotErr)
@@ -99,9 +117,8 @@ func (s *usersTestsSuite) TestFindAllUsers() {
 	assertions := assert.New(t)
 
 	type args struct {
-		ctx    context.Context
-		limit  int
-		offset int
+		ctx  context.Context
+		page models.Page
 	}
 	tests := []struct {
 		name    string
@@ -113,22 +130,10 @@ func (s *usersTestsSuite) TestFindAllUsers() {
 		{
 			name: "Should return a list of users",
 			args: args{
-				ctx:    context.TODO(),
-				limit:  100,
-				offset: 0,
+				ctx:  context.TODO(),
+				page: models.Page{Limit: 100},
 			},
 			want: []models.User{
-				{
-					Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
-					FirstName:      "Alice",
-					LastName:       "Smith",
-					Email:          "alice@example.com",
-					Username:       "alice_s",
-					HashedPassword: "hashed_pwd_1",
-					BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
-					CreatedAt:      commonTime,
-					UpdatedAt:      commonTime,
-				},
 				{
 					Id:             uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
 					FirstName:      "Bob",
@@ -136,6 +141,7 @@ func (s *usersTestsSuite) TestFindAllUsers() {
 					Email:          "bob@example.com",
 					Username:       "bobby_j",
 					HashedPassword: "hashed_pwd_2",
+					Role:           models.RoleAuthor,
 					BirthDate:      time.Date(1988, time.September, 25, 0, 0, 0, 0, time.UTC),
 					CreatedAt:      commonTime,
 					UpdatedAt:      commonTime,
@@ -147,16 +153,29 @@ func (s *usersTestsSuite) TestFindAllUsers() {
 					Email:          "charlie@example.com",
 					Username:       "charlie_b",
 					HashedPassword: "hashed_pwd_3",
+					Role:           models.RoleAuthor,
 					BirthDate:      time.Date(1995, time.February, 7, 0, 0, 0, 0, time.UTC),
 					CreatedAt:      commonTime,
 					UpdatedAt:      commonTime,
 				},
+				{
+					Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					FirstName:      "Alice",
+					LastName:       "Smith",
+					Email:          "alice@example.com",
+					Username:       "alice_s",
+					HashedPassword: "hashed_pwd_1",
+					Role:           models.RoleAuthor,
+					BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
+					CreatedAt:      commonTime,
+					UpdatedAt:      commonTime,
+				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := s.usersRepo.FindAllUsers(tt.args.ctx, tt.args.limit, tt.args.offset)
+			got, gotErr := s.usersRepo.FindAllUsers(tt.args.ctx, tt.args.page, nil)
 			if tt.wantErr {
 				assertions.Error(gotErr)
 				require.Equal(t, tt.err, gotErr)
@@ -169,6 +188,34 @@ func (s *usersTestsSuite) TestFindAllUsers() {
 	}
 }
 
+func (s *usersTestsSuite) TestFindAllUsersQuery() {
+	t := s.T()
+
+	page := models.Page{Limit: 10, Sort: []query.Sort{{Field: "first_name", Desc: true}}}
+	filters := []query.Filter{{Field: "last_name", Op: query.OpNe, Value: "Smith"}}
+
+	users, err := s.usersRepo.FindAllUsers(context.TODO(), page, filters)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "Charlie", users[0].FirstName)
+	assert.Equal(t, "Bob", users[1].FirstName)
+
+	count, err := s.usersRepo.CountUsers(context.TODO(), filters)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = s.usersRepo.FindAllUsers(context.TODO(), models.Page{Limit: 10}, []query.Filter{{Field: "email", Op: query.OpEq, Value: "bob@example.com"}})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func (s *usersTestsSuite) TestCountUsers() {
+	t := s.T()
+
+	count, err := s.usersRepo.CountUsers(context.TODO(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
 func (s *usersTestsSuite) TestFindUserById() {
 	t := s.T()
 
@@ -195,6 +242,7 @@ func (s *usersTestsSuite) TestFindUserById() {
 				Email:          "alice@example.com",
 				Username:       "alice_s",
 				HashedPassword: "hashed_pwd_1",
+				Role:           models.RoleAuthor,
 				BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
 				CreatedAt:      commonTime,
 				UpdatedAt:      commonTime,
@@ -215,6 +263,71 @@ func (s *usersTestsSuite) TestFindUserById() {
 	}
 }
 
+func (s *usersTestsSuite) TestFindUserByUsernameOrEmail() {
+	t := s.T()
+
+	alice := models.User{
+		Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		FirstName:      "Alice",
+		LastName:       "Smith",
+		Email:          "alice@example.com",
+		Username:       "alice_s",
+		HashedPassword: "hashed_pwd_1",
+		Role:           models.RoleAuthor,
+		BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
+		CreatedAt:      commonTime,
+		UpdatedAt:      commonTime,
+	}
+
+	type args struct {
+		ctx             context.Context
+		usernameOrEmail string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    models.User
+		wantErr bool
+	}{
+		{
+			name: "Should find an user by username",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "alice_s",
+			},
+			want: alice,
+		},
+		{
+			name: "Should find an user by email ignoring case",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "Alice@Example.com",
+			},
+			want: alice,
+		},
+		{
+			name: "Should fail when the user does not exist",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "nobody",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.usersRepo.FindUserByUsernameOrEmail(tt.args.ctx, tt.args.usernameOrEmail)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Repositories.FindUserByUsernameOrEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repositories.FindUserByUsernameOrEmail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func (s *usersTestsSuite) TestUpdateUserById() {
 	t := s.T()
 
@@ -240,12 +353,37 @@ func (s *usersTestsSuite) TestUpdateUserById() {
 					Email:          "alice@gmail.com",
 					Username:       "alice_s",
 					HashedPassword: "hashed_pwd_1",
+					Role:           models.RoleAuthor,
 					BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
 					CreatedAt:      commonTime,
 					UpdatedAt:      commonTime,
 				},
 			},
 		},
+		{
+			name: "Should not update a user modified since it was read",
+			args: args{
+				ctx: context.TODO(),
+				id:  uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
+				user: models.User{
+					FirstName: "Robert",
+					LastName:  "Johnson",
+					Email:     "bob@example.com",
+					Username:  "bobby_j",
+					UpdatedAt: commonTime.Add(-time.Hour),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail when the user does not exist",
+			args: args{
+				ctx:  context.TODO(),
+				id:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+				user: models.User{UpdatedAt: commonTime},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -256,18 +394,106 @@ func (s *usersTestsSuite) TestUpdateUserById() {
 	}
 }
 
+func (s *usersTestsSuite) TestUserVersions() {
+	t := s.T()
+
+	id := uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db")
+
+	user, err := s.usersRepo.FindUserById(context.TODO(), id)
+	require.NoError(t, err)
+
+	user.FirstName = "Robert"
+	require.NoError(t, s.usersRepo.UpdateUserById(context.TODO(), id, user))
+
+	// The version read above is gone once the update went through.
+	err = s.usersRepo.UpdateUserById(context.TODO(), id, user)
+	assert.ErrorIs(t, err, domain.ErrModified)
+
+	err = s.usersRepo.DeleteUserById(context.TODO(), id, &user.UpdatedAt)
+	assert.ErrorIs(t, err, domain.ErrModified)
+
+	err = s.usersRepo.UpdateUserById(context.TODO(), uuid.New(), user)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func (s *usersTestsSuite) TestUpdateUserPasswordById() {
+	t := s.T()
+
+	type args struct {
+		ctx            context.Context
+		id             uuid.UUID
+		hashedPassword string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should update the user password",
+			args: args{
+				ctx:            context.TODO(),
+				id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				hashedPassword: "$2a$10$vgDE58sSMhGafhT94PglVujGjYG38j26NG8LcV0ixQ1gyoaEXC7j6",
+			},
+		},
+		{
+			name: "Should fail when the user does not exist",
+			args: args{
+				ctx:            context.TODO(),
+				id:             uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+				hashedPassword: "$2a$10$vgDE58sSMhGafhT94PglVujGjYG38j26NG8LcV0ixQ1gyoaEXC7j6",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.usersRepo.UpdateUserPasswordById(tt.args.ctx, tt.args.id, tt.args.hashedPassword); (err != nil) != tt.wantErr {
+				t.Errorf("Repositories.UpdateUserPasswordById() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func (s *usersTestsSuite) TestUpdateUserRoleById() {
+	t := s.T()
+
+	id := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	err := s.usersRepo.UpdateUserRoleById(context.TODO(), id, models.RoleEditor)
+	require.NoError(t, err)
+
+	got, err := s.usersRepo.FindUserById(context.TODO(), id)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleEditor, got.Role)
+
+	err = s.usersRepo.UpdateUserRoleById(context.TODO(), id, models.Role("superuser"))
+	assert.Error(t, err)
+}
+
 func (s *usersTestsSuite) TestDeleteUserById() {
 	t := s.T()
 
 	type args struct {
-		ctx context.Context
-		id  uuid.UUID
+		ctx     context.Context
+		id      uuid.UUID
+		version *time.Time
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
+		{
+			name: "Should not delete a user modified since the given version",
+			args: args{
+				ctx:     context.TODO(),
+				id:      uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+				version: utils.Ptr(commonTime.Add(-time.Hour)),
+			},
+			wantErr: true,
+		},
 		{
 			name: "Should delete user",
 			args: args{
@@ -278,7 +504,7 @@ func (s *usersTestsSuite) TestDeleteUserById() {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := s.usersRepo.DeleteUserById(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			if err := s.usersRepo.DeleteUserById(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
 				t.Errorf("Repositories.DeleteUserById() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type CredentialsVerifier interface {
+	VerifyCredentials(ctx context.Context, usernameOrEmail, password string) (models.User, error)
+}
+
+type RefreshTokensRepository interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) (uuid.UUID, error)
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID, newToken models.RefreshToken) (uuid.UUID, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyId uuid.UUID) error
+}
+
+type authService struct {
+	credentials   CredentialsVerifier
+	usersRepo     UsersRepository
+	refreshTokens RefreshTokensRepository
+	tokens        *tokenService
+	timeProvider  utils.TimeProvider
+}
+
+func NewAuthService(
+	credentials CredentialsVerifier,
+	usersRepo UsersRepository,
+	refreshTokens RefreshTokensRepository,
+	tokens *tokenService,
+	timeProvider utils.TimeProvider,
+) *authService {
+	return &authService{
+		credentials:   credentials,
+		usersRepo:     usersRepo,
+		refreshTokens: refreshTokens,
+		tokens:        tokens,
+		timeProvider:  timeProvider,
+	}
+}
+
+// Login verifies the given credentials and starts a new refresh token family.
+func (s authService) Login(ctx context.Context, usernameOrEmail, password string) (models.TokenPair, error) {
+	user, err := s.credentials.VerifyCredentials(ctx, usernameOrEmail, password)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshToken, tokenHash, expiresAt, err := s.tokens.NewRefreshToken()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	_, err = s.refreshTokens.CreateRefreshToken(ctx, models.RefreshToken{
+		UserId:    user.Id,
+		FamilyId:  uuid.New(),
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return s.tokenPair(user, refreshToken, expiresAt)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Presenting a token
+// that was already rotated is treated as theft and revokes its whole family.
+func (s authService) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	current, err := s.refreshTokens.FindRefreshTokenByHash(ctx, HashRefreshToken(refreshToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.TokenPair{}, ErrInvalidToken
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	if current.RevokedAt != nil {
+		return models.TokenPair{}, s.revokeFamily(ctx, current.FamilyId)
+	}
+
+	if !s.timeProvider.Now().Before(current.ExpiresAt) {
+		return models.TokenPair{}, ErrInvalidToken
+	}
+
+	user, err := s.usersRepo.FindUserById(ctx, current.UserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.TokenPair{}, ErrInvalidToken
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	newRefreshToken, tokenHash, expiresAt, err := s.tokens.NewRefreshToken()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	_, err = s.refreshTokens.RotateRefreshToken(ctx, current.Id, models.RefreshToken{
+		UserId:    current.UserId,
+		FamilyId:  current.FamilyId,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.TokenPair{}, s.revokeFamily(ctx, current.FamilyId)
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return s.tokenPair(user, newRefreshToken, expiresAt)
+}
+
+// Logout revokes the refresh token family the given token belongs to. Unknown
+// tokens are ignored so logging out is idempotent.
+func (s authService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.refreshTokens.FindRefreshTokenByHash(ctx, HashRefreshToken(refreshToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.refreshTokens.RevokeRefreshTokenFamily(ctx, current.FamilyId)
+}
+
+// Authenticate resolves an access token into the principal it was issued to,
+// making sure the user still exists.
+func (s authService) Authenticate(ctx context.Context, accessToken string) (models.Principal, error) {
+	claims, err := s.tokens.ParseAccessToken(accessToken)
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	user, err := s.usersRepo.FindUserById(ctx, claims.UserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.Principal{}, ErrInvalidToken
+	}
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	return models.Principal{
+		UserId:   user.Id,
+		Username: user.Username,
+		Role:     user.Role,
+	}, nil
+}
+
+func (s authService) revokeFamily(ctx context.Context, familyId uuid.UUID) error {
+	err := s.refreshTokens.RevokeRefreshTokenFamily(ctx, familyId)
+	if err != nil {
+		return err
+	}
+
+	return ErrInvalidToken
+}
+
+func (s authService) tokenPair(user models.User, refreshToken string, refreshExpiresAt time.Time) (models.TokenPair, error) {
+	accessToken, accessExpiresAt, err := s.tokens.IssueAccessToken(user.Id, user.Username)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_authService_Login(t *testing.T) {
+	alice := models.User{
+		Id:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Username: "alice_s",
+	}
+
+	type fields struct {
+		credentials   *MockCredentialsVerifier
+		refreshTokens *MockRefreshTokensRepository
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr error
+	}{
+		{
+			name: "Should issue a token pair",
+			fields: fields{
+				credentials: func() *MockCredentialsVerifier {
+					c := NewMockCredentialsVerifier(t)
+					c.On("VerifyCredentials", context.TODO(), "alice_s", "s3cr3t").Return(alice, nil)
+					return c
+				}(),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					r := NewMockRefreshTokensRepository(t)
+					r.On("CreateRefreshToken", context.TODO(), mock.MatchedBy(func(token models.RefreshToken) bool {
+						return token.UserId == alice.Id && token.FamilyId != uuid.Nil && token.TokenHash != ""
+					})).Return(uuid.New(), nil)
+					return r
+				}(),
+			},
+		},
+		{
+			name: "Should fail with invalid credentials",
+			fields: fields{
+				credentials: func() *MockCredentialsVerifier {
+					c := NewMockCredentialsVerifier(t)
+					c.On("VerifyCredentials", context.TODO(), "alice_s", "s3cr3t").Return(models.User{}, ErrInvalidCredentials)
+					return c
+				}(),
+				refreshTokens: NewMockRefreshTokensRepository(t),
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(
+				tt.fields.credentials,
+				NewMockUsersRepository(t),
+				tt.fields.refreshTokens,
+				NewTokenService(testTokenConfig, utils.MockClock{}),
+				utils.MockClock{},
+			)
+
+			got, err := s.Login(context.TODO(), "alice_s", "s3cr3t")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.AccessToken)
+			assert.NotEmpty(t, got.RefreshToken)
+		})
+	}
+}
+
+func Test_authService_Refresh(t *testing.T) {
+	now := utils.MockClock{}.Now()
+
+	alice := models.User{
+		Id:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Username: "alice_s",
+	}
+	current := models.RefreshToken{
+		Id:        uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+		UserId:    alice.Id,
+		FamilyId:  uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476"),
+		TokenHash: HashRefreshToken("refresh-token"),
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	type fields struct {
+		usersRepo     *MockUsersRepository
+		refreshTokens *MockRefreshTokensRepository
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr error
+	}{
+		{
+			name: "Should rotate the refresh token",
+			fields: fields{
+				usersRepo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					return r
+				}(),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					r := NewMockRefreshTokensRepository(t)
+					r.On("FindRefreshTokenByHash", context.TODO(), current.TokenHash).Return(current, nil)
+					r.On("RotateRefreshToken", context.TODO(), current.Id, mock.MatchedBy(func(token models.RefreshToken) bool {
+						return token.FamilyId == current.FamilyId && token.TokenHash != current.TokenHash
+					})).Return(uuid.New(), nil)
+					return r
+				}(),
+			},
+		},
+		{
+			name: "Should revoke the family when a rotated token is reused",
+			fields: fields{
+				usersRepo: NewMockUsersRepository(t),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					revoked := current
+					revoked.RevokedAt = utils.Ptr(now.Add(-time.Minute))
+
+					r := NewMockRefreshTokensRepository(t)
+					r.On("FindRefreshTokenByHash", context.TODO(), current.TokenHash).Return(revoked, nil)
+					r.On("RevokeRefreshTokenFamily", context.TODO(), current.FamilyId).Return(nil)
+					return r
+				}(),
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "Should revoke the family when a concurrent rotation won",
+			fields: fields{
+				usersRepo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					return r
+				}(),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					r := NewMockRefreshTokensRepository(t)
+					r.On("FindRefreshTokenByHash", context.TODO(), current.TokenHash).Return(current, nil)
+					r.On("RotateRefreshToken", context.TODO(), current.Id, mock.Anything).Return(uuid.Nil, domain.ErrNotFound)
+					r.On("RevokeRefreshTokenFamily", context.TODO(), current.FamilyId).Return(nil)
+					return r
+				}(),
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "Should reject an expired token",
+			fields: fields{
+				usersRepo: NewMockUsersRepository(t),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					expired := current
+					expired.ExpiresAt = now
+
+					r := NewMockRefreshTokensRepository(t)
+					r.On("FindRefreshTokenByHash", context.TODO(), current.TokenHash).Return(expired, nil)
+					return r
+				}(),
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "Should reject an unknown token",
+			fields: fields{
+				usersRepo: NewMockUsersRepository(t),
+				refreshTokens: func() *MockRefreshTokensRepository {
+					r := NewMockRefreshTokensRepository(t)
+					r.On("FindRefreshTokenByHash", context.TODO(), current.TokenHash).Return(models.RefreshToken{}, domain.ErrNotFound)
+					return r
+				}(),
+			},
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(
+				NewMockCredentialsVerifier(t),
+				tt.fields.usersRepo,
+				tt.fields.refreshTokens,
+				NewTokenService(testTokenConfig, utils.MockClock{}),
+				utils.MockClock{},
+			)
+
+			got, err := s.Refresh(context.TODO(), "refresh-token")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.AccessToken)
+			assert.NotEqual(t, "refresh-token", got.RefreshToken)
+		})
+	}
+}
+
+func Test_authService_Logout(t *testing.T) {
+	familyId := uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476")
+
+	tests := []struct {
+		name          string
+		refreshTokens *MockRefreshTokensRepository
+	}{
+		{
+			name: "Should revoke the token family",
+			refreshTokens: func() *MockRefreshTokensRepository {
+				r := NewMockRefreshTokensRepository(t)
+				r.On("FindRefreshTokenByHash", context.TODO(), HashRefreshToken("refresh-token")).Return(models.RefreshToken{FamilyId: familyId}, nil)
+				r.On("RevokeRefreshTokenFamily", context.TODO(), familyId).Return(nil)
+				return r
+			}(),
+		},
+		{
+			name: "Should ignore unknown tokens",
+			refreshTokens: func() *MockRefreshTokensRepository {
+				r := NewMockRefreshTokensRepository(t)
+				r.On("FindRefreshTokenByHash", context.TODO(), HashRefreshToken("refresh-token")).Return(models.RefreshToken{}, domain.ErrNotFound)
+				return r
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(
+				NewMockCredentialsVerifier(t),
+				NewMockUsersRepository(t),
+				tt.refreshTokens,
+				NewTokenService(testTokenConfig, utils.MockClock{}),
+				utils.MockClock{},
+			)
+
+			assert.NoError(t, s.Logout(context.TODO(), "refresh-token"))
+		})
+	}
+}
+
+func Test_authService_Authenticate(t *testing.T) {
+	alice := models.User{
+		Id:       uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Username: "alice_s",
+	}
+
+	tokens := NewTokenService(testTokenConfig, utils.MockClock{})
+	accessToken, _, err := tokens.IssueAccessToken(alice.Id, alice.Username)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name        string
+		usersRepo   *MockUsersRepository
+		accessToken string
+		want        models.Principal
+		wantErr     error
+	}{
+		{
+			name: "Should return the principal of a valid token",
+			usersRepo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+				return r
+			}(),
+			accessToken: accessToken,
+			want:        models.Principal{UserId: alice.Id, Username: alice.Username},
+		},
+		{
+			name: "Should reject a token whose user no longer exists",
+			usersRepo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("FindUserById", context.TODO(), alice.Id).Return(models.User{}, domain.ErrNotFound)
+				return r
+			}(),
+			accessToken: accessToken,
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "Should reject an invalid token",
+			usersRepo:   NewMockUsersRepository(t),
+			accessToken: "not-a-token",
+			wantErr:     ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(
+				NewMockCredentialsVerifier(t),
+				tt.usersRepo,
+				NewMockRefreshTokensRepository(t),
+				tokens,
+				utils.MockClock{},
+			)
+
+			got, err := s.Authenticate(context.TODO(), tt.accessToken)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
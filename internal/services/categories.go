@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrCategoryCycle = domain.FieldsErrorf(domain.ErrValidation, map[string]string{"parent_id": "must not be the category or one of its subcategories"}, "category cannot be its own ancestor")
+
+type CategoriesRepository interface {
+	CreateCategory(ctx context.Context, category models.Category) (uuid.UUID, error)
+	FindAllCategories(ctx context.Context) ([]models.Category, error)
+	FindCategoryById(ctx context.Context, id uuid.UUID) (models.Category, error)
+	FindCategoryAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	CountCategoryChildren(ctx context.Context, id uuid.UUID) (int, error)
+	UpdateCategoryById(ctx context.Context, id uuid.UUID, category models.Category) error
+	DeleteCategoryById(ctx context.Context, id uuid.UUID) error
+}
+
+type categoriesService struct {
+	repo CategoriesRepository
+}
+
+func NewCategoriesService(repo CategoriesRepository) *categoriesService {
+	return &categoriesService{repo: repo}
+}
+
+// CreateCategory stores category under a slug derived from its name.
+func (s categoriesService) CreateCategory(ctx context.Context, category models.Category) (uuid.UUID, error) {
+	var err error
+	category.Slug, err = taxonomySlug(category.Name)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return s.repo.CreateCategory(ctx, category)
+}
+
+// FindAllCategories returns every category, parents before their children.
+func (s categoriesService) FindAllCategories(ctx context.Context) ([]models.Category, error) {
+	return s.repo.FindAllCategories(ctx)
+}
+
+func (s categoriesService) FindCategoryById(ctx context.Context, id uuid.UUID) (models.Category, error) {
+	return s.repo.FindCategoryById(ctx, id)
+}
+
+// UpdateCategoryById renames the category or moves it under another parent,
+// or back to the root when the new parent id is uuid.Nil. A category cannot be
+// moved under itself or any of its subcategories.
+func (s categoriesService) UpdateCategoryById(ctx context.Context, id uuid.UUID, newCategory models.Category) error {
+	category, err := s.repo.FindCategoryById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if newCategory.Name != "" {
+		category.Name = newCategory.Name
+		category.Slug, err = taxonomySlug(category.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case newCategory.ParentId != nil && *newCategory.ParentId == uuid.Nil:
+		category.ParentId = nil
+	case newCategory.ParentId != nil:
+		parentId := *newCategory.ParentId
+		if parentId == id {
+			return ErrCategoryCycle
+		}
+
+		ancestors, err := s.repo.FindCategoryAncestorIds(ctx, parentId)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(ancestors, id) {
+			return ErrCategoryCycle
+		}
+
+		category.ParentId = newCategory.ParentId
+	}
+
+	return s.repo.UpdateCategoryById(ctx, id, category)
+}
+
+// DeleteCategoryById deletes a category without subcategories. Posts in it
+// are left without a category.
+func (s categoriesService) DeleteCategoryById(ctx context.Context, id uuid.UUID) error {
+	children, err := s.repo.CountCategoryChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return domain.Errorf(domain.ErrConflict, "category has subcategories")
+	}
+
+	return s.repo.DeleteCategoryById(ctx, id)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	programmingCategory = models.Category{
+		Id:        uuid.MustParse("6f1d7b2e-0c1a-4b8e-9a53-1f2d3c4b5a60"),
+		Name:      "Programming",
+		Slug:      "programming",
+		CreatedAt: commonTime,
+		UpdatedAt: commonTime,
+	}
+	goCategory = models.Category{
+		Id:        uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01"),
+		Name:      "Go",
+		Slug:      "go",
+		ParentId:  utils.Ptr(programmingCategory.Id),
+		CreatedAt: commonTime,
+		UpdatedAt: commonTime,
+	}
+	lifeCategory = models.Category{
+		Id:        uuid.MustParse("d4e6f8a0-3c5e-4a7b-9d1f-2b4c6d8e0f12"),
+		Name:      "Life",
+		Slug:      "life",
+		CreatedAt: commonTime,
+		UpdatedAt: commonTime,
+	}
+)
+
+func Test_categoriesService_CreateCategory(t *testing.T) {
+	r := NewMockCategoriesRepository(t)
+	r.On("CreateCategory", context.TODO(), models.Category{Name: "Go", Slug: "go", ParentId: goCategory.ParentId}).Return(goCategory.Id, nil)
+
+	s := NewCategoriesService(r)
+
+	id, err := s.CreateCategory(context.TODO(), models.Category{Name: "Go", ParentId: goCategory.ParentId})
+	assert.NoError(t, err)
+	assert.Equal(t, goCategory.Id, id)
+}
+
+func Test_categoriesService_UpdateCategoryById(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		category models.Category
+		setup    func(r *MockCategoriesRepository)
+		wantErr  error
+	}{
+		{
+			name:     "Should move a category under another parent",
+			id:       goCategory.Id,
+			category: models.Category{ParentId: utils.Ptr(lifeCategory.Id)},
+			setup: func(r *MockCategoriesRepository) {
+				r.On("FindCategoryById", context.TODO(), goCategory.Id).Return(goCategory, nil)
+				r.On("FindCategoryAncestorIds", context.TODO(), lifeCategory.Id).Return([]uuid.UUID{}, nil)
+
+				moved := goCategory
+				moved.ParentId = utils.Ptr(lifeCategory.Id)
+				r.On("UpdateCategoryById", context.TODO(), goCategory.Id, moved).Return(nil)
+			},
+		},
+		{
+			name:     "Should move a category back to the root",
+			id:       goCategory.Id,
+			category: models.Category{ParentId: &uuid.Nil},
+			setup: func(r *MockCategoriesRepository) {
+				r.On("FindCategoryById", context.TODO(), goCategory.Id).Return(goCategory, nil)
+
+				moved := goCategory
+				moved.ParentId = nil
+				r.On("UpdateCategoryById", context.TODO(), goCategory.Id, moved).Return(nil)
+			},
+		},
+		{
+			name:     "Should rename a category",
+			id:       lifeCategory.Id,
+			category: models.Category{Name: "Day to Day"},
+			setup: func(r *MockCategoriesRepository) {
+				r.On("FindCategoryById", context.TODO(), lifeCategory.Id).Return(lifeCategory, nil)
+
+				renamed := lifeCategory
+				renamed.Name = "Day to Day"
+				renamed.Slug = "day-to-day"
+				r.On("UpdateCategoryById", context.TODO(), lifeCategory.Id, renamed).Return(nil)
+			},
+		},
+		{
+			name:     "Should not make a category its own parent",
+			id:       goCategory.Id,
+			category: models.Category{ParentId: utils.Ptr(goCategory.Id)},
+			setup: func(r *MockCategoriesRepository) {
+				r.On("FindCategoryById", context.TODO(), goCategory.Id).Return(goCategory, nil)
+			},
+			wantErr: ErrCategoryCycle,
+		},
+		{
+			name:     "Should not move a category under one of its subcategories",
+			id:       programmingCategory.Id,
+			category: models.Category{ParentId: utils.Ptr(goCategory.Id)},
+			setup: func(r *MockCategoriesRepository) {
+				r.On("FindCategoryById", context.TODO(), programmingCategory.Id).Return(programmingCategory, nil)
+				r.On("FindCategoryAncestorIds", context.TODO(), goCategory.Id).Return([]uuid.UUID{programmingCategory.Id}, nil)
+			},
+			wantErr: ErrCategoryCycle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockCategoriesRepository(t)
+			tt.setup(r)
+
+			s := NewCategoriesService(r)
+
+			err := s.UpdateCategoryById(context.TODO(), tt.id, tt.category)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_categoriesService_DeleteCategoryById(t *testing.T) {
+	t.Run("Should refuse to delete a category with subcategories", func(t *testing.T) {
+		r := NewMockCategoriesRepository(t)
+		r.On("CountCategoryChildren", context.TODO(), programmingCategory.Id).Return(1, nil)
+
+		err := NewCategoriesService(r).DeleteCategoryById(context.TODO(), programmingCategory.Id)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("Should delete a leaf category", func(t *testing.T) {
+		r := NewMockCategoriesRepository(t)
+		r.On("CountCategoryChildren", context.TODO(), goCategory.Id).Return(0, nil)
+		r.On("DeleteCategoryById", context.TODO(), goCategory.Id).Return(nil)
+
+		err := NewCategoriesService(r).DeleteCategoryById(context.TODO(), goCategory.Id)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var ErrUnknownParentComment = domain.FieldsErrorf(domain.ErrValidation, map[string]string{"parent_id": "does not exist"}, "the comment replied to does not exist")
+
+type CommentsRepository interface {
+	CreateComment(ctx context.Context, comment models.Comment) (uuid.UUID, error)
+	FindPostComments(ctx context.Context, postId, viewerId uuid.UUID) ([]models.Comment, error)
+	FindCommentsByStatus(ctx context.Context, status models.CommentStatus, limit, offset int) ([]models.Comment, error)
+	FindCommentById(ctx context.Context, id uuid.UUID) (models.Comment, error)
+	CountApprovedCommentsByAuthor(ctx context.Context, authorId uuid.UUID) (int, error)
+	UpdateCommentById(ctx context.Context, id uuid.UUID, comment models.Comment) error
+	UpdateCommentStatusById(ctx context.Context, id uuid.UUID, from models.CommentStatus, comment models.Comment) error
+	DeleteCommentById(ctx context.Context, id uuid.UUID) error
+}
+
+type commentsService struct {
+	repo   CommentsRepository
+	posts  PostsRepository
+	spam   SpamScorer
+	policy *policy
+}
+
+func NewCommentsService(repo CommentsRepository, posts PostsRepository, spam SpamScorer, policy *policy) *commentsService {
+	return &commentsService{repo: repo, posts: posts, spam: spam, policy: policy}
+}
+
+// CreateComment adds comment to a published post on behalf of principal, as
+// a reply when it has a parent, which must be an approved comment of the same
+// post. It returns the status the comment landed in, which is pending when it
+// needs a moderator: on moderated posts, for first-time commenters and when
+// it looks like spam. Comments of moderators and of the author of the post
+// never wait.
+func (s commentsService) CreateComment(ctx context.Context, principal models.Principal, comment models.Comment) (uuid.UUID, models.CommentStatus, error) {
+	post, err := s.findReadablePost(ctx, principal, comment.PostId)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	if !s.policy.Can(principal, PermCommentsCreate) {
+		return uuid.Nil, "", domain.ErrForbidden
+	}
+
+	if post.Status != models.PostStatusPublished {
+		return uuid.Nil, "", domain.Errorf(domain.ErrConflict, "cannot comment on a %s post", post.Status)
+	}
+
+	if post.CommentMode == models.CommentModeClosed {
+		return uuid.Nil, "", domain.Errorf(domain.ErrConflict, "comments are closed on this post")
+	}
+
+	if comment.ParentId != nil {
+		parent, err := s.repo.FindCommentById(ctx, *comment.ParentId)
+		if errors.Is(err, domain.ErrNotFound) {
+			return uuid.Nil, "", ErrUnknownParentComment
+		}
+		if err != nil {
+			return uuid.Nil, "", err
+		}
+
+		if parent.PostId != post.Id || parent.Status != models.CommentStatusApproved {
+			return uuid.Nil, "", ErrUnknownParentComment
+		}
+	}
+
+	comment.AuthorId = principal.UserId
+
+	err = s.screen(ctx, principal, post, &comment)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	id, err := s.repo.CreateComment(ctx, comment)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	return id, comment.Status, nil
+}
+
+// FindPostComments returns the approved comments of the post, along with the
+// pending ones of principal, parents before their replies, as long as
+// principal may read the post.
+func (s commentsService) FindPostComments(ctx context.Context, principal models.Principal, postId uuid.UUID) ([]models.Comment, error) {
+	_, err := s.findReadablePost(ctx, principal, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindPostComments(ctx, postId, principal.UserId)
+}
+
+// UpdateCommentById edits the body of the comment, which only its author may
+// do while the post takes comments. An approved comment goes back to
+// moderation when its new body looks like spam or the post is moderated,
+// unless its author could have skipped moderation in the first place.
+func (s commentsService) UpdateCommentById(ctx context.Context, principal models.Principal, postId, id uuid.UUID, newComment models.Comment) error {
+	post, comment, err := s.findComment(ctx, principal, postId, id)
+	if err != nil {
+		return err
+	}
+
+	owned := comment.AuthorId == principal.UserId
+	if !owned || !s.policy.Can(principal, PermCommentsUpdateOwn) {
+		return domain.ErrForbidden
+	}
+
+	if post.CommentMode == models.CommentModeClosed {
+		return domain.Errorf(domain.ErrConflict, "comments are closed on this post")
+	}
+
+	comment.Body = newComment.Body
+
+	verdict, err := s.spam.ScoreComment(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	comment.SpamScore, comment.SpamReasons = verdict.Score, verdict.Reasons
+
+	exempt := post.AuthorId == principal.UserId || s.policy.Can(principal, PermCommentsModerate)
+	held := verdict.Spam || post.CommentMode == models.CommentModeModerated
+	if held && !exempt && comment.Status == models.CommentStatusApproved {
+		comment.Status = models.CommentStatusPending
+	}
+
+	return s.repo.UpdateCommentById(ctx, id, comment)
+}
+
+// DeleteCommentById deletes the comment and its replies on behalf of
+// principal, who must be its author or be allowed to delete any comment.
+func (s commentsService) DeleteCommentById(ctx context.Context, principal models.Principal, postId, id uuid.UUID) error {
+	_, comment, err := s.findComment(ctx, principal, postId, id)
+	if err != nil {
+		return err
+	}
+
+	owned := comment.AuthorId == principal.UserId
+	if !s.policy.CanOnOwned(principal, PermCommentsDeleteOwn, PermCommentsDeleteAny, owned) {
+		return domain.ErrForbidden
+	}
+
+	return s.repo.DeleteCommentById(ctx, id)
+}
+
+// FindCommentsByStatus lists the comments of every post in status, oldest
+// first, for moderators to work through.
+func (s commentsService) FindCommentsByStatus(ctx context.Context, principal models.Principal, status models.CommentStatus, limit, offset int) ([]models.Comment, error) {
+	if !s.policy.Can(principal, PermCommentsModerate) {
+		return nil, domain.ErrForbidden
+	}
+
+	return s.repo.FindCommentsByStatus(ctx, status, limit, offset)
+}
+
+// ApproveComment shows a pending or rejected comment.
+func (s commentsService) ApproveComment(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.moderate(ctx, principal, id, models.CommentStatusApproved)
+}
+
+// RejectComment hides a pending or approved comment, along with its replies.
+func (s commentsService) RejectComment(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.moderate(ctx, principal, id, models.CommentStatusRejected)
+}
+
+func (s commentsService) moderate(ctx context.Context, principal models.Principal, id uuid.UUID, to models.CommentStatus) error {
+	if !s.policy.Can(principal, PermCommentsModerate) {
+		return domain.ErrForbidden
+	}
+
+	comment, err := s.repo.FindCommentById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	from := comment.Status
+	if from == to {
+		return domain.Errorf(domain.ErrConflict, "comment is already %s", to)
+	}
+
+	comment.Status = to
+	comment.ModeratedBy = utils.Ptr(principal.UserId)
+
+	return s.repo.UpdateCommentStatusById(ctx, id, from, comment)
+}
+
+// screen scores comment for spam and decides whether it waits for a
+// moderator.
+func (s commentsService) screen(ctx context.Context, principal models.Principal, post models.Post, comment *models.Comment) error {
+	verdict, err := s.spam.ScoreComment(ctx, *comment)
+	if err != nil {
+		return err
+	}
+
+	comment.SpamScore, comment.SpamReasons = verdict.Score, verdict.Reasons
+	comment.Status = models.CommentStatusApproved
+
+	if post.AuthorId == principal.UserId || s.policy.Can(principal, PermCommentsModerate) {
+		return nil
+	}
+
+	if post.CommentMode == models.CommentModeModerated || verdict.Spam {
+		comment.Status = models.CommentStatusPending
+		return nil
+	}
+
+	approved, err := s.repo.CountApprovedCommentsByAuthor(ctx, principal.UserId)
+	if err != nil {
+		return err
+	}
+
+	if approved == 0 {
+		comment.Status = models.CommentStatusPending
+	}
+
+	return nil
+}
+
+// findReadablePost returns the post, reported as not found when principal
+// may not read it.
+func (s commentsService) findReadablePost(ctx context.Context, principal models.Principal, postId uuid.UUID) (models.Post, error) {
+	post, err := s.posts.FindPostById(ctx, postId)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	if !canReadPost(s.policy, principal, post) {
+		return models.Post{}, domain.ErrNotFound
+	}
+
+	return post, nil
+}
+
+// findComment returns the comment with the given id, along with its post, if
+// it belongs to a post that principal may read.
+func (s commentsService) findComment(ctx context.Context, principal models.Principal, postId, id uuid.UUID) (models.Post, models.Comment, error) {
+	post, err := s.findReadablePost(ctx, principal, postId)
+	if err != nil {
+		return models.Post{}, models.Comment{}, err
+	}
+
+	comment, err := s.repo.FindCommentById(ctx, id)
+	if err != nil {
+		return models.Post{}, models.Comment{}, err
+	}
+
+	if comment.PostId != postId {
+		return models.Post{}, models.Comment{}, domain.ErrNotFound
+	}
+
+	return post, comment, nil
+}
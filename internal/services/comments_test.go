@@ -0,0 +1,470 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	bobComment = models.Comment{
+		Id:        uuid.MustParse("c0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a70"),
+		PostId:    alicePost.Id,
+		AuthorId:  bobPrincipal.UserId,
+		Body:      "Great post!",
+		Status:    models.CommentStatusApproved,
+		CreatedAt: commonTime,
+		UpdatedAt: commonTime,
+	}
+	aliceReply = models.Comment{
+		Id:        uuid.MustParse("c1b2c3d4-e5f6-4a70-9b2c-3d4e5f6a7b81"),
+		PostId:    alicePost.Id,
+		AuthorId:  alicePrincipal.UserId,
+		ParentId:  &bobComment.Id,
+		Body:      "Thanks, Bob!",
+		Status:    models.CommentStatusApproved,
+		Depth:     1,
+		CreatedAt: commonTime,
+		UpdatedAt: commonTime,
+	}
+)
+
+func Test_commentsService_CreateComment(t *testing.T) {
+	id := uuid.MustParse("5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d")
+	charliePrincipal := models.Principal{UserId: uuid.MustParse("2cdc1c8f-9985-4b6c-b007-038a5bef22b5"), Role: models.RoleAuthor}
+	clean := models.SpamVerdict{Reasons: []string{}}
+	spam := models.SpamVerdict{Score: 1, Reasons: []string{"duplicate"}, Spam: true}
+
+	moderated := alicePost
+	moderated.CommentMode = models.CommentModeModerated
+	closed := alicePost
+	closed.CommentMode = models.CommentModeClosed
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		post      models.Post
+		verdict   models.SpamVerdict
+		// approved is how many approved comments the principal has, -1 when
+		// nobody should ask.
+		approved   int
+		wantStatus models.CommentStatus
+		wantErr    error
+	}{
+		{
+			name:       "Should approve comments of known commenters",
+			principal:  bobPrincipal,
+			post:       alicePost,
+			verdict:    clean,
+			approved:   1,
+			wantStatus: models.CommentStatusApproved,
+		},
+		{
+			name:       "Should hold comments of first-time commenters",
+			principal:  charliePrincipal,
+			post:       alicePost,
+			verdict:    clean,
+			approved:   0,
+			wantStatus: models.CommentStatusPending,
+		},
+		{
+			name:       "Should hold comments looking like spam",
+			principal:  bobPrincipal,
+			post:       alicePost,
+			verdict:    spam,
+			approved:   -1,
+			wantStatus: models.CommentStatusPending,
+		},
+		{
+			name:       "Should hold every comment on moderated posts",
+			principal:  bobPrincipal,
+			post:       moderated,
+			verdict:    clean,
+			approved:   -1,
+			wantStatus: models.CommentStatusPending,
+		},
+		{
+			name:       "Should trust the author of the post",
+			principal:  alicePrincipal,
+			post:       moderated,
+			verdict:    spam,
+			approved:   -1,
+			wantStatus: models.CommentStatusApproved,
+		},
+		{
+			name:       "Should trust moderators",
+			principal:  editorPrincipal,
+			post:       moderated,
+			verdict:    clean,
+			approved:   -1,
+			wantStatus: models.CommentStatusApproved,
+		},
+		{
+			name:      "Should refuse comments on closed posts",
+			principal: bobPrincipal,
+			post:      closed,
+			wantErr:   domain.ErrConflict,
+		},
+		{
+			name:      "Should hide drafts of other authors",
+			principal: bobPrincipal,
+			post:      aliceDraft,
+			wantErr:   domain.ErrNotFound,
+		},
+		{
+			name:      "Should refuse comments on drafts",
+			principal: alicePrincipal,
+			post:      aliceDraft,
+			wantErr:   domain.ErrConflict,
+		},
+		{
+			name:      "Should forbid principals without a role",
+			principal: models.Principal{UserId: charliePrincipal.UserId},
+			post:      alicePost,
+			wantErr:   domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := models.Comment{PostId: tt.post.Id, ParentId: &bobComment.Id, Body: "Me too"}
+
+			p := NewMockPostsRepository(t)
+			p.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+
+			r := NewMockCommentsRepository(t)
+			sc := NewMockSpamScorer(t)
+			if tt.wantErr == nil {
+				r.On("FindCommentById", context.TODO(), bobComment.Id).Return(withStatus(bobComment, models.CommentStatusApproved), nil)
+
+				scored := comment
+				scored.AuthorId = tt.principal.UserId
+				sc.On("ScoreComment", context.TODO(), scored).Return(tt.verdict, nil)
+
+				if tt.approved >= 0 {
+					r.On("CountApprovedCommentsByAuthor", context.TODO(), tt.principal.UserId).Return(tt.approved, nil)
+				}
+
+				want := scored
+				want.Status = tt.wantStatus
+				want.SpamScore, want.SpamReasons = tt.verdict.Score, tt.verdict.Reasons
+				r.On("CreateComment", context.TODO(), want).Return(id, nil)
+			}
+
+			s := NewCommentsService(r, p, sc, NewPolicy(DefaultGrants))
+
+			got, status, err := s.CreateComment(context.TODO(), tt.principal, comment)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, id, got)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func Test_commentsService_CreateComment_parent(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent models.Comment
+	}{
+		{name: "Should refuse replies to pending comments", parent: withStatus(bobComment, models.CommentStatusPending)},
+		{name: "Should refuse replies across posts", parent: models.Comment{Id: bobComment.Id, PostId: aliceDraft.Id, Status: models.CommentStatusApproved}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMockPostsRepository(t)
+			p.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+
+			r := NewMockCommentsRepository(t)
+			r.On("FindCommentById", context.TODO(), bobComment.Id).Return(tt.parent, nil)
+
+			s := NewCommentsService(r, p, NewMockSpamScorer(t), NewPolicy(DefaultGrants))
+
+			_, _, err := s.CreateComment(context.TODO(), bobPrincipal, models.Comment{PostId: alicePost.Id, ParentId: &bobComment.Id, Body: "Me too"})
+			assert.ErrorIs(t, err, ErrUnknownParentComment)
+		})
+	}
+}
+
+func Test_commentsService_FindPostComments(t *testing.T) {
+	p := NewMockPostsRepository(t)
+	p.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+	p.On("FindPostById", context.TODO(), aliceDraft.Id).Return(aliceDraft, nil)
+
+	r := NewMockCommentsRepository(t)
+	r.On("FindPostComments", context.TODO(), alicePost.Id, bobPrincipal.UserId).Return([]models.Comment{bobComment, aliceReply}, nil)
+
+	s := NewCommentsService(r, p, NewMockSpamScorer(t), NewPolicy(DefaultGrants))
+
+	got, err := s.FindPostComments(context.TODO(), bobPrincipal, alicePost.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Comment{bobComment, aliceReply}, got)
+
+	_, err = s.FindPostComments(context.TODO(), bobPrincipal, aliceDraft.Id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_commentsService_UpdateCommentById(t *testing.T) {
+	clean := models.SpamVerdict{Reasons: []string{}}
+
+	open := alicePost
+	open.CommentMode = models.CommentModeOpen
+	moderated := alicePost
+	moderated.CommentMode = models.CommentModeModerated
+	closed := alicePost
+	closed.CommentMode = models.CommentModeClosed
+
+	tests := []struct {
+		name       string
+		principal  models.Principal
+		post       models.Post
+		comment    models.Comment
+		body       string
+		verdict    models.SpamVerdict
+		wantStatus models.CommentStatus
+		wantErr    error
+	}{
+		{
+			name:       "Should let authors edit their comments",
+			principal:  bobPrincipal,
+			post:       open,
+			comment:    bobComment,
+			body:       "Great post, thanks!",
+			verdict:    clean,
+			wantStatus: models.CommentStatusApproved,
+		},
+		{
+			name:       "Should hold edits looking like spam",
+			principal:  bobPrincipal,
+			post:       open,
+			comment:    bobComment,
+			body:       "Buy now at https://example.com",
+			verdict:    models.SpamVerdict{Score: 1, Reasons: []string{"link_density"}, Spam: true},
+			wantStatus: models.CommentStatusPending,
+		},
+		{
+			name:       "Should hold edits of approved comments on moderated posts",
+			principal:  bobPrincipal,
+			post:       moderated,
+			comment:    bobComment,
+			body:       "Great post, thanks!",
+			verdict:    clean,
+			wantStatus: models.CommentStatusPending,
+		},
+		{
+			name:       "Should not hold edits of the author of a moderated post",
+			principal:  alicePrincipal,
+			post:       moderated,
+			comment:    aliceReply,
+			body:       "Thanks a lot, Bob!",
+			verdict:    clean,
+			wantStatus: models.CommentStatusApproved,
+		},
+		{
+			name:       "Should keep rejected comments rejected",
+			principal:  bobPrincipal,
+			post:       moderated,
+			comment:    withStatus(bobComment, models.CommentStatusRejected),
+			body:       "Great post, thanks!",
+			verdict:    clean,
+			wantStatus: models.CommentStatusRejected,
+		},
+		{
+			name:      "Should refuse edits on posts closed to comments",
+			principal: bobPrincipal,
+			post:      closed,
+			comment:   bobComment,
+			body:      "Great post, thanks!",
+			wantErr:   domain.ErrConflict,
+		},
+		{
+			name:      "Should forbid editing comments of others",
+			principal: editorPrincipal,
+			post:      open,
+			comment:   bobComment,
+			wantErr:   domain.ErrForbidden,
+		},
+		{
+			name:      "Should not find comments under other posts",
+			principal: bobPrincipal,
+			post:      models.Post{Id: aliceDraft.Id, Status: models.PostStatusPublished},
+			comment:   bobComment,
+			wantErr:   domain.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMockPostsRepository(t)
+			p.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+
+			r := NewMockCommentsRepository(t)
+			r.On("FindCommentById", context.TODO(), tt.comment.Id).Return(tt.comment, nil)
+
+			sc := NewMockSpamScorer(t)
+			if tt.wantErr == nil {
+				edited := tt.comment
+				edited.Body = tt.body
+				sc.On("ScoreComment", context.TODO(), edited).Return(tt.verdict, nil)
+
+				want := edited
+				want.SpamScore, want.SpamReasons = tt.verdict.Score, tt.verdict.Reasons
+				want.Status = tt.wantStatus
+				r.On("UpdateCommentById", context.TODO(), tt.comment.Id, want).Return(nil)
+			}
+
+			s := NewCommentsService(r, p, sc, NewPolicy(DefaultGrants))
+
+			err := s.UpdateCommentById(context.TODO(), tt.principal, tt.post.Id, tt.comment.Id, models.Comment{Body: tt.body})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_commentsService_DeleteCommentById(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Principal
+		wantErr   error
+	}{
+		{
+			name:      "Should let authors delete their comments",
+			principal: bobPrincipal,
+		},
+		{
+			name:      "Should let editors delete any comment",
+			principal: editorPrincipal,
+		},
+		{
+			name:      "Should forbid other authors",
+			principal: alicePrincipal,
+			wantErr:   domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMockPostsRepository(t)
+			p.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+
+			r := NewMockCommentsRepository(t)
+			r.On("FindCommentById", context.TODO(), bobComment.Id).Return(bobComment, nil)
+			if tt.wantErr == nil {
+				r.On("DeleteCommentById", context.TODO(), bobComment.Id).Return(nil)
+			}
+
+			s := NewCommentsService(r, p, NewMockSpamScorer(t), NewPolicy(DefaultGrants))
+
+			err := s.DeleteCommentById(context.TODO(), tt.principal, alicePost.Id, bobComment.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_commentsService_moderation(t *testing.T) {
+	pending := withStatus(bobComment, models.CommentStatusPending)
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		comment   models.Comment
+		approve   bool
+		wantErr   error
+	}{
+		{
+			name:      "Should let moderators approve pending comments",
+			principal: editorPrincipal,
+			comment:   pending,
+			approve:   true,
+		},
+		{
+			name:      "Should let moderators reject approved comments",
+			principal: editorPrincipal,
+			comment:   bobComment,
+		},
+		{
+			name:      "Should refuse approving twice",
+			principal: editorPrincipal,
+			comment:   bobComment,
+			approve:   true,
+			wantErr:   domain.ErrConflict,
+		},
+		{
+			name:      "Should forbid authors",
+			principal: alicePrincipal,
+			comment:   pending,
+			approve:   true,
+			wantErr:   domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := models.CommentStatusRejected
+			if tt.approve {
+				to = models.CommentStatusApproved
+			}
+
+			r := NewMockCommentsRepository(t)
+			if !errors.Is(tt.wantErr, domain.ErrForbidden) {
+				r.On("FindCommentById", context.TODO(), tt.comment.Id).Return(tt.comment, nil)
+			}
+			if tt.wantErr == nil {
+				want := withStatus(tt.comment, to)
+				want.ModeratedBy = &tt.principal.UserId
+				r.On("UpdateCommentStatusById", context.TODO(), tt.comment.Id, tt.comment.Status, want).Return(nil)
+			}
+
+			s := NewCommentsService(r, NewMockPostsRepository(t), NewMockSpamScorer(t), NewPolicy(DefaultGrants))
+
+			moderate := s.RejectComment
+			if tt.approve {
+				moderate = s.ApproveComment
+			}
+
+			err := moderate(context.TODO(), tt.principal, tt.comment.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_commentsService_FindCommentsByStatus(t *testing.T) {
+	pending := withStatus(bobComment, models.CommentStatusPending)
+
+	r := NewMockCommentsRepository(t)
+	r.On("FindCommentsByStatus", context.TODO(), models.CommentStatusPending, 10, 0).Return([]models.Comment{pending}, nil)
+
+	s := NewCommentsService(r, NewMockPostsRepository(t), NewMockSpamScorer(t), NewPolicy(DefaultGrants))
+
+	got, err := s.FindCommentsByStatus(context.TODO(), editorPrincipal, models.CommentStatusPending, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Comment{pending}, got)
+
+	_, err = s.FindCommentsByStatus(context.TODO(), bobPrincipal, models.CommentStatusPending, 10, 0)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func withStatus(comment models.Comment, status models.CommentStatus) models.Comment {
+	comment.Status = status
+	return comment
+}
@@ -6,19 +6,3165 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/query"
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
+// NewMockCredentialsVerifier creates a new instance of MockCredentialsVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCredentialsVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCredentialsVerifier {
+	mock := &MockCredentialsVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCredentialsVerifier is an autogenerated mock type for the CredentialsVerifier type
+type MockCredentialsVerifier struct {
+	mock.Mock
+}
+
+type MockCredentialsVerifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCredentialsVerifier) EXPECT() *MockCredentialsVerifier_Expecter {
+	return &MockCredentialsVerifier_Expecter{mock: &_m.Mock}
+}
+
+// VerifyCredentials provides a mock function for the type MockCredentialsVerifier
+func (_mock *MockCredentialsVerifier) VerifyCredentials(ctx context.Context, usernameOrEmail string, password string) (models.User, error) {
+	ret := _mock.Called(ctx, usernameOrEmail, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCredentials")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.User, error)); ok {
+		return returnFunc(ctx, usernameOrEmail, password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.User); ok {
+		r0 = returnFunc(ctx, usernameOrEmail, password)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, usernameOrEmail, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCredentialsVerifier_VerifyCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyCredentials'
+type MockCredentialsVerifier_VerifyCredentials_Call struct {
+	*mock.Call
+}
+
+// VerifyCredentials is a helper method to define mock.On call
+//   - ctx context.Context
+//   - usernameOrEmail string
+//   - password string
+func (_e *MockCredentialsVerifier_Expecter) VerifyCredentials(ctx interface{}, usernameOrEmail interface{}, password interface{}) *MockCredentialsVerifier_VerifyCredentials_Call {
+	return &MockCredentialsVerifier_VerifyCredentials_Call{Call: _e.mock.On("VerifyCredentials", ctx, usernameOrEmail, password)}
+}
+
+func (_c *MockCredentialsVerifier_VerifyCredentials_Call) Run(run func(ctx context.Context, usernameOrEmail string, password string)) *MockCredentialsVerifier_VerifyCredentials_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCredentialsVerifier_VerifyCredentials_Call) Return(user models.User, err error) *MockCredentialsVerifier_VerifyCredentials_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockCredentialsVerifier_VerifyCredentials_Call) RunAndReturn(run func(ctx context.Context, usernameOrEmail string, password string) (models.User, error)) *MockCredentialsVerifier_VerifyCredentials_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRefreshTokensRepository creates a new instance of MockRefreshTokensRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRefreshTokensRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRefreshTokensRepository {
+	mock := &MockRefreshTokensRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRefreshTokensRepository is an autogenerated mock type for the RefreshTokensRepository type
+type MockRefreshTokensRepository struct {
+	mock.Mock
+}
+
+type MockRefreshTokensRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRefreshTokensRepository) EXPECT() *MockRefreshTokensRepository_Expecter {
+	return &MockRefreshTokensRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateRefreshToken provides a mock function for the type MockRefreshTokensRepository
+func (_mock *MockRefreshTokensRepository) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RefreshToken) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RefreshToken) uuid.UUID); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RefreshToken) error); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRefreshTokensRepository_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type MockRefreshTokensRepository_CreateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// CreateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token models.RefreshToken
+func (_e *MockRefreshTokensRepository_Expecter) CreateRefreshToken(ctx interface{}, token interface{}) *MockRefreshTokensRepository_CreateRefreshToken_Call {
+	return &MockRefreshTokensRepository_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", ctx, token)}
+}
+
+func (_c *MockRefreshTokensRepository_CreateRefreshToken_Call) Run(run func(ctx context.Context, token models.RefreshToken)) *MockRefreshTokensRepository_CreateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.RefreshToken
+		if args[1] != nil {
+			arg1 = args[1].(models.RefreshToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_CreateRefreshToken_Call) Return(uUID uuid.UUID, err error) *MockRefreshTokensRepository_CreateRefreshToken_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_CreateRefreshToken_Call) RunAndReturn(run func(ctx context.Context, token models.RefreshToken) (uuid.UUID, error)) *MockRefreshTokensRepository_CreateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindRefreshTokenByHash provides a mock function for the type MockRefreshTokensRepository
+func (_mock *MockRefreshTokensRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	ret := _mock.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRefreshTokenByHash")
+	}
+
+	var r0 models.RefreshToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.RefreshToken, error)); ok {
+		return returnFunc(ctx, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.RefreshToken); ok {
+		r0 = returnFunc(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(models.RefreshToken)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRefreshTokensRepository_FindRefreshTokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRefreshTokenByHash'
+type MockRefreshTokensRepository_FindRefreshTokenByHash_Call struct {
+	*mock.Call
+}
+
+// FindRefreshTokenByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockRefreshTokensRepository_Expecter) FindRefreshTokenByHash(ctx interface{}, tokenHash interface{}) *MockRefreshTokensRepository_FindRefreshTokenByHash_Call {
+	return &MockRefreshTokensRepository_FindRefreshTokenByHash_Call{Call: _e.mock.On("FindRefreshTokenByHash", ctx, tokenHash)}
+}
+
+func (_c *MockRefreshTokensRepository_FindRefreshTokenByHash_Call) Run(run func(ctx context.Context, tokenHash string)) *MockRefreshTokensRepository_FindRefreshTokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_FindRefreshTokenByHash_Call) Return(refreshToken models.RefreshToken, err error) *MockRefreshTokensRepository_FindRefreshTokenByHash_Call {
+	_c.Call.Return(refreshToken, err)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_FindRefreshTokenByHash_Call) RunAndReturn(run func(ctx context.Context, tokenHash string) (models.RefreshToken, error)) *MockRefreshTokensRepository_FindRefreshTokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshTokenFamily provides a mock function for the type MockRefreshTokensRepository
+func (_mock *MockRefreshTokensRepository) RevokeRefreshTokenFamily(ctx context.Context, familyId uuid.UUID) error {
+	ret := _mock.Called(ctx, familyId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, familyId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshTokenFamily'
+type MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshTokenFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyId uuid.UUID
+func (_e *MockRefreshTokensRepository_Expecter) RevokeRefreshTokenFamily(ctx interface{}, familyId interface{}) *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call {
+	return &MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call{Call: _e.mock.On("RevokeRefreshTokenFamily", ctx, familyId)}
+}
+
+func (_c *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call) Run(run func(ctx context.Context, familyId uuid.UUID)) *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call) Return(err error) *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call) RunAndReturn(run func(ctx context.Context, familyId uuid.UUID) error) *MockRefreshTokensRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function for the type MockRefreshTokensRepository
+func (_mock *MockRefreshTokensRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID, newToken models.RefreshToken) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, id, newToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.RefreshToken) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, id, newToken)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.RefreshToken) uuid.UUID); ok {
+		r0 = returnFunc(ctx, id, newToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.RefreshToken) error); ok {
+		r1 = returnFunc(ctx, id, newToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRefreshTokensRepository_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type MockRefreshTokensRepository_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - newToken models.RefreshToken
+func (_e *MockRefreshTokensRepository_Expecter) RotateRefreshToken(ctx interface{}, id interface{}, newToken interface{}) *MockRefreshTokensRepository_RotateRefreshToken_Call {
+	return &MockRefreshTokensRepository_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", ctx, id, newToken)}
+}
+
+func (_c *MockRefreshTokensRepository_RotateRefreshToken_Call) Run(run func(ctx context.Context, id uuid.UUID, newToken models.RefreshToken)) *MockRefreshTokensRepository_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.RefreshToken
+		if args[2] != nil {
+			arg2 = args[2].(models.RefreshToken)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RotateRefreshToken_Call) Return(uUID uuid.UUID, err error) *MockRefreshTokensRepository_RotateRefreshToken_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RotateRefreshToken_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, newToken models.RefreshToken) (uuid.UUID, error)) *MockRefreshTokensRepository_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCategoriesRepository creates a new instance of MockCategoriesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCategoriesRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCategoriesRepository {
+	mock := &MockCategoriesRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCategoriesRepository is an autogenerated mock type for the CategoriesRepository type
+type MockCategoriesRepository struct {
+	mock.Mock
+}
+
+type MockCategoriesRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCategoriesRepository) EXPECT() *MockCategoriesRepository_Expecter {
+	return &MockCategoriesRepository_Expecter{mock: &_m.Mock}
+}
+
+// CountCategoryChildren provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) CountCategoryChildren(ctx context.Context, id uuid.UUID) (int, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountCategoryChildren")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCategoriesRepository_CountCategoryChildren_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountCategoryChildren'
+type MockCategoriesRepository_CountCategoryChildren_Call struct {
+	*mock.Call
+}
+
+// CountCategoryChildren is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCategoriesRepository_Expecter) CountCategoryChildren(ctx interface{}, id interface{}) *MockCategoriesRepository_CountCategoryChildren_Call {
+	return &MockCategoriesRepository_CountCategoryChildren_Call{Call: _e.mock.On("CountCategoryChildren", ctx, id)}
+}
+
+func (_c *MockCategoriesRepository_CountCategoryChildren_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCategoriesRepository_CountCategoryChildren_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_CountCategoryChildren_Call) Return(n int, err error) *MockCategoriesRepository_CountCategoryChildren_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_CountCategoryChildren_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (int, error)) *MockCategoriesRepository_CountCategoryChildren_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCategory provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) CreateCategory(ctx context.Context, category models.Category) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCategory")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Category) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, category)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Category) uuid.UUID); ok {
+		r0 = returnFunc(ctx, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Category) error); ok {
+		r1 = returnFunc(ctx, category)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCategoriesRepository_CreateCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCategory'
+type MockCategoriesRepository_CreateCategory_Call struct {
+	*mock.Call
+}
+
+// CreateCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - category models.Category
+func (_e *MockCategoriesRepository_Expecter) CreateCategory(ctx interface{}, category interface{}) *MockCategoriesRepository_CreateCategory_Call {
+	return &MockCategoriesRepository_CreateCategory_Call{Call: _e.mock.On("CreateCategory", ctx, category)}
+}
+
+func (_c *MockCategoriesRepository_CreateCategory_Call) Run(run func(ctx context.Context, category models.Category)) *MockCategoriesRepository_CreateCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Category
+		if args[1] != nil {
+			arg1 = args[1].(models.Category)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_CreateCategory_Call) Return(uUID uuid.UUID, err error) *MockCategoriesRepository_CreateCategory_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_CreateCategory_Call) RunAndReturn(run func(ctx context.Context, category models.Category) (uuid.UUID, error)) *MockCategoriesRepository_CreateCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCategoryById provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) DeleteCategoryById(ctx context.Context, id uuid.UUID) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategoryById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCategoriesRepository_DeleteCategoryById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCategoryById'
+type MockCategoriesRepository_DeleteCategoryById_Call struct {
+	*mock.Call
+}
+
+// DeleteCategoryById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCategoriesRepository_Expecter) DeleteCategoryById(ctx interface{}, id interface{}) *MockCategoriesRepository_DeleteCategoryById_Call {
+	return &MockCategoriesRepository_DeleteCategoryById_Call{Call: _e.mock.On("DeleteCategoryById", ctx, id)}
+}
+
+func (_c *MockCategoriesRepository_DeleteCategoryById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCategoriesRepository_DeleteCategoryById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_DeleteCategoryById_Call) Return(err error) *MockCategoriesRepository_DeleteCategoryById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_DeleteCategoryById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockCategoriesRepository_DeleteCategoryById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAllCategories provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) FindAllCategories(ctx context.Context) ([]models.Category, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllCategories")
+	}
+
+	var r0 []models.Category
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Category, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Category); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Category)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCategoriesRepository_FindAllCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAllCategories'
+type MockCategoriesRepository_FindAllCategories_Call struct {
+	*mock.Call
+}
+
+// FindAllCategories is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockCategoriesRepository_Expecter) FindAllCategories(ctx interface{}) *MockCategoriesRepository_FindAllCategories_Call {
+	return &MockCategoriesRepository_FindAllCategories_Call{Call: _e.mock.On("FindAllCategories", ctx)}
+}
+
+func (_c *MockCategoriesRepository_FindAllCategories_Call) Run(run func(ctx context.Context)) *MockCategoriesRepository_FindAllCategories_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindAllCategories_Call) Return(categorys []models.Category, err error) *MockCategoriesRepository_FindAllCategories_Call {
+	_c.Call.Return(categorys, err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindAllCategories_Call) RunAndReturn(run func(ctx context.Context) ([]models.Category, error)) *MockCategoriesRepository_FindAllCategories_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCategoryAncestorIds provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) FindCategoryAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCategoryAncestorIds")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]uuid.UUID, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []uuid.UUID); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCategoriesRepository_FindCategoryAncestorIds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCategoryAncestorIds'
+type MockCategoriesRepository_FindCategoryAncestorIds_Call struct {
+	*mock.Call
+}
+
+// FindCategoryAncestorIds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCategoriesRepository_Expecter) FindCategoryAncestorIds(ctx interface{}, id interface{}) *MockCategoriesRepository_FindCategoryAncestorIds_Call {
+	return &MockCategoriesRepository_FindCategoryAncestorIds_Call{Call: _e.mock.On("FindCategoryAncestorIds", ctx, id)}
+}
+
+func (_c *MockCategoriesRepository_FindCategoryAncestorIds_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCategoriesRepository_FindCategoryAncestorIds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindCategoryAncestorIds_Call) Return(uUIDs []uuid.UUID, err error) *MockCategoriesRepository_FindCategoryAncestorIds_Call {
+	_c.Call.Return(uUIDs, err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindCategoryAncestorIds_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)) *MockCategoriesRepository_FindCategoryAncestorIds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCategoryById provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) FindCategoryById(ctx context.Context, id uuid.UUID) (models.Category, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCategoryById")
+	}
+
+	var r0 models.Category
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (models.Category, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) models.Category); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Category)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCategoriesRepository_FindCategoryById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCategoryById'
+type MockCategoriesRepository_FindCategoryById_Call struct {
+	*mock.Call
+}
+
+// FindCategoryById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCategoriesRepository_Expecter) FindCategoryById(ctx interface{}, id interface{}) *MockCategoriesRepository_FindCategoryById_Call {
+	return &MockCategoriesRepository_FindCategoryById_Call{Call: _e.mock.On("FindCategoryById", ctx, id)}
+}
+
+func (_c *MockCategoriesRepository_FindCategoryById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCategoriesRepository_FindCategoryById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindCategoryById_Call) Return(category models.Category, err error) *MockCategoriesRepository_FindCategoryById_Call {
+	_c.Call.Return(category, err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_FindCategoryById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (models.Category, error)) *MockCategoriesRepository_FindCategoryById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCategoryById provides a mock function for the type MockCategoriesRepository
+func (_mock *MockCategoriesRepository) UpdateCategoryById(ctx context.Context, id uuid.UUID, category models.Category) error {
+	ret := _mock.Called(ctx, id, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategoryById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Category) error); ok {
+		r0 = returnFunc(ctx, id, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCategoriesRepository_UpdateCategoryById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCategoryById'
+type MockCategoriesRepository_UpdateCategoryById_Call struct {
+	*mock.Call
+}
+
+// UpdateCategoryById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - category models.Category
+func (_e *MockCategoriesRepository_Expecter) UpdateCategoryById(ctx interface{}, id interface{}, category interface{}) *MockCategoriesRepository_UpdateCategoryById_Call {
+	return &MockCategoriesRepository_UpdateCategoryById_Call{Call: _e.mock.On("UpdateCategoryById", ctx, id, category)}
+}
+
+func (_c *MockCategoriesRepository_UpdateCategoryById_Call) Run(run func(ctx context.Context, id uuid.UUID, category models.Category)) *MockCategoriesRepository_UpdateCategoryById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.Category
+		if args[2] != nil {
+			arg2 = args[2].(models.Category)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCategoriesRepository_UpdateCategoryById_Call) Return(err error) *MockCategoriesRepository_UpdateCategoryById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCategoriesRepository_UpdateCategoryById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, category models.Category) error) *MockCategoriesRepository_UpdateCategoryById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCommentsRepository creates a new instance of MockCommentsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCommentsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCommentsRepository {
+	mock := &MockCommentsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCommentsRepository is an autogenerated mock type for the CommentsRepository type
+type MockCommentsRepository struct {
+	mock.Mock
+}
+
+type MockCommentsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCommentsRepository) EXPECT() *MockCommentsRepository_Expecter {
+	return &MockCommentsRepository_Expecter{mock: &_m.Mock}
+}
+
+// CountApprovedCommentsByAuthor provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) CountApprovedCommentsByAuthor(ctx context.Context, authorId uuid.UUID) (int, error) {
+	ret := _mock.Called(ctx, authorId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountApprovedCommentsByAuthor")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+		return returnFunc(ctx, authorId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+		r0 = returnFunc(ctx, authorId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, authorId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentsRepository_CountApprovedCommentsByAuthor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountApprovedCommentsByAuthor'
+type MockCommentsRepository_CountApprovedCommentsByAuthor_Call struct {
+	*mock.Call
+}
+
+// CountApprovedCommentsByAuthor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorId uuid.UUID
+func (_e *MockCommentsRepository_Expecter) CountApprovedCommentsByAuthor(ctx interface{}, authorId interface{}) *MockCommentsRepository_CountApprovedCommentsByAuthor_Call {
+	return &MockCommentsRepository_CountApprovedCommentsByAuthor_Call{Call: _e.mock.On("CountApprovedCommentsByAuthor", ctx, authorId)}
+}
+
+func (_c *MockCommentsRepository_CountApprovedCommentsByAuthor_Call) Run(run func(ctx context.Context, authorId uuid.UUID)) *MockCommentsRepository_CountApprovedCommentsByAuthor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_CountApprovedCommentsByAuthor_Call) Return(n int, err error) *MockCommentsRepository_CountApprovedCommentsByAuthor_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_CountApprovedCommentsByAuthor_Call) RunAndReturn(run func(ctx context.Context, authorId uuid.UUID) (int, error)) *MockCommentsRepository_CountApprovedCommentsByAuthor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateComment provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) CreateComment(ctx context.Context, comment models.Comment) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, comment)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) uuid.UUID); ok {
+		r0 = returnFunc(ctx, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Comment) error); ok {
+		r1 = returnFunc(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentsRepository_CreateComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateComment'
+type MockCommentsRepository_CreateComment_Call struct {
+	*mock.Call
+}
+
+// CreateComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - comment models.Comment
+func (_e *MockCommentsRepository_Expecter) CreateComment(ctx interface{}, comment interface{}) *MockCommentsRepository_CreateComment_Call {
+	return &MockCommentsRepository_CreateComment_Call{Call: _e.mock.On("CreateComment", ctx, comment)}
+}
+
+func (_c *MockCommentsRepository_CreateComment_Call) Run(run func(ctx context.Context, comment models.Comment)) *MockCommentsRepository_CreateComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Comment
+		if args[1] != nil {
+			arg1 = args[1].(models.Comment)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_CreateComment_Call) Return(uUID uuid.UUID, err error) *MockCommentsRepository_CreateComment_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_CreateComment_Call) RunAndReturn(run func(ctx context.Context, comment models.Comment) (uuid.UUID, error)) *MockCommentsRepository_CreateComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCommentById provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) DeleteCommentById(ctx context.Context, id uuid.UUID) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCommentById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCommentsRepository_DeleteCommentById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCommentById'
+type MockCommentsRepository_DeleteCommentById_Call struct {
+	*mock.Call
+}
+
+// DeleteCommentById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCommentsRepository_Expecter) DeleteCommentById(ctx interface{}, id interface{}) *MockCommentsRepository_DeleteCommentById_Call {
+	return &MockCommentsRepository_DeleteCommentById_Call{Call: _e.mock.On("DeleteCommentById", ctx, id)}
+}
+
+func (_c *MockCommentsRepository_DeleteCommentById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCommentsRepository_DeleteCommentById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_DeleteCommentById_Call) Return(err error) *MockCommentsRepository_DeleteCommentById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_DeleteCommentById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockCommentsRepository_DeleteCommentById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCommentById provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) FindCommentById(ctx context.Context, id uuid.UUID) (models.Comment, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCommentById")
+	}
+
+	var r0 models.Comment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (models.Comment, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) models.Comment); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Comment)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentsRepository_FindCommentById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCommentById'
+type MockCommentsRepository_FindCommentById_Call struct {
+	*mock.Call
+}
+
+// FindCommentById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockCommentsRepository_Expecter) FindCommentById(ctx interface{}, id interface{}) *MockCommentsRepository_FindCommentById_Call {
+	return &MockCommentsRepository_FindCommentById_Call{Call: _e.mock.On("FindCommentById", ctx, id)}
+}
+
+func (_c *MockCommentsRepository_FindCommentById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockCommentsRepository_FindCommentById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindCommentById_Call) Return(comment models.Comment, err error) *MockCommentsRepository_FindCommentById_Call {
+	_c.Call.Return(comment, err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindCommentById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (models.Comment, error)) *MockCommentsRepository_FindCommentById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCommentsByStatus provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) FindCommentsByStatus(ctx context.Context, status models.CommentStatus, limit int, offset int) ([]models.Comment, error) {
+	ret := _mock.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCommentsByStatus")
+	}
+
+	var r0 []models.Comment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CommentStatus, int, int) ([]models.Comment, error)); ok {
+		return returnFunc(ctx, status, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CommentStatus, int, int) []models.Comment); ok {
+		r0 = returnFunc(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Comment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.CommentStatus, int, int) error); ok {
+		r1 = returnFunc(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentsRepository_FindCommentsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCommentsByStatus'
+type MockCommentsRepository_FindCommentsByStatus_Call struct {
+	*mock.Call
+}
+
+// FindCommentsByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status models.CommentStatus
+//   - limit int
+//   - offset int
+func (_e *MockCommentsRepository_Expecter) FindCommentsByStatus(ctx interface{}, status interface{}, limit interface{}, offset interface{}) *MockCommentsRepository_FindCommentsByStatus_Call {
+	return &MockCommentsRepository_FindCommentsByStatus_Call{Call: _e.mock.On("FindCommentsByStatus", ctx, status, limit, offset)}
+}
+
+func (_c *MockCommentsRepository_FindCommentsByStatus_Call) Run(run func(ctx context.Context, status models.CommentStatus, limit int, offset int)) *MockCommentsRepository_FindCommentsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.CommentStatus
+		if args[1] != nil {
+			arg1 = args[1].(models.CommentStatus)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindCommentsByStatus_Call) Return(comments []models.Comment, err error) *MockCommentsRepository_FindCommentsByStatus_Call {
+	_c.Call.Return(comments, err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindCommentsByStatus_Call) RunAndReturn(run func(ctx context.Context, status models.CommentStatus, limit int, offset int) ([]models.Comment, error)) *MockCommentsRepository_FindCommentsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostComments provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) FindPostComments(ctx context.Context, postId uuid.UUID, viewerId uuid.UUID) ([]models.Comment, error) {
+	ret := _mock.Called(ctx, postId, viewerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostComments")
+	}
+
+	var r0 []models.Comment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]models.Comment, error)); ok {
+		return returnFunc(ctx, postId, viewerId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []models.Comment); ok {
+		r0 = returnFunc(ctx, postId, viewerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Comment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, postId, viewerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentsRepository_FindPostComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostComments'
+type MockCommentsRepository_FindPostComments_Call struct {
+	*mock.Call
+}
+
+// FindPostComments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postId uuid.UUID
+//   - viewerId uuid.UUID
+func (_e *MockCommentsRepository_Expecter) FindPostComments(ctx interface{}, postId interface{}, viewerId interface{}) *MockCommentsRepository_FindPostComments_Call {
+	return &MockCommentsRepository_FindPostComments_Call{Call: _e.mock.On("FindPostComments", ctx, postId, viewerId)}
+}
+
+func (_c *MockCommentsRepository_FindPostComments_Call) Run(run func(ctx context.Context, postId uuid.UUID, viewerId uuid.UUID)) *MockCommentsRepository_FindPostComments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindPostComments_Call) Return(comments []models.Comment, err error) *MockCommentsRepository_FindPostComments_Call {
+	_c.Call.Return(comments, err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_FindPostComments_Call) RunAndReturn(run func(ctx context.Context, postId uuid.UUID, viewerId uuid.UUID) ([]models.Comment, error)) *MockCommentsRepository_FindPostComments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCommentById provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) UpdateCommentById(ctx context.Context, id uuid.UUID, comment models.Comment) error {
+	ret := _mock.Called(ctx, id, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCommentById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Comment) error); ok {
+		r0 = returnFunc(ctx, id, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCommentsRepository_UpdateCommentById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCommentById'
+type MockCommentsRepository_UpdateCommentById_Call struct {
+	*mock.Call
+}
+
+// UpdateCommentById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - comment models.Comment
+func (_e *MockCommentsRepository_Expecter) UpdateCommentById(ctx interface{}, id interface{}, comment interface{}) *MockCommentsRepository_UpdateCommentById_Call {
+	return &MockCommentsRepository_UpdateCommentById_Call{Call: _e.mock.On("UpdateCommentById", ctx, id, comment)}
+}
+
+func (_c *MockCommentsRepository_UpdateCommentById_Call) Run(run func(ctx context.Context, id uuid.UUID, comment models.Comment)) *MockCommentsRepository_UpdateCommentById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.Comment
+		if args[2] != nil {
+			arg2 = args[2].(models.Comment)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_UpdateCommentById_Call) Return(err error) *MockCommentsRepository_UpdateCommentById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_UpdateCommentById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, comment models.Comment) error) *MockCommentsRepository_UpdateCommentById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCommentStatusById provides a mock function for the type MockCommentsRepository
+func (_mock *MockCommentsRepository) UpdateCommentStatusById(ctx context.Context, id uuid.UUID, from models.CommentStatus, comment models.Comment) error {
+	ret := _mock.Called(ctx, id, from, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCommentStatusById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.CommentStatus, models.Comment) error); ok {
+		r0 = returnFunc(ctx, id, from, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCommentsRepository_UpdateCommentStatusById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCommentStatusById'
+type MockCommentsRepository_UpdateCommentStatusById_Call struct {
+	*mock.Call
+}
+
+// UpdateCommentStatusById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - from models.CommentStatus
+//   - comment models.Comment
+func (_e *MockCommentsRepository_Expecter) UpdateCommentStatusById(ctx interface{}, id interface{}, from interface{}, comment interface{}) *MockCommentsRepository_UpdateCommentStatusById_Call {
+	return &MockCommentsRepository_UpdateCommentStatusById_Call{Call: _e.mock.On("UpdateCommentStatusById", ctx, id, from, comment)}
+}
+
+func (_c *MockCommentsRepository_UpdateCommentStatusById_Call) Run(run func(ctx context.Context, id uuid.UUID, from models.CommentStatus, comment models.Comment)) *MockCommentsRepository_UpdateCommentStatusById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.CommentStatus
+		if args[2] != nil {
+			arg2 = args[2].(models.CommentStatus)
+		}
+		var arg3 models.Comment
+		if args[3] != nil {
+			arg3 = args[3].(models.Comment)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_UpdateCommentStatusById_Call) Return(err error) *MockCommentsRepository_UpdateCommentStatusById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCommentsRepository_UpdateCommentStatusById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, from models.CommentStatus, comment models.Comment) error) *MockCommentsRepository_UpdateCommentStatusById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPasswordHasher creates a new instance of MockPasswordHasher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordHasher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordHasher {
+	mock := &MockPasswordHasher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPasswordHasher is an autogenerated mock type for the PasswordHasher type
+type MockPasswordHasher struct {
+	mock.Mock
+}
+
+type MockPasswordHasher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPasswordHasher) EXPECT() *MockPasswordHasher_Expecter {
+	return &MockPasswordHasher_Expecter{mock: &_m.Mock}
+}
+
+// Compare provides a mock function for the type MockPasswordHasher
+func (_mock *MockPasswordHasher) Compare(hashedPassword string, password string) error {
+	ret := _mock.Called(hashedPassword, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Compare")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(hashedPassword, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordHasher_Compare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Compare'
+type MockPasswordHasher_Compare_Call struct {
+	*mock.Call
+}
+
+// Compare is a helper method to define mock.On call
+//   - hashedPassword string
+//   - password string
+func (_e *MockPasswordHasher_Expecter) Compare(hashedPassword interface{}, password interface{}) *MockPasswordHasher_Compare_Call {
+	return &MockPasswordHasher_Compare_Call{Call: _e.mock.On("Compare", hashedPassword, password)}
+}
+
+func (_c *MockPasswordHasher_Compare_Call) Run(run func(hashedPassword string, password string)) *MockPasswordHasher_Compare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPasswordHasher_Compare_Call) Return(err error) *MockPasswordHasher_Compare_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordHasher_Compare_Call) RunAndReturn(run func(hashedPassword string, password string) error) *MockPasswordHasher_Compare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Hash provides a mock function for the type MockPasswordHasher
+func (_mock *MockPasswordHasher) Hash(password string) (string, error) {
+	ret := _mock.Called(password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Hash")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return returnFunc(password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPasswordHasher_Hash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Hash'
+type MockPasswordHasher_Hash_Call struct {
+	*mock.Call
+}
+
+// Hash is a helper method to define mock.On call
+//   - password string
+func (_e *MockPasswordHasher_Expecter) Hash(password interface{}) *MockPasswordHasher_Hash_Call {
+	return &MockPasswordHasher_Hash_Call{Call: _e.mock.On("Hash", password)}
+}
+
+func (_c *MockPasswordHasher_Hash_Call) Run(run func(password string)) *MockPasswordHasher_Hash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPasswordHasher_Hash_Call) Return(s string, err error) *MockPasswordHasher_Hash_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockPasswordHasher_Hash_Call) RunAndReturn(run func(password string) (string, error)) *MockPasswordHasher_Hash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NeedsRehash provides a mock function for the type MockPasswordHasher
+func (_mock *MockPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	ret := _mock.Called(hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NeedsRehash")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = returnFunc(hashedPassword)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockPasswordHasher_NeedsRehash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NeedsRehash'
+type MockPasswordHasher_NeedsRehash_Call struct {
+	*mock.Call
+}
+
+// NeedsRehash is a helper method to define mock.On call
+//   - hashedPassword string
+func (_e *MockPasswordHasher_Expecter) NeedsRehash(hashedPassword interface{}) *MockPasswordHasher_NeedsRehash_Call {
+	return &MockPasswordHasher_NeedsRehash_Call{Call: _e.mock.On("NeedsRehash", hashedPassword)}
+}
+
+func (_c *MockPasswordHasher_NeedsRehash_Call) Run(run func(hashedPassword string)) *MockPasswordHasher_NeedsRehash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPasswordHasher_NeedsRehash_Call) Return(b bool) *MockPasswordHasher_NeedsRehash_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockPasswordHasher_NeedsRehash_Call) RunAndReturn(run func(hashedPassword string) bool) *MockPasswordHasher_NeedsRehash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPostsRepository creates a new instance of MockPostsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockPostsRepository(t interface {
+func NewMockPostsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPostsRepository {
+	mock := &MockPostsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPostsRepository is an autogenerated mock type for the PostsRepository type
+type MockPostsRepository struct {
+	mock.Mock
+}
+
+type MockPostsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPostsRepository) EXPECT() *MockPostsRepository_Expecter {
+	return &MockPostsRepository_Expecter{mock: &_m.Mock}
+}
+
+// CountPosts provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) CountPosts(ctx context.Context, filter models.PostFilter) (int, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPosts")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostFilter) (int, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostFilter) int); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PostFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_CountPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPosts'
+type MockPostsRepository_CountPosts_Call struct {
+	*mock.Call
+}
+
+// CountPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.PostFilter
+func (_e *MockPostsRepository_Expecter) CountPosts(ctx interface{}, filter interface{}) *MockPostsRepository_CountPosts_Call {
+	return &MockPostsRepository_CountPosts_Call{Call: _e.mock.On("CountPosts", ctx, filter)}
+}
+
+func (_c *MockPostsRepository_CountPosts_Call) Run(run func(ctx context.Context, filter models.PostFilter)) *MockPostsRepository_CountPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PostFilter
+		if args[1] != nil {
+			arg1 = args[1].(models.PostFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_CountPosts_Call) Return(n int, err error) *MockPostsRepository_CountPosts_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_CountPosts_Call) RunAndReturn(run func(ctx context.Context, filter models.PostFilter) (int, error)) *MockPostsRepository_CountPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePost provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) CreatePost(ctx context.Context, post models.Post) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, post)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePost")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Post) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, post)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Post) uuid.UUID); ok {
+		r0 = returnFunc(ctx, post)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Post) error); ok {
+		r1 = returnFunc(ctx, post)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_CreatePost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePost'
+type MockPostsRepository_CreatePost_Call struct {
+	*mock.Call
+}
+
+// CreatePost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - post models.Post
+func (_e *MockPostsRepository_Expecter) CreatePost(ctx interface{}, post interface{}) *MockPostsRepository_CreatePost_Call {
+	return &MockPostsRepository_CreatePost_Call{Call: _e.mock.On("CreatePost", ctx, post)}
+}
+
+func (_c *MockPostsRepository_CreatePost_Call) Run(run func(ctx context.Context, post models.Post)) *MockPostsRepository_CreatePost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Post
+		if args[1] != nil {
+			arg1 = args[1].(models.Post)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_CreatePost_Call) Return(uUID uuid.UUID, err error) *MockPostsRepository_CreatePost_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_CreatePost_Call) RunAndReturn(run func(ctx context.Context, post models.Post) (uuid.UUID, error)) *MockPostsRepository_CreatePost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePostByIdAndAuthorId provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) DeletePostByIdAndAuthorId(ctx context.Context, id uuid.UUID, authorId uuid.UUID, version *time.Time) error {
+	ret := _mock.Called(ctx, id, authorId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePostByIdAndAuthorId")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *time.Time) error); ok {
+		r0 = returnFunc(ctx, id, authorId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_DeletePostByIdAndAuthorId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePostByIdAndAuthorId'
+type MockPostsRepository_DeletePostByIdAndAuthorId_Call struct {
+	*mock.Call
+}
+
+// DeletePostByIdAndAuthorId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - authorId uuid.UUID
+//   - version *time.Time
+func (_e *MockPostsRepository_Expecter) DeletePostByIdAndAuthorId(ctx interface{}, id interface{}, authorId interface{}, version interface{}) *MockPostsRepository_DeletePostByIdAndAuthorId_Call {
+	return &MockPostsRepository_DeletePostByIdAndAuthorId_Call{Call: _e.mock.On("DeletePostByIdAndAuthorId", ctx, id, authorId, version)}
+}
+
+func (_c *MockPostsRepository_DeletePostByIdAndAuthorId_Call) Run(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, version *time.Time)) *MockPostsRepository_DeletePostByIdAndAuthorId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 *time.Time
+		if args[3] != nil {
+			arg3 = args[3].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_DeletePostByIdAndAuthorId_Call) Return(err error) *MockPostsRepository_DeletePostByIdAndAuthorId_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_DeletePostByIdAndAuthorId_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, version *time.Time) error) *MockPostsRepository_DeletePostByIdAndAuthorId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePostReaction provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) DeletePostReaction(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind) error {
+	ret := _mock.Called(ctx, postId, userId, kind)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePostReaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, models.ReactionKind) error); ok {
+		r0 = returnFunc(ctx, postId, userId, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_DeletePostReaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePostReaction'
+type MockPostsRepository_DeletePostReaction_Call struct {
+	*mock.Call
+}
+
+// DeletePostReaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postId uuid.UUID
+//   - userId uuid.UUID
+//   - kind models.ReactionKind
+func (_e *MockPostsRepository_Expecter) DeletePostReaction(ctx interface{}, postId interface{}, userId interface{}, kind interface{}) *MockPostsRepository_DeletePostReaction_Call {
+	return &MockPostsRepository_DeletePostReaction_Call{Call: _e.mock.On("DeletePostReaction", ctx, postId, userId, kind)}
+}
+
+func (_c *MockPostsRepository_DeletePostReaction_Call) Run(run func(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind)) *MockPostsRepository_DeletePostReaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 models.ReactionKind
+		if args[3] != nil {
+			arg3 = args[3].(models.ReactionKind)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_DeletePostReaction_Call) Return(err error) *MockPostsRepository_DeletePostReaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_DeletePostReaction_Call) RunAndReturn(run func(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind) error) *MockPostsRepository_DeletePostReaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAllPosts provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindAllPosts(ctx context.Context, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, error) {
+	ret := _mock.Called(ctx, page, filter, projection)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllPosts")
+	}
+
+	var r0 []models.Post
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Page, models.PostFilter, models.PostProjection) ([]models.Post, error)); ok {
+		return returnFunc(ctx, page, filter, projection)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Page, models.PostFilter, models.PostProjection) []models.Post); ok {
+		r0 = returnFunc(ctx, page, filter, projection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Post)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Page, models.PostFilter, models.PostProjection) error); ok {
+		r1 = returnFunc(ctx, page, filter, projection)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindAllPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAllPosts'
+type MockPostsRepository_FindAllPosts_Call struct {
+	*mock.Call
+}
+
+// FindAllPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page models.Page
+//   - filter models.PostFilter
+//   - projection models.PostProjection
+func (_e *MockPostsRepository_Expecter) FindAllPosts(ctx interface{}, page interface{}, filter interface{}, projection interface{}) *MockPostsRepository_FindAllPosts_Call {
+	return &MockPostsRepository_FindAllPosts_Call{Call: _e.mock.On("FindAllPosts", ctx, page, filter, projection)}
+}
+
+func (_c *MockPostsRepository_FindAllPosts_Call) Run(run func(ctx context.Context, page models.Page, filter models.PostFilter, projection models.PostProjection)) *MockPostsRepository_FindAllPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Page
+		if args[1] != nil {
+			arg1 = args[1].(models.Page)
+		}
+		var arg2 models.PostFilter
+		if args[2] != nil {
+			arg2 = args[2].(models.PostFilter)
+		}
+		var arg3 models.PostProjection
+		if args[3] != nil {
+			arg3 = args[3].(models.PostProjection)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindAllPosts_Call) Return(posts []models.Post, err error) *MockPostsRepository_FindAllPosts_Call {
+	_c.Call.Return(posts, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindAllPosts_Call) RunAndReturn(run func(ctx context.Context, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, error)) *MockPostsRepository_FindAllPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostById provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostById(ctx context.Context, id uuid.UUID) (models.Post, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostById")
+	}
+
+	var r0 models.Post
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (models.Post, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) models.Post); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Post)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostById'
+type MockPostsRepository_FindPostById_Call struct {
+	*mock.Call
+}
+
+// FindPostById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockPostsRepository_Expecter) FindPostById(ctx interface{}, id interface{}) *MockPostsRepository_FindPostById_Call {
+	return &MockPostsRepository_FindPostById_Call{Call: _e.mock.On("FindPostById", ctx, id)}
+}
+
+func (_c *MockPostsRepository_FindPostById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockPostsRepository_FindPostById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostById_Call) Return(post models.Post, err error) *MockPostsRepository_FindPostById_Call {
+	_c.Call.Return(post, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (models.Post, error)) *MockPostsRepository_FindPostById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostByOldSlug provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostByOldSlug(ctx context.Context, username string, slug string) (models.Post, error) {
+	ret := _mock.Called(ctx, username, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostByOldSlug")
+	}
+
+	var r0 models.Post
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.Post, error)); ok {
+		return returnFunc(ctx, username, slug)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.Post); ok {
+		r0 = returnFunc(ctx, username, slug)
+	} else {
+		r0 = ret.Get(0).(models.Post)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, username, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostByOldSlug_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostByOldSlug'
+type MockPostsRepository_FindPostByOldSlug_Call struct {
+	*mock.Call
+}
+
+// FindPostByOldSlug is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - slug string
+func (_e *MockPostsRepository_Expecter) FindPostByOldSlug(ctx interface{}, username interface{}, slug interface{}) *MockPostsRepository_FindPostByOldSlug_Call {
+	return &MockPostsRepository_FindPostByOldSlug_Call{Call: _e.mock.On("FindPostByOldSlug", ctx, username, slug)}
+}
+
+func (_c *MockPostsRepository_FindPostByOldSlug_Call) Run(run func(ctx context.Context, username string, slug string)) *MockPostsRepository_FindPostByOldSlug_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostByOldSlug_Call) Return(post models.Post, err error) *MockPostsRepository_FindPostByOldSlug_Call {
+	_c.Call.Return(post, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostByOldSlug_Call) RunAndReturn(run func(ctx context.Context, username string, slug string) (models.Post, error)) *MockPostsRepository_FindPostByOldSlug_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostBySlug provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostBySlug(ctx context.Context, username string, slug string) (models.Post, error) {
+	ret := _mock.Called(ctx, username, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostBySlug")
+	}
+
+	var r0 models.Post
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.Post, error)); ok {
+		return returnFunc(ctx, username, slug)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.Post); ok {
+		r0 = returnFunc(ctx, username, slug)
+	} else {
+		r0 = ret.Get(0).(models.Post)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, username, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostBySlug_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostBySlug'
+type MockPostsRepository_FindPostBySlug_Call struct {
+	*mock.Call
+}
+
+// FindPostBySlug is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - slug string
+func (_e *MockPostsRepository_Expecter) FindPostBySlug(ctx interface{}, username interface{}, slug interface{}) *MockPostsRepository_FindPostBySlug_Call {
+	return &MockPostsRepository_FindPostBySlug_Call{Call: _e.mock.On("FindPostBySlug", ctx, username, slug)}
+}
+
+func (_c *MockPostsRepository_FindPostBySlug_Call) Run(run func(ctx context.Context, username string, slug string)) *MockPostsRepository_FindPostBySlug_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostBySlug_Call) Return(post models.Post, err error) *MockPostsRepository_FindPostBySlug_Call {
+	_c.Call.Return(post, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostBySlug_Call) RunAndReturn(run func(ctx context.Context, username string, slug string) (models.Post, error)) *MockPostsRepository_FindPostBySlug_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostRevision provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostRevision(ctx context.Context, postId uuid.UUID, revision int) (models.PostRevision, error) {
+	ret := _mock.Called(ctx, postId, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostRevision")
+	}
+
+	var r0 models.PostRevision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) (models.PostRevision, error)); ok {
+		return returnFunc(ctx, postId, revision)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) models.PostRevision); ok {
+		r0 = returnFunc(ctx, postId, revision)
+	} else {
+		r0 = ret.Get(0).(models.PostRevision)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = returnFunc(ctx, postId, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostRevision'
+type MockPostsRepository_FindPostRevision_Call struct {
+	*mock.Call
+}
+
+// FindPostRevision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postId uuid.UUID
+//   - revision int
+func (_e *MockPostsRepository_Expecter) FindPostRevision(ctx interface{}, postId interface{}, revision interface{}) *MockPostsRepository_FindPostRevision_Call {
+	return &MockPostsRepository_FindPostRevision_Call{Call: _e.mock.On("FindPostRevision", ctx, postId, revision)}
+}
+
+func (_c *MockPostsRepository_FindPostRevision_Call) Run(run func(ctx context.Context, postId uuid.UUID, revision int)) *MockPostsRepository_FindPostRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostRevision_Call) Return(postRevision models.PostRevision, err error) *MockPostsRepository_FindPostRevision_Call {
+	_c.Call.Return(postRevision, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostRevision_Call) RunAndReturn(run func(ctx context.Context, postId uuid.UUID, revision int) (models.PostRevision, error)) *MockPostsRepository_FindPostRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostRevisions provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostRevisions(ctx context.Context, postId uuid.UUID) ([]models.PostRevision, error) {
+	ret := _mock.Called(ctx, postId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostRevisions")
+	}
+
+	var r0 []models.PostRevision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.PostRevision, error)); ok {
+		return returnFunc(ctx, postId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.PostRevision); ok {
+		r0 = returnFunc(ctx, postId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PostRevision)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, postId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostRevisions'
+type MockPostsRepository_FindPostRevisions_Call struct {
+	*mock.Call
+}
+
+// FindPostRevisions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postId uuid.UUID
+func (_e *MockPostsRepository_Expecter) FindPostRevisions(ctx interface{}, postId interface{}) *MockPostsRepository_FindPostRevisions_Call {
+	return &MockPostsRepository_FindPostRevisions_Call{Call: _e.mock.On("FindPostRevisions", ctx, postId)}
+}
+
+func (_c *MockPostsRepository_FindPostRevisions_Call) Run(run func(ctx context.Context, postId uuid.UUID)) *MockPostsRepository_FindPostRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostRevisions_Call) Return(postRevisions []models.PostRevision, err error) *MockPostsRepository_FindPostRevisions_Call {
+	_c.Call.Return(postRevisions, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostRevisions_Call) RunAndReturn(run func(ctx context.Context, postId uuid.UUID) ([]models.PostRevision, error)) *MockPostsRepository_FindPostRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostsBySimilarTitle provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindPostsBySimilarTitle(ctx context.Context, text string, limit int, offset int, filter models.PostFilter) ([]models.PostSearchResult, error) {
+	ret := _mock.Called(ctx, text, limit, offset, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostsBySimilarTitle")
+	}
+
+	var r0 []models.PostSearchResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, models.PostFilter) ([]models.PostSearchResult, error)); ok {
+		return returnFunc(ctx, text, limit, offset, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, models.PostFilter) []models.PostSearchResult); ok {
+		r0 = returnFunc(ctx, text, limit, offset, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PostSearchResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, models.PostFilter) error); ok {
+		r1 = returnFunc(ctx, text, limit, offset, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindPostsBySimilarTitle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostsBySimilarTitle'
+type MockPostsRepository_FindPostsBySimilarTitle_Call struct {
+	*mock.Call
+}
+
+// FindPostsBySimilarTitle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - text string
+//   - limit int
+//   - offset int
+//   - filter models.PostFilter
+func (_e *MockPostsRepository_Expecter) FindPostsBySimilarTitle(ctx interface{}, text interface{}, limit interface{}, offset interface{}, filter interface{}) *MockPostsRepository_FindPostsBySimilarTitle_Call {
+	return &MockPostsRepository_FindPostsBySimilarTitle_Call{Call: _e.mock.On("FindPostsBySimilarTitle", ctx, text, limit, offset, filter)}
+}
+
+func (_c *MockPostsRepository_FindPostsBySimilarTitle_Call) Run(run func(ctx context.Context, text string, limit int, offset int, filter models.PostFilter)) *MockPostsRepository_FindPostsBySimilarTitle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 models.PostFilter
+		if args[4] != nil {
+			arg4 = args[4].(models.PostFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostsBySimilarTitle_Call) Return(postSearchResults []models.PostSearchResult, err error) *MockPostsRepository_FindPostsBySimilarTitle_Call {
+	_c.Call.Return(postSearchResults, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindPostsBySimilarTitle_Call) RunAndReturn(run func(ctx context.Context, text string, limit int, offset int, filter models.PostFilter) ([]models.PostSearchResult, error)) *MockPostsRepository_FindPostsBySimilarTitle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTakenSlugs provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindTakenSlugs(ctx context.Context, authorId uuid.UUID, base string, excludeId uuid.UUID) ([]string, error) {
+	ret := _mock.Called(ctx, authorId, base, excludeId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTakenSlugs")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, uuid.UUID) ([]string, error)); ok {
+		return returnFunc(ctx, authorId, base, excludeId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, uuid.UUID) []string); ok {
+		r0 = returnFunc(ctx, authorId, base, excludeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, authorId, base, excludeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindTakenSlugs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTakenSlugs'
+type MockPostsRepository_FindTakenSlugs_Call struct {
+	*mock.Call
+}
+
+// FindTakenSlugs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorId uuid.UUID
+//   - base string
+//   - excludeId uuid.UUID
+func (_e *MockPostsRepository_Expecter) FindTakenSlugs(ctx interface{}, authorId interface{}, base interface{}, excludeId interface{}) *MockPostsRepository_FindTakenSlugs_Call {
+	return &MockPostsRepository_FindTakenSlugs_Call{Call: _e.mock.On("FindTakenSlugs", ctx, authorId, base, excludeId)}
+}
+
+func (_c *MockPostsRepository_FindTakenSlugs_Call) Run(run func(ctx context.Context, authorId uuid.UUID, base string, excludeId uuid.UUID)) *MockPostsRepository_FindTakenSlugs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 uuid.UUID
+		if args[3] != nil {
+			arg3 = args[3].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindTakenSlugs_Call) Return(strings []string, err error) *MockPostsRepository_FindTakenSlugs_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindTakenSlugs_Call) RunAndReturn(run func(ctx context.Context, authorId uuid.UUID, base string, excludeId uuid.UUID) ([]string, error)) *MockPostsRepository_FindTakenSlugs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUnrenderedPosts provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) FindUnrenderedPosts(ctx context.Context, limit int) ([]models.Post, error) {
+	ret := _mock.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUnrenderedPosts")
+	}
+
+	var r0 []models.Post
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.Post, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.Post); ok {
+		r0 = returnFunc(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Post)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_FindUnrenderedPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUnrenderedPosts'
+type MockPostsRepository_FindUnrenderedPosts_Call struct {
+	*mock.Call
+}
+
+// FindUnrenderedPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *MockPostsRepository_Expecter) FindUnrenderedPosts(ctx interface{}, limit interface{}) *MockPostsRepository_FindUnrenderedPosts_Call {
+	return &MockPostsRepository_FindUnrenderedPosts_Call{Call: _e.mock.On("FindUnrenderedPosts", ctx, limit)}
+}
+
+func (_c *MockPostsRepository_FindUnrenderedPosts_Call) Run(run func(ctx context.Context, limit int)) *MockPostsRepository_FindUnrenderedPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_FindUnrenderedPosts_Call) Return(posts []models.Post, err error) *MockPostsRepository_FindUnrenderedPosts_Call {
+	_c.Call.Return(posts, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_FindUnrenderedPosts_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]models.Post, error)) *MockPostsRepository_FindUnrenderedPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchPosts provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) SearchPosts(ctx context.Context, tsQuery string, limit int, offset int, filter models.PostFilter) ([]models.PostSearchResult, error) {
+	ret := _mock.Called(ctx, tsQuery, limit, offset, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchPosts")
+	}
+
+	var r0 []models.PostSearchResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, models.PostFilter) ([]models.PostSearchResult, error)); ok {
+		return returnFunc(ctx, tsQuery, limit, offset, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, models.PostFilter) []models.PostSearchResult); ok {
+		r0 = returnFunc(ctx, tsQuery, limit, offset, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PostSearchResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, models.PostFilter) error); ok {
+		r1 = returnFunc(ctx, tsQuery, limit, offset, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPostsRepository_SearchPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchPosts'
+type MockPostsRepository_SearchPosts_Call struct {
+	*mock.Call
+}
+
+// SearchPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tsQuery string
+//   - limit int
+//   - offset int
+//   - filter models.PostFilter
+func (_e *MockPostsRepository_Expecter) SearchPosts(ctx interface{}, tsQuery interface{}, limit interface{}, offset interface{}, filter interface{}) *MockPostsRepository_SearchPosts_Call {
+	return &MockPostsRepository_SearchPosts_Call{Call: _e.mock.On("SearchPosts", ctx, tsQuery, limit, offset, filter)}
+}
+
+func (_c *MockPostsRepository_SearchPosts_Call) Run(run func(ctx context.Context, tsQuery string, limit int, offset int, filter models.PostFilter)) *MockPostsRepository_SearchPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 models.PostFilter
+		if args[4] != nil {
+			arg4 = args[4].(models.PostFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_SearchPosts_Call) Return(postSearchResults []models.PostSearchResult, err error) *MockPostsRepository_SearchPosts_Call {
+	_c.Call.Return(postSearchResults, err)
+	return _c
+}
+
+func (_c *MockPostsRepository_SearchPosts_Call) RunAndReturn(run func(ctx context.Context, tsQuery string, limit int, offset int, filter models.PostFilter) ([]models.PostSearchResult, error)) *MockPostsRepository_SearchPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPostReaction provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) SetPostReaction(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind) error {
+	ret := _mock.Called(ctx, postId, userId, kind)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPostReaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, models.ReactionKind) error); ok {
+		r0 = returnFunc(ctx, postId, userId, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_SetPostReaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPostReaction'
+type MockPostsRepository_SetPostReaction_Call struct {
+	*mock.Call
+}
+
+// SetPostReaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postId uuid.UUID
+//   - userId uuid.UUID
+//   - kind models.ReactionKind
+func (_e *MockPostsRepository_Expecter) SetPostReaction(ctx interface{}, postId interface{}, userId interface{}, kind interface{}) *MockPostsRepository_SetPostReaction_Call {
+	return &MockPostsRepository_SetPostReaction_Call{Call: _e.mock.On("SetPostReaction", ctx, postId, userId, kind)}
+}
+
+func (_c *MockPostsRepository_SetPostReaction_Call) Run(run func(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind)) *MockPostsRepository_SetPostReaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 models.ReactionKind
+		if args[3] != nil {
+			arg3 = args[3].(models.ReactionKind)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_SetPostReaction_Call) Return(err error) *MockPostsRepository_SetPostReaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_SetPostReaction_Call) RunAndReturn(run func(ctx context.Context, postId uuid.UUID, userId uuid.UUID, kind models.ReactionKind) error) *MockPostsRepository_SetPostReaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePostByIdAndAuthorId provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) UpdatePostByIdAndAuthorId(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post) error {
+	ret := _mock.Called(ctx, id, authorId, post)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePostByIdAndAuthorId")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, models.Post) error); ok {
+		r0 = returnFunc(ctx, id, authorId, post)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_UpdatePostByIdAndAuthorId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePostByIdAndAuthorId'
+type MockPostsRepository_UpdatePostByIdAndAuthorId_Call struct {
+	*mock.Call
+}
+
+// UpdatePostByIdAndAuthorId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - authorId uuid.UUID
+//   - post models.Post
+func (_e *MockPostsRepository_Expecter) UpdatePostByIdAndAuthorId(ctx interface{}, id interface{}, authorId interface{}, post interface{}) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+	return &MockPostsRepository_UpdatePostByIdAndAuthorId_Call{Call: _e.mock.On("UpdatePostByIdAndAuthorId", ctx, id, authorId, post)}
+}
+
+func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) Run(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post)) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 models.Post
+		if args[3] != nil {
+			arg3 = args[3].(models.Post)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) Return(err error) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post) error) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePostRenderingById provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) UpdatePostRenderingById(ctx context.Context, id uuid.UUID, post models.Post) error {
+	ret := _mock.Called(ctx, id, post)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePostRenderingById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Post) error); ok {
+		r0 = returnFunc(ctx, id, post)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_UpdatePostRenderingById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePostRenderingById'
+type MockPostsRepository_UpdatePostRenderingById_Call struct {
+	*mock.Call
+}
+
+// UpdatePostRenderingById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - post models.Post
+func (_e *MockPostsRepository_Expecter) UpdatePostRenderingById(ctx interface{}, id interface{}, post interface{}) *MockPostsRepository_UpdatePostRenderingById_Call {
+	return &MockPostsRepository_UpdatePostRenderingById_Call{Call: _e.mock.On("UpdatePostRenderingById", ctx, id, post)}
+}
+
+func (_c *MockPostsRepository_UpdatePostRenderingById_Call) Run(run func(ctx context.Context, id uuid.UUID, post models.Post)) *MockPostsRepository_UpdatePostRenderingById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.Post
+		if args[2] != nil {
+			arg2 = args[2].(models.Post)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostRenderingById_Call) Return(err error) *MockPostsRepository_UpdatePostRenderingById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostRenderingById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, post models.Post) error) *MockPostsRepository_UpdatePostRenderingById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePostStatusById provides a mock function for the type MockPostsRepository
+func (_mock *MockPostsRepository) UpdatePostStatusById(ctx context.Context, id uuid.UUID, from models.PostStatus, post models.Post) error {
+	ret := _mock.Called(ctx, id, from, post)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePostStatusById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.PostStatus, models.Post) error); ok {
+		r0 = returnFunc(ctx, id, from, post)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPostsRepository_UpdatePostStatusById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePostStatusById'
+type MockPostsRepository_UpdatePostStatusById_Call struct {
+	*mock.Call
+}
+
+// UpdatePostStatusById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - from models.PostStatus
+//   - post models.Post
+func (_e *MockPostsRepository_Expecter) UpdatePostStatusById(ctx interface{}, id interface{}, from interface{}, post interface{}) *MockPostsRepository_UpdatePostStatusById_Call {
+	return &MockPostsRepository_UpdatePostStatusById_Call{Call: _e.mock.On("UpdatePostStatusById", ctx, id, from, post)}
+}
+
+func (_c *MockPostsRepository_UpdatePostStatusById_Call) Run(run func(ctx context.Context, id uuid.UUID, from models.PostStatus, post models.Post)) *MockPostsRepository_UpdatePostStatusById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.PostStatus
+		if args[2] != nil {
+			arg2 = args[2].(models.PostStatus)
+		}
+		var arg3 models.Post
+		if args[3] != nil {
+			arg3 = args[3].(models.Post)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostStatusById_Call) Return(err error) *MockPostsRepository_UpdatePostStatusById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPostsRepository_UpdatePostStatusById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, from models.PostStatus, post models.Post) error) *MockPostsRepository_UpdatePostStatusById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockScheduledPostsRepository creates a new instance of MockScheduledPostsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockScheduledPostsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockScheduledPostsRepository {
+	mock := &MockScheduledPostsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockScheduledPostsRepository is an autogenerated mock type for the ScheduledPostsRepository type
+type MockScheduledPostsRepository struct {
+	mock.Mock
+}
+
+type MockScheduledPostsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockScheduledPostsRepository) EXPECT() *MockScheduledPostsRepository_Expecter {
+	return &MockScheduledPostsRepository_Expecter{mock: &_m.Mock}
+}
+
+// PublishDuePosts provides a mock function for the type MockScheduledPostsRepository
+func (_mock *MockScheduledPostsRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error) {
+	ret := _mock.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishDuePosts")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) (int64, error)); ok {
+		return returnFunc(ctx, now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = returnFunc(ctx, now, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockScheduledPostsRepository_PublishDuePosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishDuePosts'
+type MockScheduledPostsRepository_PublishDuePosts_Call struct {
+	*mock.Call
+}
+
+// PublishDuePosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - limit int
+func (_e *MockScheduledPostsRepository_Expecter) PublishDuePosts(ctx interface{}, now interface{}, limit interface{}) *MockScheduledPostsRepository_PublishDuePosts_Call {
+	return &MockScheduledPostsRepository_PublishDuePosts_Call{Call: _e.mock.On("PublishDuePosts", ctx, now, limit)}
+}
+
+func (_c *MockScheduledPostsRepository_PublishDuePosts_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *MockScheduledPostsRepository_PublishDuePosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockScheduledPostsRepository_PublishDuePosts_Call) Return(n int64, err error) *MockScheduledPostsRepository_PublishDuePosts_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockScheduledPostsRepository_PublishDuePosts_Call) RunAndReturn(run func(ctx context.Context, now time.Time, limit int) (int64, error)) *MockScheduledPostsRepository_PublishDuePosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSpamScorer creates a new instance of MockSpamScorer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSpamScorer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSpamScorer {
+	mock := &MockSpamScorer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSpamScorer is an autogenerated mock type for the SpamScorer type
+type MockSpamScorer struct {
+	mock.Mock
+}
+
+type MockSpamScorer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSpamScorer) EXPECT() *MockSpamScorer_Expecter {
+	return &MockSpamScorer_Expecter{mock: &_m.Mock}
+}
+
+// ScoreComment provides a mock function for the type MockSpamScorer
+func (_mock *MockSpamScorer) ScoreComment(ctx context.Context, comment models.Comment) (models.SpamVerdict, error) {
+	ret := _mock.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScoreComment")
+	}
+
+	var r0 models.SpamVerdict
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) (models.SpamVerdict, error)); ok {
+		return returnFunc(ctx, comment)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) models.SpamVerdict); ok {
+		r0 = returnFunc(ctx, comment)
+	} else {
+		r0 = ret.Get(0).(models.SpamVerdict)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Comment) error); ok {
+		r1 = returnFunc(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSpamScorer_ScoreComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScoreComment'
+type MockSpamScorer_ScoreComment_Call struct {
+	*mock.Call
+}
+
+// ScoreComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - comment models.Comment
+func (_e *MockSpamScorer_Expecter) ScoreComment(ctx interface{}, comment interface{}) *MockSpamScorer_ScoreComment_Call {
+	return &MockSpamScorer_ScoreComment_Call{Call: _e.mock.On("ScoreComment", ctx, comment)}
+}
+
+func (_c *MockSpamScorer_ScoreComment_Call) Run(run func(ctx context.Context, comment models.Comment)) *MockSpamScorer_ScoreComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Comment
+		if args[1] != nil {
+			arg1 = args[1].(models.Comment)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSpamScorer_ScoreComment_Call) Return(spamVerdict models.SpamVerdict, err error) *MockSpamScorer_ScoreComment_Call {
+	_c.Call.Return(spamVerdict, err)
+	return _c
+}
+
+func (_c *MockSpamScorer_ScoreComment_Call) RunAndReturn(run func(ctx context.Context, comment models.Comment) (models.SpamVerdict, error)) *MockSpamScorer_ScoreComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSpamRule creates a new instance of MockSpamRule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSpamRule(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockPostsRepository {
-	mock := &MockPostsRepository{}
+}) *MockSpamRule {
+	mock := &MockSpamRule{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -26,68 +3172,388 @@ func NewMockPostsRepository(t interface {
 	return mock
 }
 
-// MockPostsRepository is an autogenerated mock type for the PostsRepository type
-type MockPostsRepository struct {
+// MockSpamRule is an autogenerated mock type for the SpamRule type
+type MockSpamRule struct {
 	mock.Mock
 }
 
-type MockPostsRepository_Expecter struct {
+type MockSpamRule_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockPostsRepository) EXPECT() *MockPostsRepository_Expecter {
-	return &MockPostsRepository_Expecter{mock: &_m.Mock}
+func (_m *MockSpamRule) EXPECT() *MockSpamRule_Expecter {
+	return &MockSpamRule_Expecter{mock: &_m.Mock}
 }
 
-// CreatePost provides a mock function for the type MockPostsRepository
-func (_mock *MockPostsRepository) CreatePost(ctx context.Context, post models.Post) (uuid.UUID, error) {
-	ret := _mock.Called(ctx, post)
+// Name provides a mock function for the type MockSpamRule
+func (_mock *MockSpamRule) Name() string {
+	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreatePost")
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockSpamRule_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type MockSpamRule_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *MockSpamRule_Expecter) Name() *MockSpamRule_Name_Call {
+	return &MockSpamRule_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *MockSpamRule_Name_Call) Run(run func()) *MockSpamRule_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSpamRule_Name_Call) Return(s string) *MockSpamRule_Name_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockSpamRule_Name_Call) RunAndReturn(run func() string) *MockSpamRule_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Score provides a mock function for the type MockSpamRule
+func (_mock *MockSpamRule) Score(ctx context.Context, comment models.Comment) (float64, error) {
+	ret := _mock.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Score")
+	}
+
+	var r0 float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) (float64, error)); ok {
+		return returnFunc(ctx, comment)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Comment) float64); ok {
+		r0 = returnFunc(ctx, comment)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Comment) error); ok {
+		r1 = returnFunc(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSpamRule_Score_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Score'
+type MockSpamRule_Score_Call struct {
+	*mock.Call
+}
+
+// Score is a helper method to define mock.On call
+//   - ctx context.Context
+//   - comment models.Comment
+func (_e *MockSpamRule_Expecter) Score(ctx interface{}, comment interface{}) *MockSpamRule_Score_Call {
+	return &MockSpamRule_Score_Call{Call: _e.mock.On("Score", ctx, comment)}
+}
+
+func (_c *MockSpamRule_Score_Call) Run(run func(ctx context.Context, comment models.Comment)) *MockSpamRule_Score_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Comment
+		if args[1] != nil {
+			arg1 = args[1].(models.Comment)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSpamRule_Score_Call) Return(f float64, err error) *MockSpamRule_Score_Call {
+	_c.Call.Return(f, err)
+	return _c
+}
+
+func (_c *MockSpamRule_Score_Call) RunAndReturn(run func(ctx context.Context, comment models.Comment) (float64, error)) *MockSpamRule_Score_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCommentHistory creates a new instance of MockCommentHistory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCommentHistory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCommentHistory {
+	mock := &MockCommentHistory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCommentHistory is an autogenerated mock type for the CommentHistory type
+type MockCommentHistory struct {
+	mock.Mock
+}
+
+type MockCommentHistory_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCommentHistory) EXPECT() *MockCommentHistory_Expecter {
+	return &MockCommentHistory_Expecter{mock: &_m.Mock}
+}
+
+// CountCommentsByAuthorSince provides a mock function for the type MockCommentHistory
+func (_mock *MockCommentHistory) CountCommentsByAuthorSince(ctx context.Context, authorId uuid.UUID, excludeId uuid.UUID, since time.Time) (int, error) {
+	ret := _mock.Called(ctx, authorId, excludeId, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountCommentsByAuthorSince")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) (int, error)); ok {
+		return returnFunc(ctx, authorId, excludeId, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) int); ok {
+		r0 = returnFunc(ctx, authorId, excludeId, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) error); ok {
+		r1 = returnFunc(ctx, authorId, excludeId, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentHistory_CountCommentsByAuthorSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountCommentsByAuthorSince'
+type MockCommentHistory_CountCommentsByAuthorSince_Call struct {
+	*mock.Call
+}
+
+// CountCommentsByAuthorSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorId uuid.UUID
+//   - excludeId uuid.UUID
+//   - since time.Time
+func (_e *MockCommentHistory_Expecter) CountCommentsByAuthorSince(ctx interface{}, authorId interface{}, excludeId interface{}, since interface{}) *MockCommentHistory_CountCommentsByAuthorSince_Call {
+	return &MockCommentHistory_CountCommentsByAuthorSince_Call{Call: _e.mock.On("CountCommentsByAuthorSince", ctx, authorId, excludeId, since)}
+}
+
+func (_c *MockCommentHistory_CountCommentsByAuthorSince_Call) Run(run func(ctx context.Context, authorId uuid.UUID, excludeId uuid.UUID, since time.Time)) *MockCommentHistory_CountCommentsByAuthorSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentHistory_CountCommentsByAuthorSince_Call) Return(n int, err error) *MockCommentHistory_CountCommentsByAuthorSince_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockCommentHistory_CountCommentsByAuthorSince_Call) RunAndReturn(run func(ctx context.Context, authorId uuid.UUID, excludeId uuid.UUID, since time.Time) (int, error)) *MockCommentHistory_CountCommentsByAuthorSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountDuplicateComments provides a mock function for the type MockCommentHistory
+func (_mock *MockCommentHistory) CountDuplicateComments(ctx context.Context, body string, excludeId uuid.UUID, since time.Time) (int, error) {
+	ret := _mock.Called(ctx, body, excludeId, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountDuplicateComments")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, time.Time) (int, error)); ok {
+		return returnFunc(ctx, body, excludeId, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, time.Time) int); ok {
+		r0 = returnFunc(ctx, body, excludeId, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, time.Time) error); ok {
+		r1 = returnFunc(ctx, body, excludeId, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommentHistory_CountDuplicateComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountDuplicateComments'
+type MockCommentHistory_CountDuplicateComments_Call struct {
+	*mock.Call
+}
+
+// CountDuplicateComments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - body string
+//   - excludeId uuid.UUID
+//   - since time.Time
+func (_e *MockCommentHistory_Expecter) CountDuplicateComments(ctx interface{}, body interface{}, excludeId interface{}, since interface{}) *MockCommentHistory_CountDuplicateComments_Call {
+	return &MockCommentHistory_CountDuplicateComments_Call{Call: _e.mock.On("CountDuplicateComments", ctx, body, excludeId, since)}
+}
+
+func (_c *MockCommentHistory_CountDuplicateComments_Call) Run(run func(ctx context.Context, body string, excludeId uuid.UUID, since time.Time)) *MockCommentHistory_CountDuplicateComments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommentHistory_CountDuplicateComments_Call) Return(n int, err error) *MockCommentHistory_CountDuplicateComments_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockCommentHistory_CountDuplicateComments_Call) RunAndReturn(run func(ctx context.Context, body string, excludeId uuid.UUID, since time.Time) (int, error)) *MockCommentHistory_CountDuplicateComments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTagsRepository creates a new instance of MockTagsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTagsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTagsRepository {
+	mock := &MockTagsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTagsRepository is an autogenerated mock type for the TagsRepository type
+type MockTagsRepository struct {
+	mock.Mock
+}
+
+type MockTagsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTagsRepository) EXPECT() *MockTagsRepository_Expecter {
+	return &MockTagsRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateTag provides a mock function for the type MockTagsRepository
+func (_mock *MockTagsRepository) CreateTag(ctx context.Context, tag models.Tag) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTag")
 	}
 
 	var r0 uuid.UUID
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Post) (uuid.UUID, error)); ok {
-		return returnFunc(ctx, post)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Tag) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, tag)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Post) uuid.UUID); ok {
-		r0 = returnFunc(ctx, post)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Tag) uuid.UUID); ok {
+		r0 = returnFunc(ctx, tag)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(uuid.UUID)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Post) error); ok {
-		r1 = returnFunc(ctx, post)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Tag) error); ok {
+		r1 = returnFunc(ctx, tag)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockPostsRepository_CreatePost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePost'
-type MockPostsRepository_CreatePost_Call struct {
+// MockTagsRepository_CreateTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTag'
+type MockTagsRepository_CreateTag_Call struct {
 	*mock.Call
 }
 
-// CreatePost is a helper method to define mock.On call
+// CreateTag is a helper method to define mock.On call
 //   - ctx context.Context
-//   - post models.Post
-func (_e *MockPostsRepository_Expecter) CreatePost(ctx interface{}, post interface{}) *MockPostsRepository_CreatePost_Call {
-	return &MockPostsRepository_CreatePost_Call{Call: _e.mock.On("CreatePost", ctx, post)}
+//   - tag models.Tag
+func (_e *MockTagsRepository_Expecter) CreateTag(ctx interface{}, tag interface{}) *MockTagsRepository_CreateTag_Call {
+	return &MockTagsRepository_CreateTag_Call{Call: _e.mock.On("CreateTag", ctx, tag)}
 }
 
-func (_c *MockPostsRepository_CreatePost_Call) Run(run func(ctx context.Context, post models.Post)) *MockPostsRepository_CreatePost_Call {
+func (_c *MockTagsRepository_CreateTag_Call) Run(run func(ctx context.Context, tag models.Tag)) *MockTagsRepository_CreateTag_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 models.Post
+		var arg1 models.Tag
 		if args[1] != nil {
-			arg1 = args[1].(models.Post)
+			arg1 = args[1].(models.Tag)
 		}
 		run(
 			arg0,
@@ -97,22 +3563,22 @@ func (_c *MockPostsRepository_CreatePost_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockPostsRepository_CreatePost_Call) Return(uUID uuid.UUID, err error) *MockPostsRepository_CreatePost_Call {
+func (_c *MockTagsRepository_CreateTag_Call) Return(uUID uuid.UUID, err error) *MockTagsRepository_CreateTag_Call {
 	_c.Call.Return(uUID, err)
 	return _c
 }
 
-func (_c *MockPostsRepository_CreatePost_Call) RunAndReturn(run func(ctx context.Context, post models.Post) (uuid.UUID, error)) *MockPostsRepository_CreatePost_Call {
+func (_c *MockTagsRepository_CreateTag_Call) RunAndReturn(run func(ctx context.Context, tag models.Tag) (uuid.UUID, error)) *MockTagsRepository_CreateTag_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeletePostById provides a mock function for the type MockPostsRepository
-func (_mock *MockPostsRepository) DeletePostById(ctx context.Context, id uuid.UUID) error {
+// DeleteTagById provides a mock function for the type MockTagsRepository
+func (_mock *MockTagsRepository) DeleteTagById(ctx context.Context, id uuid.UUID) error {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeletePostById")
+		panic("no return value specified for DeleteTagById")
 	}
 
 	var r0 error
@@ -124,19 +3590,19 @@ func (_mock *MockPostsRepository) DeletePostById(ctx context.Context, id uuid.UU
 	return r0
 }
 
-// MockPostsRepository_DeletePostById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePostById'
-type MockPostsRepository_DeletePostById_Call struct {
+// MockTagsRepository_DeleteTagById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTagById'
+type MockTagsRepository_DeleteTagById_Call struct {
 	*mock.Call
 }
 
-// DeletePostById is a helper method to define mock.On call
+// DeleteTagById is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockPostsRepository_Expecter) DeletePostById(ctx interface{}, id interface{}) *MockPostsRepository_DeletePostById_Call {
-	return &MockPostsRepository_DeletePostById_Call{Call: _e.mock.On("DeletePostById", ctx, id)}
+func (_e *MockTagsRepository_Expecter) DeleteTagById(ctx interface{}, id interface{}) *MockTagsRepository_DeleteTagById_Call {
+	return &MockTagsRepository_DeleteTagById_Call{Call: _e.mock.On("DeleteTagById", ctx, id)}
 }
 
-func (_c *MockPostsRepository_DeletePostById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockPostsRepository_DeletePostById_Call {
+func (_c *MockTagsRepository_DeleteTagById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTagsRepository_DeleteTagById_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -154,59 +3620,58 @@ func (_c *MockPostsRepository_DeletePostById_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockPostsRepository_DeletePostById_Call) Return(err error) *MockPostsRepository_DeletePostById_Call {
+func (_c *MockTagsRepository_DeleteTagById_Call) Return(err error) *MockTagsRepository_DeleteTagById_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockPostsRepository_DeletePostById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockPostsRepository_DeletePostById_Call {
+func (_c *MockTagsRepository_DeleteTagById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockTagsRepository_DeleteTagById_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindAllPosts provides a mock function for the type MockPostsRepository
-func (_mock *MockPostsRepository) FindAllPosts(ctx context.Context, limit int, offset int, authorId uuid.UUID) ([]models.Post, error) {
-	ret := _mock.Called(ctx, limit, offset, authorId)
+// FindAllTags provides a mock function for the type MockTagsRepository
+func (_mock *MockTagsRepository) FindAllTags(ctx context.Context, limit int, offset int) ([]models.Tag, error) {
+	ret := _mock.Called(ctx, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindAllPosts")
+		panic("no return value specified for FindAllTags")
 	}
 
-	var r0 []models.Post
+	var r0 []models.Tag
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, uuid.UUID) ([]models.Post, error)); ok {
-		return returnFunc(ctx, limit, offset, authorId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Tag, error)); ok {
+		return returnFunc(ctx, limit, offset)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, uuid.UUID) []models.Post); ok {
-		r0 = returnFunc(ctx, limit, offset, authorId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []models.Tag); ok {
+		r0 = returnFunc(ctx, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Post)
+			r0 = ret.Get(0).([]models.Tag)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, limit, offset, authorId)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockPostsRepository_FindAllPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAllPosts'
-type MockPostsRepository_FindAllPosts_Call struct {
+// MockTagsRepository_FindAllTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAllTags'
+type MockTagsRepository_FindAllTags_Call struct {
 	*mock.Call
 }
 
-// FindAllPosts is a helper method to define mock.On call
+// FindAllTags is a helper method to define mock.On call
 //   - ctx context.Context
 //   - limit int
 //   - offset int
-//   - authorId uuid.UUID
-func (_e *MockPostsRepository_Expecter) FindAllPosts(ctx interface{}, limit interface{}, offset interface{}, authorId interface{}) *MockPostsRepository_FindAllPosts_Call {
-	return &MockPostsRepository_FindAllPosts_Call{Call: _e.mock.On("FindAllPosts", ctx, limit, offset, authorId)}
+func (_e *MockTagsRepository_Expecter) FindAllTags(ctx interface{}, limit interface{}, offset interface{}) *MockTagsRepository_FindAllTags_Call {
+	return &MockTagsRepository_FindAllTags_Call{Call: _e.mock.On("FindAllTags", ctx, limit, offset)}
 }
 
-func (_c *MockPostsRepository_FindAllPosts_Call) Run(run func(ctx context.Context, limit int, offset int, authorId uuid.UUID)) *MockPostsRepository_FindAllPosts_Call {
+func (_c *MockTagsRepository_FindAllTags_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockTagsRepository_FindAllTags_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -220,70 +3685,64 @@ func (_c *MockPostsRepository_FindAllPosts_Call) Run(run func(ctx context.Contex
 		if args[2] != nil {
 			arg2 = args[2].(int)
 		}
-		var arg3 uuid.UUID
-		if args[3] != nil {
-			arg3 = args[3].(uuid.UUID)
-		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockPostsRepository_FindAllPosts_Call) Return(posts []models.Post, err error) *MockPostsRepository_FindAllPosts_Call {
-	_c.Call.Return(posts, err)
+func (_c *MockTagsRepository_FindAllTags_Call) Return(tags []models.Tag, err error) *MockTagsRepository_FindAllTags_Call {
+	_c.Call.Return(tags, err)
 	return _c
 }
 
-func (_c *MockPostsRepository_FindAllPosts_Call) RunAndReturn(run func(ctx context.Context, limit int, offset int, authorId uuid.UUID) ([]models.Post, error)) *MockPostsRepository_FindAllPosts_Call {
+func (_c *MockTagsRepository_FindAllTags_Call) RunAndReturn(run func(ctx context.Context, limit int, offset int) ([]models.Tag, error)) *MockTagsRepository_FindAllTags_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindPostByIdAndAuthorId provides a mock function for the type MockPostsRepository
-func (_mock *MockPostsRepository) FindPostByIdAndAuthorId(ctx context.Context, id uuid.UUID, authorId uuid.UUID) (models.Post, error) {
-	ret := _mock.Called(ctx, id, authorId)
+// FindTagById provides a mock function for the type MockTagsRepository
+func (_mock *MockTagsRepository) FindTagById(ctx context.Context, id uuid.UUID) (models.Tag, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindPostByIdAndAuthorId")
+		panic("no return value specified for FindTagById")
 	}
 
-	var r0 models.Post
+	var r0 models.Tag
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (models.Post, error)); ok {
-		return returnFunc(ctx, id, authorId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (models.Tag, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) models.Post); ok {
-		r0 = returnFunc(ctx, id, authorId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) models.Tag); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Get(0).(models.Post)
+		r0 = ret.Get(0).(models.Tag)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, id, authorId)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockPostsRepository_FindPostByIdAndAuthorId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostByIdAndAuthorId'
-type MockPostsRepository_FindPostByIdAndAuthorId_Call struct {
+// MockTagsRepository_FindTagById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTagById'
+type MockTagsRepository_FindTagById_Call struct {
 	*mock.Call
 }
 
-// FindPostByIdAndAuthorId is a helper method to define mock.On call
+// FindTagById is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-//   - authorId uuid.UUID
-func (_e *MockPostsRepository_Expecter) FindPostByIdAndAuthorId(ctx interface{}, id interface{}, authorId interface{}) *MockPostsRepository_FindPostByIdAndAuthorId_Call {
-	return &MockPostsRepository_FindPostByIdAndAuthorId_Call{Call: _e.mock.On("FindPostByIdAndAuthorId", ctx, id, authorId)}
+func (_e *MockTagsRepository_Expecter) FindTagById(ctx interface{}, id interface{}) *MockTagsRepository_FindTagById_Call {
+	return &MockTagsRepository_FindTagById_Call{Call: _e.mock.On("FindTagById", ctx, id)}
 }
 
-func (_c *MockPostsRepository_FindPostByIdAndAuthorId_Call) Run(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID)) *MockPostsRepository_FindPostByIdAndAuthorId_Call {
+func (_c *MockTagsRepository_FindTagById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTagsRepository_FindTagById_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -293,61 +3752,55 @@ func (_c *MockPostsRepository_FindPostByIdAndAuthorId_Call) Run(run func(ctx con
 		if args[1] != nil {
 			arg1 = args[1].(uuid.UUID)
 		}
-		var arg2 uuid.UUID
-		if args[2] != nil {
-			arg2 = args[2].(uuid.UUID)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockPostsRepository_FindPostByIdAndAuthorId_Call) Return(post models.Post, err error) *MockPostsRepository_FindPostByIdAndAuthorId_Call {
-	_c.Call.Return(post, err)
+func (_c *MockTagsRepository_FindTagById_Call) Return(tag models.Tag, err error) *MockTagsRepository_FindTagById_Call {
+	_c.Call.Return(tag, err)
 	return _c
 }
 
-func (_c *MockPostsRepository_FindPostByIdAndAuthorId_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID) (models.Post, error)) *MockPostsRepository_FindPostByIdAndAuthorId_Call {
+func (_c *MockTagsRepository_FindTagById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (models.Tag, error)) *MockTagsRepository_FindTagById_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdatePostByIdAndAuthorId provides a mock function for the type MockPostsRepository
-func (_mock *MockPostsRepository) UpdatePostByIdAndAuthorId(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post) error {
-	ret := _mock.Called(ctx, id, authorId, post)
+// UpdateTagById provides a mock function for the type MockTagsRepository
+func (_mock *MockTagsRepository) UpdateTagById(ctx context.Context, id uuid.UUID, tag models.Tag) error {
+	ret := _mock.Called(ctx, id, tag)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdatePostByIdAndAuthorId")
+		panic("no return value specified for UpdateTagById")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, models.Post) error); ok {
-		r0 = returnFunc(ctx, id, authorId, post)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Tag) error); ok {
+		r0 = returnFunc(ctx, id, tag)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockPostsRepository_UpdatePostByIdAndAuthorId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePostByIdAndAuthorId'
-type MockPostsRepository_UpdatePostByIdAndAuthorId_Call struct {
+// MockTagsRepository_UpdateTagById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTagById'
+type MockTagsRepository_UpdateTagById_Call struct {
 	*mock.Call
 }
 
-// UpdatePostByIdAndAuthorId is a helper method to define mock.On call
+// UpdateTagById is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-//   - authorId uuid.UUID
-//   - post models.Post
-func (_e *MockPostsRepository_Expecter) UpdatePostByIdAndAuthorId(ctx interface{}, id interface{}, authorId interface{}, post interface{}) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
-	return &MockPostsRepository_UpdatePostByIdAndAuthorId_Call{Call: _e.mock.On("UpdatePostByIdAndAuthorId", ctx, id, authorId, post)}
+//   - tag models.Tag
+func (_e *MockTagsRepository_Expecter) UpdateTagById(ctx interface{}, id interface{}, tag interface{}) *MockTagsRepository_UpdateTagById_Call {
+	return &MockTagsRepository_UpdateTagById_Call{Call: _e.mock.On("UpdateTagById", ctx, id, tag)}
 }
 
-func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) Run(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post)) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+func (_c *MockTagsRepository_UpdateTagById_Call) Run(run func(ctx context.Context, id uuid.UUID, tag models.Tag)) *MockTagsRepository_UpdateTagById_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -357,30 +3810,25 @@ func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) Run(run func(ctx c
 		if args[1] != nil {
 			arg1 = args[1].(uuid.UUID)
 		}
-		var arg2 uuid.UUID
+		var arg2 models.Tag
 		if args[2] != nil {
-			arg2 = args[2].(uuid.UUID)
-		}
-		var arg3 models.Post
-		if args[3] != nil {
-			arg3 = args[3].(models.Post)
+			arg2 = args[2].(models.Tag)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) Return(err error) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+func (_c *MockTagsRepository_UpdateTagById_Call) Return(err error) *MockTagsRepository_UpdateTagById_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockPostsRepository_UpdatePostByIdAndAuthorId_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, authorId uuid.UUID, post models.Post) error) *MockPostsRepository_UpdatePostByIdAndAuthorId_Call {
+func (_c *MockTagsRepository_UpdateTagById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, tag models.Tag) error) *MockTagsRepository_UpdateTagById_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -412,6 +3860,72 @@ func (_m *MockUsersRepository) EXPECT() *MockUsersRepository_Expecter {
 	return &MockUsersRepository_Expecter{mock: &_m.Mock}
 }
 
+// CountUsers provides a mock function for the type MockUsersRepository
+func (_mock *MockUsersRepository) CountUsers(ctx context.Context, filters []query.Filter) (int, error) {
+	ret := _mock.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []query.Filter) (int, error)); ok {
+		return returnFunc(ctx, filters)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []query.Filter) int); ok {
+		r0 = returnFunc(ctx, filters)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []query.Filter) error); ok {
+		r1 = returnFunc(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsersRepository_CountUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountUsers'
+type MockUsersRepository_CountUsers_Call struct {
+	*mock.Call
+}
+
+// CountUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filters []query.Filter
+func (_e *MockUsersRepository_Expecter) CountUsers(ctx interface{}, filters interface{}) *MockUsersRepository_CountUsers_Call {
+	return &MockUsersRepository_CountUsers_Call{Call: _e.mock.On("CountUsers", ctx, filters)}
+}
+
+func (_c *MockUsersRepository_CountUsers_Call) Run(run func(ctx context.Context, filters []query.Filter)) *MockUsersRepository_CountUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []query.Filter
+		if args[1] != nil {
+			arg1 = args[1].([]query.Filter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_CountUsers_Call) Return(n int, err error) *MockUsersRepository_CountUsers_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUsersRepository_CountUsers_Call) RunAndReturn(run func(ctx context.Context, filters []query.Filter) (int, error)) *MockUsersRepository_CountUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockUsersRepository
 func (_mock *MockUsersRepository) CreateUser(ctx context.Context, user models.User) (uuid.UUID, error) {
 	ret := _mock.Called(ctx, user)
@@ -481,16 +3995,16 @@ func (_c *MockUsersRepository_CreateUser_Call) RunAndReturn(run func(ctx context
 }
 
 // DeleteUserById provides a mock function for the type MockUsersRepository
-func (_mock *MockUsersRepository) DeleteUserById(ctx context.Context, id uuid.UUID) error {
-	ret := _mock.Called(ctx, id)
+func (_mock *MockUsersRepository) DeleteUserById(ctx context.Context, id uuid.UUID, version *time.Time) error {
+	ret := _mock.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUserById")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) error); ok {
+		r0 = returnFunc(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -505,11 +4019,12 @@ type MockUsersRepository_DeleteUserById_Call struct {
 // DeleteUserById is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockUsersRepository_Expecter) DeleteUserById(ctx interface{}, id interface{}) *MockUsersRepository_DeleteUserById_Call {
-	return &MockUsersRepository_DeleteUserById_Call{Call: _e.mock.On("DeleteUserById", ctx, id)}
+//   - version *time.Time
+func (_e *MockUsersRepository_Expecter) DeleteUserById(ctx interface{}, id interface{}, version interface{}) *MockUsersRepository_DeleteUserById_Call {
+	return &MockUsersRepository_DeleteUserById_Call{Call: _e.mock.On("DeleteUserById", ctx, id, version)}
 }
 
-func (_c *MockUsersRepository_DeleteUserById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockUsersRepository_DeleteUserById_Call {
+func (_c *MockUsersRepository_DeleteUserById_Call) Run(run func(ctx context.Context, id uuid.UUID, version *time.Time)) *MockUsersRepository_DeleteUserById_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -519,9 +4034,14 @@ func (_c *MockUsersRepository_DeleteUserById_Call) Run(run func(ctx context.Cont
 		if args[1] != nil {
 			arg1 = args[1].(uuid.UUID)
 		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -532,14 +4052,14 @@ func (_c *MockUsersRepository_DeleteUserById_Call) Return(err error) *MockUsersR
 	return _c
 }
 
-func (_c *MockUsersRepository_DeleteUserById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockUsersRepository_DeleteUserById_Call {
+func (_c *MockUsersRepository_DeleteUserById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, version *time.Time) error) *MockUsersRepository_DeleteUserById_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // FindAllUsers provides a mock function for the type MockUsersRepository
-func (_mock *MockUsersRepository) FindAllUsers(ctx context.Context, limit int, offset int) ([]models.User, error) {
-	ret := _mock.Called(ctx, limit, offset)
+func (_mock *MockUsersRepository) FindAllUsers(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, error) {
+	ret := _mock.Called(ctx, page, filters)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAllUsers")
@@ -547,18 +4067,18 @@ func (_mock *MockUsersRepository) FindAllUsers(ctx context.Context, limit int, o
 
 	var r0 []models.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]models.User, error)); ok {
-		return returnFunc(ctx, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Page, []query.Filter) ([]models.User, error)); ok {
+		return returnFunc(ctx, page, filters)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []models.User); ok {
-		r0 = returnFunc(ctx, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Page, []query.Filter) []models.User); ok {
+		r0 = returnFunc(ctx, page, filters)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = returnFunc(ctx, limit, offset)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Page, []query.Filter) error); ok {
+		r1 = returnFunc(ctx, page, filters)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -572,25 +4092,25 @@ type MockUsersRepository_FindAllUsers_Call struct {
 
 // FindAllUsers is a helper method to define mock.On call
 //   - ctx context.Context
-//   - limit int
-//   - offset int
-func (_e *MockUsersRepository_Expecter) FindAllUsers(ctx interface{}, limit interface{}, offset interface{}) *MockUsersRepository_FindAllUsers_Call {
-	return &MockUsersRepository_FindAllUsers_Call{Call: _e.mock.On("FindAllUsers", ctx, limit, offset)}
+//   - page models.Page
+//   - filters []query.Filter
+func (_e *MockUsersRepository_Expecter) FindAllUsers(ctx interface{}, page interface{}, filters interface{}) *MockUsersRepository_FindAllUsers_Call {
+	return &MockUsersRepository_FindAllUsers_Call{Call: _e.mock.On("FindAllUsers", ctx, page, filters)}
 }
 
-func (_c *MockUsersRepository_FindAllUsers_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockUsersRepository_FindAllUsers_Call {
+func (_c *MockUsersRepository_FindAllUsers_Call) Run(run func(ctx context.Context, page models.Page, filters []query.Filter)) *MockUsersRepository_FindAllUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 models.Page
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(models.Page)
 		}
-		var arg2 int
+		var arg2 []query.Filter
 		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg2 = args[2].([]query.Filter)
 		}
 		run(
 			arg0,
@@ -606,7 +4126,7 @@ func (_c *MockUsersRepository_FindAllUsers_Call) Return(users []models.User, err
 	return _c
 }
 
-func (_c *MockUsersRepository_FindAllUsers_Call) RunAndReturn(run func(ctx context.Context, limit int, offset int) ([]models.User, error)) *MockUsersRepository_FindAllUsers_Call {
+func (_c *MockUsersRepository_FindAllUsers_Call) RunAndReturn(run func(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, error)) *MockUsersRepository_FindAllUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -677,6 +4197,72 @@ func (_c *MockUsersRepository_FindUserById_Call) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// FindUserByUsernameOrEmail provides a mock function for the type MockUsersRepository
+func (_mock *MockUsersRepository) FindUserByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (models.User, error) {
+	ret := _mock.Called(ctx, usernameOrEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByUsernameOrEmail")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return returnFunc(ctx, usernameOrEmail)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = returnFunc(ctx, usernameOrEmail)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, usernameOrEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsersRepository_FindUserByUsernameOrEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByUsernameOrEmail'
+type MockUsersRepository_FindUserByUsernameOrEmail_Call struct {
+	*mock.Call
+}
+
+// FindUserByUsernameOrEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - usernameOrEmail string
+func (_e *MockUsersRepository_Expecter) FindUserByUsernameOrEmail(ctx interface{}, usernameOrEmail interface{}) *MockUsersRepository_FindUserByUsernameOrEmail_Call {
+	return &MockUsersRepository_FindUserByUsernameOrEmail_Call{Call: _e.mock.On("FindUserByUsernameOrEmail", ctx, usernameOrEmail)}
+}
+
+func (_c *MockUsersRepository_FindUserByUsernameOrEmail_Call) Run(run func(ctx context.Context, usernameOrEmail string)) *MockUsersRepository_FindUserByUsernameOrEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_FindUserByUsernameOrEmail_Call) Return(user models.User, err error) *MockUsersRepository_FindUserByUsernameOrEmail_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUsersRepository_FindUserByUsernameOrEmail_Call) RunAndReturn(run func(ctx context.Context, usernameOrEmail string) (models.User, error)) *MockUsersRepository_FindUserByUsernameOrEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUserById provides a mock function for the type MockUsersRepository
 func (_mock *MockUsersRepository) UpdateUserById(ctx context.Context, id uuid.UUID, user models.User) error {
 	ret := _mock.Called(ctx, id, user)
@@ -739,3 +4325,129 @@ func (_c *MockUsersRepository_UpdateUserById_Call) RunAndReturn(run func(ctx con
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateUserPasswordById provides a mock function for the type MockUsersRepository
+func (_mock *MockUsersRepository) UpdateUserPasswordById(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	ret := _mock.Called(ctx, id, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserPasswordById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUsersRepository_UpdateUserPasswordById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserPasswordById'
+type MockUsersRepository_UpdateUserPasswordById_Call struct {
+	*mock.Call
+}
+
+// UpdateUserPasswordById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - hashedPassword string
+func (_e *MockUsersRepository_Expecter) UpdateUserPasswordById(ctx interface{}, id interface{}, hashedPassword interface{}) *MockUsersRepository_UpdateUserPasswordById_Call {
+	return &MockUsersRepository_UpdateUserPasswordById_Call{Call: _e.mock.On("UpdateUserPasswordById", ctx, id, hashedPassword)}
+}
+
+func (_c *MockUsersRepository_UpdateUserPasswordById_Call) Run(run func(ctx context.Context, id uuid.UUID, hashedPassword string)) *MockUsersRepository_UpdateUserPasswordById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateUserPasswordById_Call) Return(err error) *MockUsersRepository_UpdateUserPasswordById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateUserPasswordById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, hashedPassword string) error) *MockUsersRepository_UpdateUserPasswordById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserRoleById provides a mock function for the type MockUsersRepository
+func (_mock *MockUsersRepository) UpdateUserRoleById(ctx context.Context, id uuid.UUID, role models.Role) error {
+	ret := _mock.Called(ctx, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserRoleById")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Role) error); ok {
+		r0 = returnFunc(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUsersRepository_UpdateUserRoleById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserRoleById'
+type MockUsersRepository_UpdateUserRoleById_Call struct {
+	*mock.Call
+}
+
+// UpdateUserRoleById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - role models.Role
+func (_e *MockUsersRepository_Expecter) UpdateUserRoleById(ctx interface{}, id interface{}, role interface{}) *MockUsersRepository_UpdateUserRoleById_Call {
+	return &MockUsersRepository_UpdateUserRoleById_Call{Call: _e.mock.On("UpdateUserRoleById", ctx, id, role)}
+}
+
+func (_c *MockUsersRepository_UpdateUserRoleById_Call) Run(run func(ctx context.Context, id uuid.UUID, role models.Role)) *MockUsersRepository_UpdateUserRoleById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 models.Role
+		if args[2] != nil {
+			arg2 = args[2].(models.Role)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateUserRoleById_Call) Return(err error) *MockUsersRepository_UpdateUserRoleById_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateUserRoleById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, role models.Role) error) *MockUsersRepository_UpdateUserRoleById_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,48 @@
+package services
+
+import "github.com/gera9/blog/internal/models"
+
+// fetchPage reads page with fetch, asking for one more row than the page
+// holds to learn whether the listing goes on past it. Offset pages are also
+// counted with count, which keyset pages skip since they are meant for
+// listings too long to count on every request.
+func fetchPage[T any](page models.Page, fetch func(page models.Page) ([]T, error), count func() (int, error)) ([]T, models.PageInfo, error) {
+	info := models.PageInfo{
+		HasPrev: page.After != nil || (page.Before == nil && page.Offset > 0),
+		HasNext: page.Before != nil,
+	}
+
+	probe := page
+	probe.Limit++
+
+	rows, err := fetch(probe)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	if page.After == nil && page.Before == nil {
+		// A first page holding the whole listing needs no counting.
+		total := len(rows)
+		if page.Offset > 0 || total > page.Limit {
+			total, err = count()
+			if err != nil {
+				return nil, models.PageInfo{}, err
+			}
+		}
+		info.Total = &total
+	}
+
+	if len(rows) <= page.Limit {
+		return rows, info, nil
+	}
+
+	// Rows before a keyset come newest first too, so the extra one is the
+	// first rather than the last.
+	if page.Before != nil {
+		info.HasPrev = true
+		return rows[1:], info, nil
+	}
+
+	info.HasNext = true
+	return rows[:page.Limit], info, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fetchPage(t *testing.T) {
+	keyset := &models.Keyset{CreatedAt: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC), Id: uuid.New()}
+
+	tests := []struct {
+		name     string
+		page     models.Page
+		rows     []int
+		total    int
+		want     []int
+		wantInfo models.PageInfo
+	}{
+		{
+			name:     "Should count the first page holding the whole listing",
+			page:     models.Page{Limit: 3},
+			rows:     []int{1, 2},
+			want:     []int{1, 2},
+			wantInfo: models.PageInfo{Total: utils.Ptr(2)},
+		},
+		{
+			name:     "Should drop the row past the page",
+			page:     models.Page{Limit: 2},
+			rows:     []int{1, 2, 3},
+			total:    5,
+			want:     []int{1, 2},
+			wantInfo: models.PageInfo{HasNext: true, Total: utils.Ptr(5)},
+		},
+		{
+			name:     "Should report pages past an offset",
+			page:     models.Page{Limit: 2, Offset: 2},
+			rows:     []int{3},
+			total:    3,
+			want:     []int{3},
+			wantInfo: models.PageInfo{HasPrev: true, Total: utils.Ptr(3)},
+		},
+		{
+			name:     "Should report pages after a keyset",
+			page:     models.Page{Limit: 2, After: keyset},
+			rows:     []int{3, 4, 5},
+			want:     []int{3, 4},
+			wantInfo: models.PageInfo{HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "Should drop the newest row before a keyset",
+			page:     models.Page{Limit: 2, Before: keyset},
+			rows:     []int{1, 2, 3},
+			want:     []int{2, 3},
+			wantInfo: models.PageInfo{HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "Should report the first page before a keyset",
+			page:     models.Page{Limit: 2, Before: keyset},
+			rows:     []int{1},
+			want:     []int{1},
+			wantInfo: models.PageInfo{HasNext: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetched models.Page
+			got, info, err := fetchPage(tt.page, func(page models.Page) ([]int, error) {
+				fetched = page
+				return tt.rows, nil
+			}, func() (int, error) {
+				if tt.total == 0 {
+					t.Fatal("counted a page that did not need it")
+				}
+				return tt.total, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.page.Limit+1, fetched.Limit)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantInfo, info)
+		})
+	}
+}
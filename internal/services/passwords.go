@@ -0,0 +1,76 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrPasswordMismatch = errors.New("password does not match")
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hashedPassword, password string) error
+	NeedsRehash(hashedPassword string) bool
+}
+
+type bcryptHasher struct {
+	cost            int
+	acceptPlaintext bool
+}
+
+// NewBcryptHasher returns a PasswordHasher backed by bcrypt. Costs outside of
+// the range accepted by bcrypt fall back to bcrypt.DefaultCost.
+//
+// acceptPlaintext lets the passwords stored in plaintext before hashing was
+// introduced still log in, so they get hashed on the way. It is a temporary
+// migration path, to be turned off once no plaintext row is left and removed
+// afterwards.
+func NewBcryptHasher(cost int, acceptPlaintext bool) *bcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &bcryptHasher{cost: cost, acceptPlaintext: acceptPlaintext}
+}
+
+func (h bcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+// Compare checks password against hashedPassword. Values that are not bcrypt
+// hashes are legacy plaintext rows: they never match unless the hasher accepts
+// plaintext, in which case they are compared in constant time so they can be
+// upgraded on the next successful login.
+func (h bcryptHasher) Compare(hashedPassword, password string) error {
+	if _, err := bcrypt.Cost([]byte(hashedPassword)); err != nil {
+		if !h.acceptPlaintext || subtle.ConstantTimeCompare([]byte(hashedPassword), []byte(password)) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+
+	return err
+}
+
+// NeedsRehash reports whether hashedPassword was produced with parameters
+// other than the current ones, including legacy plaintext values.
+func (h bcryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewBcryptHasher(t *testing.T) {
+	tests := []struct {
+		name string
+		cost int
+		want int
+	}{
+		{
+			name: "Should keep a valid cost",
+			cost: bcrypt.MinCost,
+			want: bcrypt.MinCost,
+		},
+		{
+			name: "Should fall back to the default cost when unset",
+			cost: 0,
+			want: bcrypt.DefaultCost,
+		},
+		{
+			name: "Should fall back to the default cost when too high",
+			cost: bcrypt.MaxCost + 1,
+			want: bcrypt.DefaultCost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewBcryptHasher(tt.cost, false).cost)
+		})
+	}
+}
+
+func Test_bcryptHasher(t *testing.T) {
+	h := NewBcryptHasher(bcrypt.MinCost, false)
+
+	hashed, err := h.Hash("s3cr3t")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotEqual(t, "s3cr3t", hashed)
+	assert.NoError(t, h.Compare(hashed, "s3cr3t"))
+	assert.ErrorIs(t, h.Compare(hashed, "wrong"), ErrPasswordMismatch)
+	assert.False(t, h.NeedsRehash(hashed))
+	assert.True(t, NewBcryptHasher(bcrypt.MinCost+1, false).NeedsRehash(hashed))
+}
+
+func Test_bcryptHasher_LegacyPlaintext(t *testing.T) {
+	h := NewBcryptHasher(bcrypt.MinCost, true)
+
+	assert.NoError(t, h.Compare("hashed_pwd_1", "hashed_pwd_1"))
+	assert.ErrorIs(t, h.Compare("hashed_pwd_1", "wrong"), ErrPasswordMismatch)
+	assert.True(t, h.NeedsRehash("hashed_pwd_1"))
+
+	// Once the migration is over, plaintext rows no longer log in.
+	h = NewBcryptHasher(bcrypt.MinCost, false)
+
+	assert.ErrorIs(t, h.Compare("hashed_pwd_1", "hashed_pwd_1"), ErrPasswordMismatch)
+}
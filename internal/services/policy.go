@@ -0,0 +1,98 @@
+package services
+
+import "github.com/gera9/blog/internal/models"
+
+// Permissions are written as resource:action[:scope], where the "own" scope
+// only applies to resources that belong to the caller and "any" to all of them.
+const (
+	PermPostsCreate    = "posts:create"
+	PermPostsUpdateOwn = "posts:update:own"
+	PermPostsUpdateAny = "posts:update:any"
+	PermPostsDeleteOwn = "posts:delete:own"
+	PermPostsDeleteAny = "posts:delete:any"
+
+	PermPostsPublishAny         = "posts:publish:any"
+	PermPostsReadUnpublishedAny = "posts:read_unpublished:any"
+
+	PermTaxonomyManage = "taxonomy:manage"
+
+	PermCommentsCreate    = "comments:create"
+	PermCommentsUpdateOwn = "comments:update:own"
+	PermCommentsDeleteOwn = "comments:delete:own"
+	PermCommentsDeleteAny = "comments:delete:any"
+	PermCommentsModerate  = "comments:moderate"
+
+	PermReactionsCreate = "reactions:create"
+
+	PermUsersReadPrivateAny = "users:read_private:any"
+	PermUsersUpdateAny      = "users:update:any"
+	PermUsersDeleteAny      = "users:delete:any"
+	PermUsersManage         = "users:manage"
+)
+
+var authorGrants = []string{
+	PermPostsCreate,
+	PermPostsUpdateOwn,
+	PermPostsDeleteOwn,
+	PermCommentsCreate,
+	PermCommentsUpdateOwn,
+	PermCommentsDeleteOwn,
+	PermReactionsCreate,
+}
+
+var editorGrants = append([]string{
+	PermPostsUpdateAny,
+	PermPostsPublishAny,
+	PermPostsReadUnpublishedAny,
+	PermTaxonomyManage,
+	PermCommentsDeleteAny,
+	PermCommentsModerate,
+}, authorGrants...)
+
+var adminGrants = append([]string{
+	PermPostsDeleteAny,
+	PermUsersReadPrivateAny,
+	PermUsersUpdateAny,
+	PermUsersDeleteAny,
+	PermUsersManage,
+}, editorGrants...)
+
+// DefaultGrants maps every role to the permissions it holds.
+var DefaultGrants = map[models.Role][]string{
+	models.RoleAuthor: authorGrants,
+	models.RoleEditor: editorGrants,
+	models.RoleAdmin:  adminGrants,
+}
+
+type policy struct {
+	grants map[models.Role]map[string]struct{}
+}
+
+func NewPolicy(grants map[models.Role][]string) *policy {
+	p := &policy{grants: make(map[models.Role]map[string]struct{}, len(grants))}
+	for role, permissions := range grants {
+		p.grants[role] = make(map[string]struct{}, len(permissions))
+		for _, permission := range permissions {
+			p.grants[role][permission] = struct{}{}
+		}
+	}
+
+	return p
+}
+
+// Can reports whether principal holds permission through its role.
+func (p policy) Can(principal models.Principal, permission string) bool {
+	_, ok := p.grants[principal.Role][permission]
+	return ok
+}
+
+// CanOnOwned reports whether principal may perform the action guarded by the
+// own/any permission pair on a resource, owned telling whether it belongs to
+// principal.
+func (p policy) CanOnOwned(principal models.Principal, ownPermission, anyPermission string, owned bool) bool {
+	if owned && p.Can(principal, ownPermission) {
+		return true
+	}
+
+	return p.Can(principal, anyPermission)
+}
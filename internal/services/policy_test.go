@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_policy_Can(t *testing.T) {
+	p := NewPolicy(DefaultGrants)
+
+	tests := []struct {
+		role       models.Role
+		permission string
+		want       bool
+	}{
+		{role: models.RoleAuthor, permission: PermPostsCreate, want: true},
+		{role: models.RoleAuthor, permission: PermPostsUpdateOwn, want: true},
+		{role: models.RoleAuthor, permission: PermPostsUpdateAny, want: false},
+		{role: models.RoleAuthor, permission: PermPostsPublishAny, want: false},
+		{role: models.RoleEditor, permission: PermPostsUpdateAny, want: true},
+		{role: models.RoleEditor, permission: PermPostsPublishAny, want: true},
+		{role: models.RoleEditor, permission: PermPostsDeleteAny, want: false},
+		{role: models.RoleAuthor, permission: PermTaxonomyManage, want: false},
+		{role: models.RoleEditor, permission: PermTaxonomyManage, want: true},
+		{role: models.RoleEditor, permission: PermUsersManage, want: false},
+		{role: models.RoleAuthor, permission: PermCommentsCreate, want: true},
+		{role: models.RoleAuthor, permission: PermCommentsDeleteAny, want: false},
+		{role: models.RoleEditor, permission: PermCommentsDeleteAny, want: true},
+		{role: models.RoleAuthor, permission: PermCommentsModerate, want: false},
+		{role: models.RoleEditor, permission: PermCommentsModerate, want: true},
+		{role: models.RoleAdmin, permission: PermPostsDeleteAny, want: true},
+		{role: models.RoleEditor, permission: PermUsersReadPrivateAny, want: false},
+		{role: models.RoleAdmin, permission: PermUsersManage, want: true},
+		{role: models.RoleAdmin, permission: PermUsersReadPrivateAny, want: true},
+		{role: models.Role(""), permission: PermPostsCreate, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+tt.permission, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Can(models.Principal{Role: tt.role}, tt.permission))
+		})
+	}
+}
+
+func Test_policy_CanOnOwned(t *testing.T) {
+	p := NewPolicy(DefaultGrants)
+
+	author := models.Principal{Role: models.RoleAuthor}
+	editor := models.Principal{Role: models.RoleEditor}
+
+	assert.True(t, p.CanOnOwned(author, PermPostsUpdateOwn, PermPostsUpdateAny, true))
+	assert.False(t, p.CanOnOwned(author, PermPostsUpdateOwn, PermPostsUpdateAny, false))
+	assert.True(t, p.CanOnOwned(editor, PermPostsUpdateOwn, PermPostsUpdateAny, false))
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+)
+
+// ReactToPost makes kind the reaction of principal to the post, replacing
+// the one they had. Only published posts take reactions.
+func (s postsService) ReactToPost(ctx context.Context, principal models.Principal, id uuid.UUID, kind models.ReactionKind) error {
+	if !kind.IsValid() {
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{"kind": "is invalid"}, "invalid reaction kind %q", kind)
+	}
+
+	post, err := s.FindPostById(ctx, principal, id)
+	if err != nil {
+		return err
+	}
+
+	if !s.policy.Can(principal, PermReactionsCreate) {
+		return domain.ErrForbidden
+	}
+
+	if post.Status != models.PostStatusPublished {
+		return domain.Errorf(domain.ErrConflict, "cannot react to a %s post", post.Status)
+	}
+
+	return s.repo.SetPostReaction(ctx, id, principal.UserId, kind)
+}
+
+// RemovePostReaction takes back the reaction of principal to the post, which
+// is reported as not found unless it is of the given kind.
+func (s postsService) RemovePostReaction(ctx context.Context, principal models.Principal, id uuid.UUID, kind models.ReactionKind) error {
+	if !kind.IsValid() {
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{"kind": "is invalid"}, "invalid reaction kind %q", kind)
+	}
+
+	_, err := s.FindPostById(ctx, principal, id)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeletePostReaction(ctx, id, principal.UserId, kind)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_postsService_ReactToPost(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Principal
+		post      models.Post
+		kind      models.ReactionKind
+		react     bool
+		wantErr   error
+	}{
+		{name: "Should react to published posts", principal: bobPrincipal, post: alicePost, kind: models.ReactionKindLike, react: true},
+		{name: "Should reject unknown kinds", principal: bobPrincipal, post: alicePost, kind: "meh", wantErr: domain.ErrValidation},
+		{name: "Should forbid anonymous callers", post: alicePost, kind: models.ReactionKindLike, wantErr: domain.ErrForbidden},
+		{name: "Should refuse reactions to unpublished posts", principal: alicePrincipal, post: aliceDraft, kind: models.ReactionKindLike, wantErr: domain.ErrConflict},
+		{name: "Should hide drafts from other users", principal: bobPrincipal, post: aliceDraft, kind: models.ReactionKindLike, wantErr: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			if tt.kind.IsValid() {
+				r.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+			}
+			if tt.react {
+				r.On("SetPostReaction", context.TODO(), tt.post.Id, tt.principal.UserId, tt.kind).Return(nil)
+			}
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			err := s.ReactToPost(context.TODO(), tt.principal, tt.post.Id, tt.kind)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_postsService_RemovePostReaction(t *testing.T) {
+	r := NewMockPostsRepository(t)
+	r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+	r.On("DeletePostReaction", context.TODO(), alicePost.Id, bobPrincipal.UserId, models.ReactionKindLove).Return(domain.ErrNotFound)
+
+	s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+	err := s.RemovePostReaction(context.TODO(), bobPrincipal, alicePost.Id, models.ReactionKindLove)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/diff"
+	"github.com/google/uuid"
+)
+
+// FindPostRevisions lists the revisions of the post, newest first, to
+// principal, who must be allowed to update the post.
+func (s postsService) FindPostRevisions(ctx context.Context, principal models.Principal, id uuid.UUID) ([]models.PostRevision, error) {
+	_, err := s.findEditablePost(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindPostRevisions(ctx, id)
+}
+
+// DiffPostRevisions returns the unified diff turning revision against of the
+// post into revision rev. Revision 0 stands for the empty post, so that the
+// first revision can be diffed as well.
+func (s postsService) DiffPostRevisions(ctx context.Context, principal models.Principal, id uuid.UUID, rev, against int) (string, error) {
+	_, err := s.findEditablePost(ctx, principal, id)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := s.repo.FindPostRevision(ctx, id, rev)
+	if err != nil {
+		return "", err
+	}
+
+	from := models.PostRevision{PostId: id}
+	if against != 0 {
+		from, err = s.repo.FindPostRevision(ctx, id, against)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return diff.Unified(revisionName(from), revisionName(to), revisionText(from), revisionText(to)), nil
+}
+
+// RestorePostRevision brings the text of revision rev back into the post,
+// which records it as a new revision. The slug follows the restored title.
+func (s postsService) RestorePostRevision(ctx context.Context, principal models.Principal, id uuid.UUID, rev int) error {
+	post, err := s.findEditablePost(ctx, principal, id)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.repo.FindPostRevision(ctx, id, rev)
+	if err != nil {
+		return err
+	}
+
+	if revision.Title != post.Title {
+		post.Slug, err = s.uniqueSlug(ctx, post.AuthorId, revision.Title, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	post.Title = revision.Title
+	post.Extract = revision.Extract
+	post.Content = revision.Content
+
+	err = render(&post, "")
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdatePostByIdAndAuthorId(ctx, id, post.AuthorId, post)
+}
+
+// findEditablePost returns the post if principal may update it. Posts that
+// principal cannot even read are reported as not found.
+func (s postsService) findEditablePost(ctx context.Context, principal models.Principal, id uuid.UUID) (models.Post, error) {
+	post, err := s.repo.FindPostById(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	if !s.canRead(principal, post) {
+		return models.Post{}, domain.ErrNotFound
+	}
+
+	owned := post.AuthorId == principal.UserId
+	if !s.policy.CanOnOwned(principal, PermPostsUpdateOwn, PermPostsUpdateAny, owned) {
+		return models.Post{}, domain.ErrForbidden
+	}
+
+	return post, nil
+}
+
+func revisionName(revision models.PostRevision) string {
+	if revision.Revision == 0 {
+		return "/dev/null"
+	}
+
+	return fmt.Sprintf("revision %d", revision.Revision)
+}
+
+// revisionText lays the fields of a revision out as a single document, so
+// that one diff covers all of them.
+func revisionText(revision models.PostRevision) string {
+	if revision.Revision == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Title: %s\nExtract: %s\n\n%s\n", revision.Title, revision.Extract, revision.Content)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	aliceFirstRevision = models.PostRevision{
+		PostId:    alicePost.Id,
+		Revision:  1,
+		Title:     "My Post",
+		Extract:   alicePost.Extract,
+		Content:   "First line.\nSecond line.",
+		CreatedAt: commonTime,
+	}
+	aliceSecondRevision = models.PostRevision{
+		PostId:    alicePost.Id,
+		Revision:  2,
+		Title:     alicePost.Title,
+		Extract:   alicePost.Extract,
+		Content:   alicePost.Content,
+		CreatedAt: commonTime,
+	}
+)
+
+func Test_postsService_FindPostRevisions(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Principal
+		post      models.Post
+		wantErr   error
+	}{
+		{
+			name:      "Should list the revisions to the author",
+			principal: alicePrincipal,
+			post:      alicePost,
+		},
+		{
+			name:      "Should list the revisions to editors",
+			principal: editorPrincipal,
+			post:      alicePost,
+		},
+		{
+			name:      "Should forbid other authors",
+			principal: bobPrincipal,
+			post:      alicePost,
+			wantErr:   domain.ErrForbidden,
+		},
+		{
+			name:      "Should hide drafts of other authors",
+			principal: bobPrincipal,
+			post:      aliceDraft,
+			wantErr:   domain.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			r.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+			if tt.wantErr == nil {
+				r.On("FindPostRevisions", context.TODO(), tt.post.Id).Return([]models.PostRevision{aliceSecondRevision, aliceFirstRevision}, nil)
+			}
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, err := s.FindPostRevisions(context.TODO(), tt.principal, tt.post.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []models.PostRevision{aliceSecondRevision, aliceFirstRevision}, got)
+		})
+	}
+}
+
+func Test_postsService_DiffPostRevisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		against int
+		setup   func(r *MockPostsRepository)
+		want    string
+	}{
+		{
+			name:    "Should diff two revisions",
+			against: 1,
+			setup: func(r *MockPostsRepository) {
+				r.On("FindPostRevision", context.TODO(), alicePost.Id, 1).Return(aliceFirstRevision, nil)
+			},
+			want: "--- revision 1\n+++ revision 2\n" +
+				"@@ -1,5 +1,4 @@\n" +
+				"-Title: My Post\n+Title: My First Post\n" +
+				" Extract: This is my first post extract.\n \n" +
+				"-First line.\n-Second line.\n+This is the full content of my first post.\n",
+		},
+		{
+			name:    "Should diff against an empty post",
+			against: 0,
+			setup:   func(r *MockPostsRepository) {},
+			want: "--- /dev/null\n+++ revision 2\n" +
+				"@@ -0,0 +1,4 @@\n" +
+				"+Title: My First Post\n+Extract: This is my first post extract.\n+\n" +
+				"+This is the full content of my first post.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+			r.On("FindPostRevision", context.TODO(), alicePost.Id, 2).Return(aliceSecondRevision, nil)
+			tt.setup(r)
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, err := s.DiffPostRevisions(context.TODO(), alicePrincipal, alicePost.Id, 2, tt.against)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_postsService_RestorePostRevision(t *testing.T) {
+	r := NewMockPostsRepository(t)
+	r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+	r.On("FindPostRevision", context.TODO(), alicePost.Id, 1).Return(aliceFirstRevision, nil)
+	r.On("FindTakenSlugs", context.TODO(), alicePost.AuthorId, "my-post", alicePost.Id).Return([]string{}, nil)
+
+	restored := alicePost
+	restored.Title = aliceFirstRevision.Title
+	restored.Slug = "my-post"
+	restored.Content = aliceFirstRevision.Content
+	restored.ContentHTML = "<p>First line.\nSecond line.</p>\n"
+	restored.ContentText = "First line. Second line."
+	restored.WordCount = 4
+	r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePost.AuthorId, restored).Return(nil)
+
+	s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+	err := s.RestorePostRevision(context.TODO(), alicePrincipal, alicePost.Id, 1)
+	assert.NoError(t, err)
+}
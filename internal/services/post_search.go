@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/search"
+)
+
+// SearchPosts returns the posts matching q that principal may list, as
+// FindAllPosts would. q is made of words, "quoted phrases" and prefixes
+// such as gener*. When no post contains its words, posts whose titles
+// resemble q are returned instead, so that typos still find something.
+func (s postsService) SearchPosts(ctx context.Context, principal models.Principal, q string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error) {
+	query := search.Parse(q)
+	if query.IsEmpty() {
+		return nil, domain.FieldsErrorf(domain.ErrValidation, map[string]string{"q": "must contain a word"}, "the search query must contain a word")
+	}
+
+	filter = s.visibleTo(principal, filter)
+
+	results, err := s.repo.SearchPosts(ctx, query.TSQuery(), limit, offset, filter)
+	if err != nil || len(results) > 0 {
+		return results, err
+	}
+
+	// Past the first page an empty page does not tell whether the query
+	// matched at all, and the fallback must only serve queries that did not.
+	if offset > 0 {
+		first, err := s.repo.SearchPosts(ctx, query.TSQuery(), 1, 0, filter)
+		if err != nil || len(first) > 0 {
+			return results, err
+		}
+	}
+
+	return s.repo.FindPostsBySimilarTitle(ctx, query.String(), limit, offset, filter)
+}
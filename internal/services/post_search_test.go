@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_postsService_SearchPosts(t *testing.T) {
+	published := models.PostFilter{Statuses: []models.PostStatus{models.PostStatusPublished}}
+	fullText := []models.PostSearchResult{{Post: alicePost, Rank: 0.5, Snippet: "my <mark>post</mark>", Match: models.PostMatchFullText}}
+	similar := []models.PostSearchResult{{Post: alicePost, Rank: 0.7, Snippet: alicePost.Extract, Match: models.PostMatchSimilarTitle}}
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		q         string
+		offset    int
+		setup     func(r *MockPostsRepository)
+		want      []models.PostSearchResult
+		wantErr   error
+	}{
+		{
+			name:      "Should search published posts",
+			principal: bobPrincipal,
+			q:         `"my post" gener*`,
+			setup: func(r *MockPostsRepository) {
+				r.On("SearchPosts", context.TODO(), "'my' <-> 'post' & 'gener':*", 10, 0, published).Return(fullText, nil)
+			},
+			want: fullText,
+		},
+		{
+			name:      "Should search every post for editors",
+			principal: editorPrincipal,
+			q:         "post",
+			setup: func(r *MockPostsRepository) {
+				r.On("SearchPosts", context.TODO(), "'post'", 10, 0, models.PostFilter{}).Return(fullText, nil)
+			},
+			want: fullText,
+		},
+		{
+			name:      "Should fall back to similar titles",
+			principal: bobPrincipal,
+			q:         "My Frist Post",
+			setup: func(r *MockPostsRepository) {
+				r.On("SearchPosts", context.TODO(), "'my' & 'frist' & 'post'", 10, 0, published).Return([]models.PostSearchResult{}, nil)
+				r.On("FindPostsBySimilarTitle", context.TODO(), "my frist post", 10, 0, published).Return(similar, nil)
+			},
+			want: similar,
+		},
+		{
+			name:      "Should fall back on later pages of unmatched queries",
+			principal: bobPrincipal,
+			q:         "frist",
+			offset:    10,
+			setup: func(r *MockPostsRepository) {
+				r.On("SearchPosts", context.TODO(), "'frist'", 10, 10, published).Return([]models.PostSearchResult{}, nil)
+				r.On("SearchPosts", context.TODO(), "'frist'", 1, 0, published).Return([]models.PostSearchResult{}, nil)
+				r.On("FindPostsBySimilarTitle", context.TODO(), "frist", 10, 10, published).Return(similar, nil)
+			},
+			want: similar,
+		},
+		{
+			name:      "Should not fall back past the last page of matched queries",
+			principal: bobPrincipal,
+			q:         "post",
+			offset:    10,
+			setup: func(r *MockPostsRepository) {
+				r.On("SearchPosts", context.TODO(), "'post'", 10, 10, published).Return([]models.PostSearchResult{}, nil)
+				r.On("SearchPosts", context.TODO(), "'post'", 1, 0, published).Return(fullText, nil)
+			},
+			want: []models.PostSearchResult{},
+		},
+		{
+			name:      "Should reject queries without words",
+			principal: bobPrincipal,
+			q:         `"" *`,
+			setup:     func(r *MockPostsRepository) {},
+			wantErr:   domain.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			tt.setup(r)
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, err := s.SearchPosts(context.TODO(), tt.principal, tt.q, 10, tt.offset, models.PostFilter{})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,58 +2,334 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/slug"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 )
 
 type PostsRepository interface {
 	CreatePost(ctx context.Context, post models.Post) (uuid.UUID, error)
-	FindAllPosts(ctx context.Context, limit, offset int, authorId uuid.UUID) ([]models.Post, error)
-	FindPostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID) (models.Post, error)
+	FindAllPosts(ctx context.Context, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, error)
+	CountPosts(ctx context.Context, filter models.PostFilter) (int, error)
+	FindPostById(ctx context.Context, id uuid.UUID) (models.Post, error)
+	FindPostBySlug(ctx context.Context, username, slug string) (models.Post, error)
+	FindPostByOldSlug(ctx context.Context, username, slug string) (models.Post, error)
+	FindTakenSlugs(ctx context.Context, authorId uuid.UUID, base string, excludeId uuid.UUID) ([]string, error)
 	UpdatePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, post models.Post) error
-	DeletePostById(ctx context.Context, id uuid.UUID) error
+	UpdatePostStatusById(ctx context.Context, id uuid.UUID, from models.PostStatus, post models.Post) error
+	DeletePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, version *time.Time) error
+	FindPostRevisions(ctx context.Context, postId uuid.UUID) ([]models.PostRevision, error)
+	FindPostRevision(ctx context.Context, postId uuid.UUID, revision int) (models.PostRevision, error)
+	FindUnrenderedPosts(ctx context.Context, limit int) ([]models.Post, error)
+	UpdatePostRenderingById(ctx context.Context, id uuid.UUID, post models.Post) error
+	SearchPosts(ctx context.Context, tsQuery string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error)
+	FindPostsBySimilarTitle(ctx context.Context, text string, limit, offset int, filter models.PostFilter) ([]models.PostSearchResult, error)
+	SetPostReaction(ctx context.Context, postId, userId uuid.UUID, kind models.ReactionKind) error
+	DeletePostReaction(ctx context.Context, postId, userId uuid.UUID, kind models.ReactionKind) error
 }
 
 type postsService struct {
-	repo PostsRepository
+	repo         PostsRepository
+	policy       *policy
+	timeProvider utils.TimeProvider
 }
 
-func NewPostsService(repo PostsRepository) *postsService {
-	return &postsService{repo: repo}
+func NewPostsService(repo PostsRepository, policy *policy, timeProvider utils.TimeProvider) *postsService {
+	return &postsService{repo: repo, policy: policy, timeProvider: timeProvider}
 }
 
+// CreatePost stores post as a draft, whatever status it came with, under a
+// slug derived from its title.
 func (s postsService) CreatePost(ctx context.Context, post models.Post) (uuid.UUID, error) {
+	post.Status = models.PostStatusDraft
+	post.PublishedAt = nil
+	post.ScheduledAt = nil
+
+	var err error
+	post.Slug, err = s.uniqueSlug(ctx, post.AuthorId, post.Title, uuid.Nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = render(&post, "")
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	return s.repo.CreatePost(ctx, post)
 }
 
-func (s postsService) FindAllPosts(ctx context.Context, limit, offset int, authorId uuid.UUID) ([]models.Post, error) {
-	return s.repo.FindAllPosts(ctx, limit, offset, authorId)
+// FindAllPosts lists the posts matching filter, loading what projection asks
+// for, whose statuses are decided here: only published posts are listed
+// unless principal is the author the listing is narrowed down to or may read
+// unpublished posts.
+func (s postsService) FindAllPosts(ctx context.Context, principal models.Principal, page models.Page, filter models.PostFilter, projection models.PostProjection) ([]models.Post, models.PageInfo, error) {
+	filter = s.visibleTo(principal, filter)
+
+	return fetchPage(page, func(page models.Page) ([]models.Post, error) {
+		return s.repo.FindAllPosts(ctx, page, filter, projection)
+	}, func() (int, error) {
+		return s.repo.CountPosts(ctx, filter)
+	})
 }
 
-func (s postsService) FindPostById(ctx context.Context, id, authorId uuid.UUID) (models.Post, error) {
-	return s.repo.FindPostByIdAndAuthorId(ctx, id, authorId)
+// visibleTo narrows filter down to the posts principal may list.
+func (s postsService) visibleTo(principal models.Principal, filter models.PostFilter) models.PostFilter {
+	filter.Statuses = nil
+
+	owned := filter.AuthorId != uuid.Nil && filter.AuthorId == principal.UserId
+	if !owned && !s.policy.Can(principal, PermPostsReadUnpublishedAny) {
+		filter.Statuses = []models.PostStatus{models.PostStatusPublished}
+	}
+
+	return filter
 }
 
-func (s postsService) UpdatePostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID, newPost models.Post) error {
-	post, err := s.repo.FindPostByIdAndAuthorId(ctx, id, newPost.AuthorId)
+// FindPostById returns the post unless it is unpublished and principal is
+// neither its author nor allowed to read unpublished posts, in which case it
+// is reported as not found.
+func (s postsService) FindPostById(ctx context.Context, principal models.Principal, id uuid.UUID) (models.Post, error) {
+	post, err := s.repo.FindPostById(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	if !s.canRead(principal, post) {
+		return models.Post{}, domain.ErrNotFound
+	}
+
+	return post, nil
+}
+
+// FindPostBySlug returns the post of the user with the given username that
+// is reachable through postSlug. moved reports that postSlug is a former slug of the
+// post, whose current one clients should be redirected to.
+func (s postsService) FindPostBySlug(ctx context.Context, principal models.Principal, username, postSlug string) (post models.Post, moved bool, err error) {
+	post, err = s.repo.FindPostBySlug(ctx, username, postSlug)
+	if errors.Is(err, domain.ErrNotFound) {
+		moved = true
+		post, err = s.repo.FindPostByOldSlug(ctx, username, postSlug)
+	}
+	if err != nil {
+		return models.Post{}, false, err
+	}
+
+	if !s.canRead(principal, post) {
+		return models.Post{}, false, domain.ErrNotFound
+	}
+
+	return post, moved, nil
+}
+
+func (s postsService) canRead(principal models.Principal, post models.Post) bool {
+	return canReadPost(s.policy, principal, post)
+}
+
+// canReadPost reports whether principal may see post, which is the case of
+// published posts and of unpublished ones to their authors and to whoever
+// may read unpublished posts.
+func canReadPost(policy *policy, principal models.Principal, post models.Post) bool {
+	return post.Status == models.PostStatusPublished ||
+		post.AuthorId == principal.UserId ||
+		policy.Can(principal, PermPostsReadUnpublishedAny)
+}
+
+// UpdatePostById patches the post on behalf of principal, who must be its
+// author or be allowed to update any post. When version is given the post
+// must still have been last updated at that time. A category id of uuid.Nil
+// takes the post out of its category. The repository repeats the
+// author check and only applies the patch to the post as it was read here, so
+// the post cannot change hands or be changed between the read and the write.
+func (s postsService) UpdatePostById(ctx context.Context, principal models.Principal, id uuid.UUID, newPost models.Post, version *time.Time) error {
+	post, err := s.repo.FindPostById(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	owned := post.AuthorId == principal.UserId
+	if !s.policy.CanOnOwned(principal, PermPostsUpdateOwn, PermPostsUpdateAny, owned) {
+		return domain.ErrForbidden
+	}
+
+	err = checkVersion(post.UpdatedAt, version)
+	if err != nil {
+		return err
+	}
+
+	// The author and status of a post never change through an update.
+	newPost.AuthorId = uuid.Nil
+	newPost.Status = ""
+	newPost.PublishedAt = nil
+	newPost.ScheduledAt = nil
+
+	oldTitle, oldContent := post.Title, post.Content
+
 	err = utils.PatchStruct(&post, newPost)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.UpdatePostByIdAndAuthorId(ctx, id, authorId, post)
+	if post.CategoryId != nil && *post.CategoryId == uuid.Nil {
+		post.CategoryId = nil
+	}
+
+	// An explicit slug wins, otherwise the slug follows the title.
+	switch {
+	case newPost.Slug != "":
+		post.Slug, err = s.uniqueSlug(ctx, post.AuthorId, newPost.Slug, id)
+	case post.Title != oldTitle:
+		post.Slug, err = s.uniqueSlug(ctx, post.AuthorId, post.Title, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = render(&post, oldContent)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdatePostByIdAndAuthorId(ctx, id, post.AuthorId, post)
+	return versionError(err, version)
 }
 
-func (s postsService) FindPostByIdAndAuthorId(ctx context.Context, id, authorId uuid.UUID) (models.Post, error) {
-	return models.Post{}, nil
+// DeletePostById deletes the post on behalf of principal, who must be its
+// author or be allowed to delete any post. When version is given the post
+// must still have been last updated at that time.
+func (s postsService) DeletePostById(ctx context.Context, principal models.Principal, id uuid.UUID, version *time.Time) error {
+	post, err := s.repo.FindPostById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	owned := post.AuthorId == principal.UserId
+	if !s.policy.CanOnOwned(principal, PermPostsDeleteOwn, PermPostsDeleteAny, owned) {
+		return domain.ErrForbidden
+	}
+
+	err = checkVersion(post.UpdatedAt, version)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeletePostByIdAndAuthorId(ctx, id, post.AuthorId, version)
+	return versionError(err, version)
+}
+
+// SubmitPostForReview moves a draft to review on behalf of principal, who must
+// be allowed to update the post.
+func (s postsService) SubmitPostForReview(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.transition(ctx, principal, id, models.PostStatusInReview, func(post models.Post) bool {
+		owned := post.AuthorId == principal.UserId
+		return s.policy.CanOnOwned(principal, PermPostsUpdateOwn, PermPostsUpdateAny, owned)
+	})
+}
+
+// PublishPost publishes a draft or a post under review, stamping its
+// publication time.
+func (s postsService) PublishPost(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.transition(ctx, principal, id, models.PostStatusPublished, s.canPublish(principal))
+}
+
+// UnpublishPost sends a published or archived post back to draft.
+func (s postsService) UnpublishPost(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.transition(ctx, principal, id, models.PostStatusDraft, s.canPublish(principal))
+}
+
+// ArchivePost retires a published post, hiding it from everyone but those
+// who may read unpublished posts.
+func (s postsService) ArchivePost(ctx context.Context, principal models.Principal, id uuid.UUID) error {
+	return s.transition(ctx, principal, id, models.PostStatusArchived, s.canPublish(principal))
+}
+
+// SchedulePost makes the scheduler publish the post at the given time, which
+// must lie in the future. The post keeps its current status until then.
+func (s postsService) SchedulePost(ctx context.Context, principal models.Principal, id uuid.UUID, at time.Time) error {
+	if !at.After(s.timeProvider.Now()) {
+		return domain.FieldsErrorf(domain.ErrValidation, map[string]string{"scheduled_at": "must be in the future"}, "scheduled_at must be in the future")
+	}
+
+	post, err := s.repo.FindPostById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !s.canRead(principal, post) {
+		return domain.ErrNotFound
+	}
+
+	if !s.policy.Can(principal, PermPostsPublishAny) {
+		return domain.ErrForbidden
+	}
+
+	if !post.Status.CanTransitionTo(models.PostStatusPublished) {
+		return domain.Errorf(domain.ErrConflict, "cannot schedule a %s post", post.Status)
+	}
+
+	post.ScheduledAt = utils.Ptr(at.UTC())
+
+	return s.repo.UpdatePostStatusById(ctx, id, post.Status, post)
+}
+
+func (s postsService) canPublish(principal models.Principal) func(models.Post) bool {
+	return func(models.Post) bool {
+		return s.policy.Can(principal, PermPostsPublishAny)
+	}
+}
+
+// transition moves the post to status to when allowed reports that principal
+// may do so and the workflow permits it.
+func (s postsService) transition(ctx context.Context, principal models.Principal, id uuid.UUID, to models.PostStatus, allowed func(models.Post) bool) error {
+	post, err := s.repo.FindPostById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !s.canRead(principal, post) {
+		return domain.ErrNotFound
+	}
+
+	if !allowed(post) {
+		return domain.ErrForbidden
+	}
+
+	from := post.Status
+	if !from.CanTransitionTo(to) {
+		return domain.Errorf(domain.ErrConflict, "cannot move a %s post to %s", from, to)
+	}
+
+	post.Status = to
+	switch to {
+	case models.PostStatusPublished:
+		post.PublishedAt = utils.Ptr(s.timeProvider.Now().UTC())
+	case models.PostStatusDraft:
+		post.PublishedAt = nil
+	}
+	// A schedule survives review but any other move replaces it.
+	if to != models.PostStatusInReview {
+		post.ScheduledAt = nil
+	}
+
+	return s.repo.UpdatePostStatusById(ctx, id, from, post)
 }
 
-func (s postsService) DeletePostById(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeletePostById(ctx, id)
+// uniqueSlug derives a slug from text that no other post of authorId uses or
+// used to use. excludeId is the post the slug is for, if it already exists.
+func (s postsService) uniqueSlug(ctx context.Context, authorId uuid.UUID, text string, excludeId uuid.UUID) (string, error) {
+	base := slug.Make(text)
+	if base == "" {
+		base = "post"
+	}
+
+	taken, err := s.repo.FindTakenSlugs(ctx, authorId, base, excludeId)
+	if err != nil {
+		return "", err
+	}
+
+	return slug.Unique(base, taken), nil
 }
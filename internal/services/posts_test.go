@@ -0,0 +1,700 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	alicePrincipal = models.Principal{
+		UserId:   uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Username: "alice_s",
+		Role:     models.RoleAuthor,
+	}
+	bobPrincipal = models.Principal{
+		UserId:   uuid.MustParse("b2ccc80d-606e-422f-a9e1-5fd7371163db"),
+		Username: "bobby_j",
+		Role:     models.RoleAuthor,
+	}
+	editorPrincipal = models.Principal{
+		UserId:   uuid.MustParse("2cdc1c8f-9985-4b6c-b007-038a5bef22b5"),
+		Username: "charlie_b",
+		Role:     models.RoleEditor,
+	}
+	adminPrincipal = models.Principal{
+		UserId:   uuid.MustParse("2cdc1c8f-9985-4b6c-b007-038a5bef22b5"),
+		Username: "charlie_b",
+		Role:     models.RoleAdmin,
+	}
+	alicePost = models.Post{
+		Id:             uuid.MustParse("91c1538a-518c-4b05-9a1e-180c561a70b3"),
+		Title:          "My First Post",
+		Slug:           "my-first-post",
+		Extract:        "This is my first post extract.",
+		Content:        "This is the full content of my first post.",
+		ContentHTML:    "<p>This is the full content of my first post.</p>\n",
+		ContentText:    "This is the full content of my first post.",
+		WordCount:      9,
+		ReadingMinutes: 1,
+		Toc:            []models.TocEntry{},
+		AuthorId:       alicePrincipal.UserId,
+		Status:         models.PostStatusPublished,
+		PublishedAt:    utils.Ptr(commonTime),
+		CreatedAt:      commonTime,
+		UpdatedAt:      commonTime,
+	}
+	aliceDraft = models.Post{
+		Id:             uuid.MustParse("4c09ea12-30ec-4fea-a667-15be9f13e476"),
+		Title:          "Another Day in the Life",
+		Slug:           "another-day-in-the-life",
+		Extract:        "A short story extract.",
+		Content:        "A longer text describing my second post.",
+		ContentHTML:    "<p>A longer text describing my second post.</p>\n",
+		ContentText:    "A longer text describing my second post.",
+		WordCount:      7,
+		ReadingMinutes: 1,
+		Toc:            []models.TocEntry{},
+		AuthorId:       alicePrincipal.UserId,
+		Status:         models.PostStatusDraft,
+		CreatedAt:      commonTime,
+		UpdatedAt:      commonTime,
+	}
+)
+
+func Test_postsService_CreatePost(t *testing.T) {
+	post := models.Post{Title: "Hello", Content: "World", AuthorId: alicePrincipal.UserId, Status: models.PostStatusPublished}
+
+	r := NewMockPostsRepository(t)
+	r.On("FindTakenSlugs", context.TODO(), alicePrincipal.UserId, "hello", uuid.Nil).Return([]string{"hello"}, nil)
+	r.On("CreatePost", context.TODO(), models.Post{
+		Title:          "Hello",
+		Slug:           "hello-2",
+		Extract:        "World",
+		Content:        "World",
+		ContentHTML:    "<p>World</p>\n",
+		ContentText:    "World",
+		WordCount:      1,
+		ReadingMinutes: 1,
+		Toc:            []models.TocEntry{},
+		AuthorId:       alicePrincipal.UserId,
+		Status:         models.PostStatusDraft,
+	}).Return(aliceDraft.Id, nil)
+
+	s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+	id, err := s.CreatePost(context.TODO(), post)
+	assert.NoError(t, err)
+	assert.Equal(t, aliceDraft.Id, id)
+}
+
+func Test_postsService_FindAllPosts(t *testing.T) {
+	published := []models.PostStatus{models.PostStatusPublished}
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		filter    models.PostFilter
+		want      models.PostFilter
+	}{
+		{
+			name:      "Should show authors all of their posts",
+			principal: alicePrincipal,
+			filter:    models.PostFilter{AuthorId: alicePrincipal.UserId},
+			want:      models.PostFilter{AuthorId: alicePrincipal.UserId},
+		},
+		{
+			name:      "Should only show published posts to other users",
+			principal: bobPrincipal,
+			filter:    models.PostFilter{AuthorId: alicePrincipal.UserId},
+			want:      models.PostFilter{AuthorId: alicePrincipal.UserId, Statuses: published},
+		},
+		{
+			name:   "Should only show published posts to anonymous callers",
+			filter: models.PostFilter{},
+			want:   models.PostFilter{Statuses: published},
+		},
+		{
+			name:   "Should ignore statuses asked for by the caller",
+			filter: models.PostFilter{Statuses: []models.PostStatus{models.PostStatusDraft}},
+			want:   models.PostFilter{Statuses: published},
+		},
+		{
+			name:   "Should keep the tag and category filters",
+			filter: models.PostFilter{Tag: "go", Category: "programming"},
+			want:   models.PostFilter{Tag: "go", Category: "programming", Statuses: published},
+		},
+		{
+			name:      "Should show editors every post",
+			principal: editorPrincipal,
+			filter:    models.PostFilter{},
+			want:      models.PostFilter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			r.On("FindAllPosts", context.TODO(), models.Page{Limit: 11}, tt.want, models.PostProjection{Author: true}).Return([]models.Post{alicePost}, nil)
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, _, err := s.FindAllPosts(context.TODO(), tt.principal, models.Page{Limit: 10}, tt.filter, models.PostProjection{Author: true})
+			assert.NoError(t, err)
+			assert.Equal(t, []models.Post{alicePost}, got)
+		})
+	}
+}
+
+func Test_postsService_FindPostById(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Principal
+		post      models.Post
+		wantErr   error
+	}{
+		{name: "Should show published posts to anyone", post: alicePost},
+		{name: "Should show drafts to their author", principal: alicePrincipal, post: aliceDraft},
+		{name: "Should show drafts to editors", principal: editorPrincipal, post: aliceDraft},
+		{name: "Should hide drafts from other users", principal: bobPrincipal, post: aliceDraft, wantErr: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			r.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, err := s.FindPostById(context.TODO(), tt.principal, tt.post.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.post, got)
+		})
+	}
+}
+
+func Test_postsService_transitions(t *testing.T) {
+	now := time.Date(2006, time.January, 3, 0, 0, 0, 0, time.UTC)
+
+	inReview := aliceDraft
+	inReview.Status = models.PostStatusInReview
+	inReview.ScheduledAt = utils.Ptr(now.Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		action    func(postsService, context.Context, models.Principal, uuid.UUID) error
+		principal models.Principal
+		post      models.Post
+		want      *models.Post
+		wantErr   error
+	}{
+		{
+			name:      "Should let authors submit their drafts for review",
+			action:    postsService.SubmitPostForReview,
+			principal: alicePrincipal,
+			post:      aliceDraft,
+			want: func() *models.Post {
+				p := aliceDraft
+				p.Status = models.PostStatusInReview
+				return &p
+			}(),
+		},
+		{
+			name:      "Should let editors publish posts under review",
+			action:    postsService.PublishPost,
+			principal: editorPrincipal,
+			post:      inReview,
+			want: func() *models.Post {
+				p := inReview
+				p.Status = models.PostStatusPublished
+				p.PublishedAt = &now
+				p.ScheduledAt = nil
+				return &p
+			}(),
+		},
+		{
+			name:      "Should forbid authors from publishing",
+			action:    postsService.PublishPost,
+			principal: alicePrincipal,
+			post:      inReview,
+			wantErr:   domain.ErrForbidden,
+		},
+		{
+			name:      "Should clear the publication time when unpublishing",
+			action:    postsService.UnpublishPost,
+			principal: editorPrincipal,
+			post:      alicePost,
+			want: func() *models.Post {
+				p := alicePost
+				p.Status = models.PostStatusDraft
+				p.PublishedAt = nil
+				return &p
+			}(),
+		},
+		{
+			name:      "Should reject transitions outside the workflow",
+			action:    postsService.ArchivePost,
+			principal: editorPrincipal,
+			post:      aliceDraft,
+			wantErr:   domain.ErrConflict,
+		},
+		{
+			name:      "Should hide drafts of other authors",
+			action:    postsService.SubmitPostForReview,
+			principal: bobPrincipal,
+			post:      aliceDraft,
+			wantErr:   domain.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			r.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+			if tt.want != nil {
+				r.On("UpdatePostStatusById", context.TODO(), tt.post.Id, tt.post.Status, *tt.want).Return(nil)
+			}
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{NowFunc: func() time.Time { return now }})
+
+			err := tt.action(*s, context.TODO(), tt.principal, tt.post.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_postsService_UpdatePostById(t *testing.T) {
+	type args struct {
+		principal models.Principal
+		newPost   models.Post
+		version   *time.Time
+	}
+	tests := []struct {
+		name    string
+		repo    *MockPostsRepository
+		args    args
+		wantErr error
+	}{
+		{
+			name: "Should let the author update the post",
+			repo: func() *MockPostsRepository {
+				updated := alicePost
+				updated.Title = "New Title"
+				updated.Slug = "new-title"
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("FindTakenSlugs", context.TODO(), alicePrincipal.UserId, "new-title", alicePost.Id).Return([]string{}, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, updated).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Title: "New Title"},
+			},
+		},
+		{
+			name: "Should not let the author reassign the post",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, alicePost).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{AuthorId: bobPrincipal.UserId},
+			},
+		},
+		{
+			name: "Should let editors update any post",
+			repo: func() *MockPostsRepository {
+				updated := alicePost
+				updated.Title = "Fixed Title"
+				updated.Slug = "fixed-title"
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("FindTakenSlugs", context.TODO(), alicePrincipal.UserId, "fixed-title", alicePost.Id).Return([]string{}, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, updated).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: editorPrincipal,
+				newPost:   models.Post{Title: "Fixed Title"},
+			},
+		},
+		{
+			name: "Should keep the slug when the title does not change",
+			repo: func() *MockPostsRepository {
+				updated := alicePost
+				updated.Content = "New content"
+				updated.ContentHTML = "<p>New content</p>\n"
+				updated.ContentText = "New content"
+				updated.WordCount = 2
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, updated).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Content: "New content"},
+			},
+		},
+		{
+			name: "Should prefer an explicit slug over the title",
+			repo: func() *MockPostsRepository {
+				updated := alicePost
+				updated.Title = "New Title"
+				updated.Slug = "first-2"
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("FindTakenSlugs", context.TODO(), alicePrincipal.UserId, "first", alicePost.Id).Return([]string{"first"}, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, updated).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Title: "New Title", Slug: "first"},
+			},
+		},
+		{
+			name: "Should update the version the client saw",
+			repo: func() *MockPostsRepository {
+				updated := alicePost
+				updated.Extract = "New extract"
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, updated).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Extract: "New extract"},
+				version:   utils.Ptr(commonTime),
+			},
+		},
+		{
+			name: "Should refuse to update a version the client did not see",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Extract: "New extract"},
+				version:   utils.Ptr(commonTime.Add(-time.Hour)),
+			},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+		{
+			name: "Should take the post out of its category",
+			repo: func() *MockPostsRepository {
+				categorized := alicePost
+				categorized.CategoryId = utils.Ptr(uuid.MustParse("a3c5e7f9-2b4d-4f6a-8c0e-1a3b5c7d9e01"))
+
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(categorized, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, alicePost).Return(nil)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{CategoryId: &uuid.Nil},
+			},
+		},
+		{
+			name: "Should report losing a race as a conflict without a version",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, mock.Anything).Return(domain.ErrModified)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Extract: "New extract"},
+			},
+			wantErr: domain.ErrConflict,
+		},
+		{
+			name: "Should report losing a race as a failed precondition with a version",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("UpdatePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, mock.Anything).Return(domain.ErrModified)
+				return r
+			}(),
+			args: args{
+				principal: alicePrincipal,
+				newPost:   models.Post{Extract: "New extract"},
+				version:   utils.Ptr(commonTime),
+			},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+		{
+			name: "Should forbid other users from updating the post",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				return r
+			}(),
+			args: args{
+				principal: bobPrincipal,
+				newPost:   models.Post{Title: "New Title"},
+			},
+			wantErr: domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPostsService(tt.repo, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			err := s.UpdatePostById(context.TODO(), tt.args.principal, alicePost.Id, tt.args.newPost, tt.args.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_postsService_DeletePostById(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      *MockPostsRepository
+		principal models.Principal
+		version   *time.Time
+		wantErr   error
+	}{
+		{
+			name: "Should let the author delete the post",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("DeletePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, (*time.Time)(nil)).Return(nil)
+				return r
+			}(),
+			principal: alicePrincipal,
+		},
+		{
+			name: "Should let admins delete any post",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("DeletePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, (*time.Time)(nil)).Return(nil)
+				return r
+			}(),
+			principal: adminPrincipal,
+		},
+		{
+			name: "Should delete the version the client saw",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				r.On("DeletePostByIdAndAuthorId", context.TODO(), alicePost.Id, alicePrincipal.UserId, utils.Ptr(commonTime)).Return(nil)
+				return r
+			}(),
+			principal: alicePrincipal,
+			version:   utils.Ptr(commonTime),
+		},
+		{
+			name: "Should refuse to delete a version the client did not see",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				return r
+			}(),
+			principal: alicePrincipal,
+			version:   utils.Ptr(commonTime.Add(-time.Hour)),
+			wantErr:   domain.ErrPreconditionFailed,
+		},
+		{
+			name: "Should forbid editors from deleting other authors' posts",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				return r
+			}(),
+			principal: editorPrincipal,
+			wantErr:   domain.ErrForbidden,
+		},
+		{
+			name: "Should forbid other users from deleting the post",
+			repo: func() *MockPostsRepository {
+				r := NewMockPostsRepository(t)
+				r.On("FindPostById", context.TODO(), alicePost.Id).Return(alicePost, nil)
+				return r
+			}(),
+			principal: bobPrincipal,
+			wantErr:   domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPostsService(tt.repo, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			err := s.DeletePostById(context.TODO(), tt.principal, alicePost.Id, tt.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_postsService_SchedulePost(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	at := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		post      models.Post
+		at        time.Time
+		schedule  bool
+		wantErr   error
+	}{
+		{
+			name:      "Should let editors schedule a draft",
+			principal: editorPrincipal,
+			post:      aliceDraft,
+			at:        at,
+			schedule:  true,
+		},
+		{
+			name:      "Should reject times in the past",
+			principal: editorPrincipal,
+			post:      aliceDraft,
+			at:        now.Add(-time.Minute),
+			wantErr:   domain.ErrValidation,
+		},
+		{
+			name:      "Should forbid authors from scheduling",
+			principal: alicePrincipal,
+			post:      aliceDraft,
+			at:        at,
+			wantErr:   domain.ErrForbidden,
+		},
+		{
+			name:      "Should not schedule published posts",
+			principal: editorPrincipal,
+			post:      alicePost,
+			at:        at,
+			wantErr:   domain.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			if tt.wantErr != domain.ErrValidation {
+				r.On("FindPostById", context.TODO(), tt.post.Id).Return(tt.post, nil)
+			}
+			if tt.schedule {
+				scheduled := tt.post
+				scheduled.ScheduledAt = &tt.at
+				r.On("UpdatePostStatusById", context.TODO(), tt.post.Id, tt.post.Status, scheduled).Return(nil)
+			}
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{NowFunc: func() time.Time { return now }})
+
+			err := s.SchedulePost(context.TODO(), tt.principal, tt.post.Id, tt.at)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_postsService_FindPostBySlug(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Principal
+		slug      string
+		repo      func(r *MockPostsRepository)
+		want      models.Post
+		wantMoved bool
+		wantErr   error
+	}{
+		{
+			name: "Should find a post by its current slug",
+			slug: "my-first-post",
+			repo: func(r *MockPostsRepository) {
+				r.On("FindPostBySlug", context.TODO(), "alice_s", "my-first-post").Return(alicePost, nil)
+			},
+			want: alicePost,
+		},
+		{
+			name: "Should report former slugs as moved",
+			slug: "first-post",
+			repo: func(r *MockPostsRepository) {
+				r.On("FindPostBySlug", context.TODO(), "alice_s", "first-post").Return(models.Post{}, domain.ErrNotFound)
+				r.On("FindPostByOldSlug", context.TODO(), "alice_s", "first-post").Return(alicePost, nil)
+			},
+			want:      alicePost,
+			wantMoved: true,
+		},
+		{
+			name:      "Should hide drafts from other users",
+			principal: bobPrincipal,
+			slug:      "another-day-in-the-life",
+			repo: func(r *MockPostsRepository) {
+				r.On("FindPostBySlug", context.TODO(), "alice_s", "another-day-in-the-life").Return(aliceDraft, nil)
+			},
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name: "Should fail when no post ever had the slug",
+			slug: "missing",
+			repo: func(r *MockPostsRepository) {
+				r.On("FindPostBySlug", context.TODO(), "alice_s", "missing").Return(models.Post{}, domain.ErrNotFound)
+				r.On("FindPostByOldSlug", context.TODO(), "alice_s", "missing").Return(models.Post{}, domain.ErrNotFound)
+			},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockPostsRepository(t)
+			tt.repo(r)
+
+			s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+			got, moved, err := s.FindPostBySlug(context.TODO(), tt.principal, "alice_s", tt.slug)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMoved, moved)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/markdown"
+)
+
+const renderBatchSize = 100
+
+const (
+	// wordsPerMinute is the pace reading times are estimated at.
+	wordsPerMinute = 200
+	// maxExtractChars bounds generated extracts, which end on a whole word.
+	maxExtractChars = 200
+)
+
+// render fills in the fields of post derived from its Markdown content. The
+// extract is generated as well when it is empty or was itself generated
+// from previousContent, so that it keeps following the content.
+func render(post *models.Post, previousContent string) error {
+	doc, err := markdown.Render(post.Content)
+	if err != nil {
+		return err
+	}
+
+	if post.Extract == "" {
+		post.Extract = extract(doc.Text)
+	} else if previousContent != "" {
+		previous, err := markdown.Render(previousContent)
+		if err != nil {
+			return err
+		}
+
+		if post.Extract == extract(previous.Text) {
+			post.Extract = extract(doc.Text)
+		}
+	}
+
+	words := len(strings.Fields(doc.Text))
+	toc := make([]models.TocEntry, len(doc.Headings))
+	for i, heading := range doc.Headings {
+		words += len(strings.Fields(heading.Text))
+		toc[i] = models.TocEntry{Level: heading.Level, Id: heading.Id, Text: heading.Text}
+	}
+
+	post.ContentHTML = doc.HTML
+	post.ContentText = doc.PlainText
+	post.WordCount = words
+	post.ReadingMinutes = (words + wordsPerMinute - 1) / wordsPerMinute
+	post.Toc = toc
+
+	return nil
+}
+
+// extract returns the first paragraph of text, shortened to at most
+// maxExtractChars on a word boundary.
+func extract(text string) string {
+	paragraph, _, _ := strings.Cut(text, "\n\n")
+	if utf8.RuneCountInString(paragraph) <= maxExtractChars {
+		return paragraph
+	}
+
+	var sb strings.Builder
+	chars := 0
+	for _, word := range strings.Fields(paragraph) {
+		// Leave room for the separating space and the ellipsis.
+		n := utf8.RuneCountInString(word)
+		if chars+n+2 > maxExtractChars {
+			break
+		}
+		if chars > 0 {
+			sb.WriteByte(' ')
+			chars++
+		}
+		sb.WriteString(word)
+		chars += n
+	}
+
+	return sb.String() + "…"
+}
+
+// RenderPendingPosts renders the content of every post stored without it,
+// in batches, and returns how many posts it rendered. Posts edited or deleted
+// while being rendered are skipped: edits render the content themselves.
+func (s postsService) RenderPendingPosts(ctx context.Context) (int, error) {
+	rendered := 0
+	for {
+		posts, err := s.repo.FindUnrenderedPosts(ctx, renderBatchSize)
+		if err != nil {
+			return rendered, err
+		}
+
+		for _, post := range posts {
+			err = render(&post, "")
+			if err != nil {
+				return rendered, err
+			}
+
+			err = s.repo.UpdatePostRenderingById(ctx, post.Id, post)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return rendered, err
+			}
+			rendered++
+		}
+
+		if len(posts) < renderBatchSize {
+			return rendered, nil
+		}
+	}
+}
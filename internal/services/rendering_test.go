@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_postsService_RenderPendingPosts(t *testing.T) {
+	unrendered := alicePost
+	unrendered.Content = "# Title\n\nSome *text*."
+	unrendered.ContentHTML = ""
+
+	rendered := unrendered
+	rendered.ContentHTML = "<h1 id=\"title\"><a href=\"#title\" class=\"anchor\" rel=\"nofollow\">#</a>Title</h1>\n<p>Some <em>text</em>.</p>\n"
+	rendered.ContentText = "Title\n\nSome text."
+	rendered.WordCount = 3
+	rendered.ReadingMinutes = 1
+	rendered.Toc = []models.TocEntry{{Level: 1, Id: "title", Text: "Title"}}
+
+	r := NewMockPostsRepository(t)
+	r.On("FindUnrenderedPosts", context.TODO(), renderBatchSize).Return([]models.Post{unrendered}, nil)
+	r.On("UpdatePostRenderingById", context.TODO(), unrendered.Id, rendered).Return(nil)
+
+	s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+	n, err := s.RenderPendingPosts(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func Test_postsService_RenderPendingPosts_skipsChangedPosts(t *testing.T) {
+	edited := alicePost
+	edited.ContentHTML = ""
+	deleted := aliceDraft
+	deleted.ContentHTML = ""
+
+	r := NewMockPostsRepository(t)
+	r.On("FindUnrenderedPosts", context.TODO(), renderBatchSize).Return([]models.Post{edited, deleted}, nil)
+	r.On("UpdatePostRenderingById", context.TODO(), edited.Id, mock.Anything).Return(domain.ErrNotFound)
+	r.On("UpdatePostRenderingById", context.TODO(), deleted.Id, mock.Anything).Return(domain.ErrNotFound)
+
+	s := NewPostsService(r, NewPolicy(DefaultGrants), utils.MockClock{})
+
+	n, err := s.RenderPendingPosts(context.TODO())
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func Test_render(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+
+	tests := []struct {
+		name            string
+		post            models.Post
+		previousContent string
+		want            models.Post
+	}{
+		{
+			name: "Should generate a missing extract from the first paragraph",
+			post: models.Post{Content: "## Intro\n\nFirst paragraph.\n\nSecond paragraph."},
+			want: models.Post{
+				Extract:        "First paragraph.",
+				WordCount:      5,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{{Level: 2, Id: "intro", Text: "Intro"}},
+			},
+		},
+		{
+			name: "Should shorten long extracts on a word boundary",
+			post: models.Post{Content: long},
+			want: models.Post{
+				Extract:        strings.Repeat("word ", 39) + "word…",
+				WordCount:      60,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{},
+			},
+		},
+		{
+			name: "Should keep a written extract",
+			post: models.Post{Extract: "Hand written.", Content: "New text."},
+			want: models.Post{
+				Extract:        "Hand written.",
+				WordCount:      2,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{},
+			},
+		},
+		{
+			name:            "Should regenerate an extract generated from the previous content",
+			post:            models.Post{Extract: "Old text.", Content: "New text."},
+			previousContent: "Old text.",
+			want: models.Post{
+				Extract:        "New text.",
+				WordCount:      2,
+				ReadingMinutes: 1,
+				Toc:            []models.TocEntry{},
+			},
+		},
+		{
+			name: "Should round reading times up",
+			post: models.Post{Extract: "Long.", Content: strings.Repeat("word ", 201)},
+			want: models.Post{
+				Extract:        "Long.",
+				WordCount:      201,
+				ReadingMinutes: 2,
+				Toc:            []models.TocEntry{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := tt.post
+			err := render(&post, tt.previousContent)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want.Extract, post.Extract)
+			assert.Equal(t, tt.want.WordCount, post.WordCount)
+			assert.Equal(t, tt.want.ReadingMinutes, post.ReadingMinutes)
+			assert.Equal(t, tt.want.Toc, post.Toc)
+			assert.NotEmpty(t, post.ContentHTML)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gera9/blog/pkg/utils"
+)
+
+const schedulerBatchSize = 100
+
+type ScheduledPostsRepository interface {
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+// postScheduler periodically publishes the posts whose scheduled time has
+// come. Several replicas may run it at once: the repository skips the posts
+// another replica is already publishing.
+type postScheduler struct {
+	repo     ScheduledPostsRepository
+	clock    utils.Clock
+	interval time.Duration
+}
+
+func NewPostScheduler(repo ScheduledPostsRepository, clock utils.Clock, interval time.Duration) *postScheduler {
+	return &postScheduler{repo: repo, clock: clock, interval: interval}
+}
+
+// Run publishes due posts every interval until ctx is done.
+func (s postScheduler) Run(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			published, err := s.PublishDuePosts(ctx)
+			if err != nil {
+				log.Printf("publishing scheduled posts: %v", err)
+			}
+			if published > 0 {
+				log.Printf("published %d scheduled posts", published)
+			}
+		}
+	}
+}
+
+// PublishDuePosts publishes every post scheduled up to now, in batches, and
+// returns how many were published.
+func (s postScheduler) PublishDuePosts(ctx context.Context) (int64, error) {
+	now := s.clock.Now()
+
+	var total int64
+	for {
+		published, err := s.repo.PublishDuePosts(ctx, now, schedulerBatchSize)
+		total += published
+		if err != nil {
+			return total, err
+		}
+
+		if published < schedulerBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_postScheduler_PublishDuePosts(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		repo    func(t *testing.T) *MockScheduledPostsRepository
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "Should publish the posts due at the current time",
+			repo: func(t *testing.T) *MockScheduledPostsRepository {
+				r := NewMockScheduledPostsRepository(t)
+				r.On("PublishDuePosts", context.TODO(), now, schedulerBatchSize).Return(int64(3), nil).Once()
+				return r
+			},
+			want: 3,
+		},
+		{
+			name: "Should keep going while batches are full",
+			repo: func(t *testing.T) *MockScheduledPostsRepository {
+				r := NewMockScheduledPostsRepository(t)
+				r.On("PublishDuePosts", context.TODO(), now, schedulerBatchSize).Return(int64(schedulerBatchSize), nil).Twice()
+				r.On("PublishDuePosts", context.TODO(), now, schedulerBatchSize).Return(int64(0), nil).Once()
+				return r
+			},
+			want: 2 * schedulerBatchSize,
+		},
+		{
+			name: "Should report repository failures",
+			repo: func(t *testing.T) *MockScheduledPostsRepository {
+				r := NewMockScheduledPostsRepository(t)
+				r.On("PublishDuePosts", context.TODO(), now, schedulerBatchSize).Return(int64(0), errors.New("connection refused")).Once()
+				return r
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPostScheduler(tt.repo(t), utils.MockClock{NowFunc: clock}, time.Minute)
+
+			got, err := s.PublishDuePosts(context.TODO())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_postScheduler_Run(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	ticks := make(chan time.Time)
+	clock := utils.MockClock{NowFunc: func() time.Time { return now }, Ticks: ticks}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan time.Time)
+	r := NewMockScheduledPostsRepository(t)
+	r.On("PublishDuePosts", ctx, mock.AnythingOfType("time.Time"), schedulerBatchSize).
+		Run(func(args mock.Arguments) { published <- args.Get(1).(time.Time) }).
+		Return(int64(1), nil)
+
+	done := make(chan struct{})
+	go func() {
+		NewPostScheduler(r, clock, time.Minute).Run(ctx)
+		close(done)
+	}()
+
+	for range 2 {
+		ticks <- now
+
+		select {
+		case got := <-published:
+			assert.Equal(t, now, got)
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not publish on tick")
+		}
+
+		now = now.Add(time.Minute)
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after its context was cancelled")
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// SpamScorer tells whether a comment about to be posted looks like spam.
+type SpamScorer interface {
+	ScoreComment(ctx context.Context, comment models.Comment) (models.SpamVerdict, error)
+}
+
+// SpamRule scores one sign of spam in a comment about to be posted, from 0
+// when it shows none up to 1 when it is enough to hold the comment on its
+// own.
+type SpamRule interface {
+	Name() string
+	Score(ctx context.Context, comment models.Comment) (float64, error)
+}
+
+// CommentHistory is what the rules looking at past comments need. The
+// comment being scored is left out of the counts by its id, which is nil for
+// comments not stored yet.
+type CommentHistory interface {
+	CountCommentsByAuthorSince(ctx context.Context, authorId, excludeId uuid.UUID, since time.Time) (int, error)
+	CountDuplicateComments(ctx context.Context, body string, excludeId uuid.UUID, since time.Time) (int, error)
+}
+
+type spamScorer struct {
+	threshold float64
+	rules     []SpamRule
+}
+
+// NewSpamScorer returns a scorer adding up the scores of rules and finding
+// spam from threshold on.
+func NewSpamScorer(threshold float64, rules ...SpamRule) *spamScorer {
+	return &spamScorer{threshold: threshold, rules: rules}
+}
+
+func (s spamScorer) ScoreComment(ctx context.Context, comment models.Comment) (models.SpamVerdict, error) {
+	verdict := models.SpamVerdict{Reasons: []string{}}
+	for _, rule := range s.rules {
+		score, err := rule.Score(ctx, comment)
+		if err != nil {
+			return models.SpamVerdict{}, err
+		}
+
+		if score > 0 {
+			verdict.Score += score
+			verdict.Reasons = append(verdict.Reasons, rule.Name())
+		}
+	}
+
+	verdict.Spam = verdict.Score >= s.threshold
+
+	return verdict, nil
+}
+
+var linkRx = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+type linkDensityRule struct {
+	maxDensity float64
+}
+
+// NewLinkDensityRule scores comments by their share of words that are links,
+// fully from maxDensity on.
+func NewLinkDensityRule(maxDensity float64) *linkDensityRule {
+	return &linkDensityRule{maxDensity: maxDensity}
+}
+
+func (r linkDensityRule) Name() string {
+	return "link_density"
+}
+
+func (r linkDensityRule) Score(_ context.Context, comment models.Comment) (float64, error) {
+	words := len(strings.Fields(comment.Body))
+	if words == 0 {
+		return 0, nil
+	}
+
+	density := float64(len(linkRx.FindAllString(comment.Body, -1))) / float64(words)
+
+	return min(1, density/r.maxDensity), nil
+}
+
+type blocklistRule struct {
+	words map[string]struct{}
+}
+
+// NewBlocklistRule flags comments containing any of words, whatever their
+// case. Blank words are ignored.
+func NewBlocklistRule(words []string) *blocklistRule {
+	r := &blocklistRule{words: make(map[string]struct{}, len(words))}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			r.words[word] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+func (r blocklistRule) Name() string {
+	return "blocklisted_words"
+}
+
+func (r blocklistRule) Score(_ context.Context, comment models.Comment) (float64, error) {
+	words := strings.FieldsFunc(strings.ToLower(comment.Body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if _, ok := r.words[word]; ok {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+type postingRateRule struct {
+	history      CommentHistory
+	maxComments  int
+	window       time.Duration
+	timeProvider utils.TimeProvider
+}
+
+// NewPostingRateRule flags the comments of authors who already posted
+// maxComments other comments within window.
+func NewPostingRateRule(history CommentHistory, maxComments int, window time.Duration, timeProvider utils.TimeProvider) *postingRateRule {
+	return &postingRateRule{history: history, maxComments: maxComments, window: window, timeProvider: timeProvider}
+}
+
+func (r postingRateRule) Name() string {
+	return "posting_rate"
+}
+
+func (r postingRateRule) Score(ctx context.Context, comment models.Comment) (float64, error) {
+	count, err := r.history.CountCommentsByAuthorSince(ctx, comment.AuthorId, comment.Id, r.timeProvider.Now().Add(-r.window))
+	if err != nil {
+		return 0, err
+	}
+
+	if count >= r.maxComments {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+type duplicateRule struct {
+	history      CommentHistory
+	window       time.Duration
+	timeProvider utils.TimeProvider
+}
+
+// NewDuplicateRule flags comments repeating, by anyone, another comment
+// posted within window.
+func NewDuplicateRule(history CommentHistory, window time.Duration, timeProvider utils.TimeProvider) *duplicateRule {
+	return &duplicateRule{history: history, window: window, timeProvider: timeProvider}
+}
+
+func (r duplicateRule) Name() string {
+	return "duplicate"
+}
+
+func (r duplicateRule) Score(ctx context.Context, comment models.Comment) (float64, error) {
+	count, err := r.history.CountDuplicateComments(ctx, comment.Body, comment.Id, r.timeProvider.Now().Add(-r.window))
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		return 1, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_spamScorer_ScoreComment(t *testing.T) {
+	comment := models.Comment{AuthorId: bobPrincipal.UserId, Body: "Cheap pills at https://spam.example and www.spam.example"}
+
+	h := NewMockCommentHistory(t)
+	h.On("CountCommentsByAuthorSince", context.TODO(), bobPrincipal.UserId, uuid.Nil, utils.MockClock{}.Now().Add(-10*time.Minute)).Return(2, nil)
+	h.On("CountDuplicateComments", context.TODO(), comment.Body, uuid.Nil, utils.MockClock{}.Now().Add(-24*time.Hour)).Return(0, nil)
+
+	s := NewSpamScorer(1,
+		NewLinkDensityRule(0.5),
+		NewBlocklistRule([]string{" Pills", ""}),
+		NewPostingRateRule(h, 5, 10*time.Minute, utils.MockClock{}),
+		NewDuplicateRule(h, 24*time.Hour, utils.MockClock{}),
+	)
+
+	got, err := s.ScoreComment(context.TODO(), comment)
+	assert.NoError(t, err)
+	assert.InDelta(t, 5.0/3, got.Score, 1e-9)
+	assert.Equal(t, []string{"link_density", "blocklisted_words"}, got.Reasons)
+	assert.True(t, got.Spam)
+}
+
+func Test_spamRules(t *testing.T) {
+	h := NewMockCommentHistory(t)
+	h.On("CountCommentsByAuthorSince", context.TODO(), bobPrincipal.UserId, uuid.Nil, utils.MockClock{}.Now().Add(-time.Minute)).Return(3, nil)
+	h.On("CountDuplicateComments", context.TODO(), "First!", uuid.Nil, utils.MockClock{}.Now().Add(-time.Hour)).Return(1, nil)
+
+	tests := []struct {
+		name string
+		rule SpamRule
+		body string
+		want float64
+	}{
+		{name: "Should ignore comments without links", rule: NewLinkDensityRule(0.2), body: "Nice read, thanks", want: 0},
+		{name: "Should score the share of links", rule: NewLinkDensityRule(0.2), body: "See https://example.com for more on this topic", want: 5.0 / 7},
+		{name: "Should cap the link score", rule: NewLinkDensityRule(0.2), body: "http://a.example http://b.example", want: 1},
+		{name: "Should find blocklisted words in any case", rule: NewBlocklistRule([]string{"casino"}), body: "Best CASINO bonus!", want: 1},
+		{name: "Should only match whole words", rule: NewBlocklistRule([]string{"casino"}), body: "Casinos are boring", want: 0},
+		{name: "Should flag fast posters", rule: NewPostingRateRule(h, 3, time.Minute, utils.MockClock{}), body: "Again", want: 1},
+		{name: "Should let slower posters through", rule: NewPostingRateRule(h, 4, time.Minute, utils.MockClock{}), body: "Again", want: 0},
+		{name: "Should flag duplicates", rule: NewDuplicateRule(h, time.Hour, utils.MockClock{}), body: "First!", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Score(context.TODO(), models.Comment{AuthorId: bobPrincipal.UserId, Body: tt.body})
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func Test_spamRules_edits(t *testing.T) {
+	h := NewMockCommentHistory(t)
+	h.On("CountCommentsByAuthorSince", context.TODO(), bobPrincipal.UserId, bobComment.Id, utils.MockClock{}.Now().Add(-time.Minute)).Return(0, nil)
+	h.On("CountDuplicateComments", context.TODO(), bobComment.Body, bobComment.Id, utils.MockClock{}.Now().Add(-time.Hour)).Return(0, nil)
+
+	rate, err := NewPostingRateRule(h, 1, time.Minute, utils.MockClock{}).Score(context.TODO(), bobComment)
+	assert.NoError(t, err)
+	assert.Zero(t, rate)
+
+	duplicate, err := NewDuplicateRule(h, time.Hour, utils.MockClock{}).Score(context.TODO(), bobComment)
+	assert.NoError(t, err)
+	assert.Zero(t, duplicate)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/slug"
+	"github.com/google/uuid"
+)
+
+type TagsRepository interface {
+	CreateTag(ctx context.Context, tag models.Tag) (uuid.UUID, error)
+	FindAllTags(ctx context.Context, limit, offset int) ([]models.Tag, error)
+	FindTagById(ctx context.Context, id uuid.UUID) (models.Tag, error)
+	UpdateTagById(ctx context.Context, id uuid.UUID, tag models.Tag) error
+	DeleteTagById(ctx context.Context, id uuid.UUID) error
+}
+
+type tagsService struct {
+	repo TagsRepository
+}
+
+func NewTagsService(repo TagsRepository) *tagsService {
+	return &tagsService{repo: repo}
+}
+
+// CreateTag stores tag under a slug derived from its name. Two tags whose
+// names share a slug conflict.
+func (s tagsService) CreateTag(ctx context.Context, tag models.Tag) (uuid.UUID, error) {
+	var err error
+	tag.Slug, err = taxonomySlug(tag.Name)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return s.repo.CreateTag(ctx, tag)
+}
+
+// FindAllTags lists tags along with their number of published posts, most
+// used first.
+func (s tagsService) FindAllTags(ctx context.Context, limit, offset int) ([]models.Tag, error) {
+	return s.repo.FindAllTags(ctx, limit, offset)
+}
+
+func (s tagsService) FindTagById(ctx context.Context, id uuid.UUID) (models.Tag, error) {
+	return s.repo.FindTagById(ctx, id)
+}
+
+// UpdateTagById renames the tag, its slug following the new name.
+func (s tagsService) UpdateTagById(ctx context.Context, id uuid.UUID, newTag models.Tag) error {
+	tag, err := s.repo.FindTagById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if newTag.Name != "" {
+		tag.Name = newTag.Name
+		tag.Slug, err = taxonomySlug(tag.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repo.UpdateTagById(ctx, id, tag)
+}
+
+func (s tagsService) DeleteTagById(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteTagById(ctx, id)
+}
+
+// taxonomySlug derives the slug of a tag or category from its name, which
+// must contain at least one letter or digit.
+func taxonomySlug(name string) (string, error) {
+	s := slug.Make(name)
+	if s == "" {
+		return "", domain.FieldsErrorf(domain.ErrValidation, map[string]string{"name": "must contain a letter or a digit"}, "name must contain a letter or a digit")
+	}
+
+	return s, nil
+}
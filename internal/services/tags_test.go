@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var goTag = models.Tag{
+	Id:        uuid.MustParse("0e9a8b7c-6d5e-4f3a-8b1c-9d8e7f6a5b40"),
+	Name:      "Go",
+	Slug:      "go",
+	CreatedAt: commonTime,
+	UpdatedAt: commonTime,
+}
+
+func Test_tagsService_CreateTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     models.Tag
+		want    models.Tag
+		wantErr error
+	}{
+		{
+			name: "Should derive the slug from the name",
+			tag:  models.Tag{Name: "Go Generics", Slug: "ignored"},
+			want: models.Tag{Name: "Go Generics", Slug: "go-generics"},
+		},
+		{
+			name:    "Should reject names without letters or digits",
+			tag:     models.Tag{Name: "!!!"},
+			wantErr: domain.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMockTagsRepository(t)
+			if tt.wantErr == nil {
+				r.On("CreateTag", context.TODO(), tt.want).Return(goTag.Id, nil)
+			}
+
+			s := NewTagsService(r)
+
+			id, err := s.CreateTag(context.TODO(), tt.tag)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, goTag.Id, id)
+		})
+	}
+}
+
+func Test_tagsService_UpdateTagById(t *testing.T) {
+	r := NewMockTagsRepository(t)
+	r.On("FindTagById", context.TODO(), goTag.Id).Return(goTag, nil)
+
+	renamed := goTag
+	renamed.Name = "Golang"
+	renamed.Slug = "golang"
+	r.On("UpdateTagById", context.TODO(), goTag.Id, renamed).Return(nil)
+
+	s := NewTagsService(r)
+
+	err := s.UpdateTagById(context.TODO(), goTag.Id, models.Tag{Name: "Golang"})
+	assert.NoError(t, err)
+}
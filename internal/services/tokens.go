@@ -0,0 +1,110 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidToken = domain.Errorf(domain.ErrUnauthorized, "invalid token")
+
+const tokenIssuer = "blog"
+
+type AccessClaims struct {
+	UserId   uuid.UUID
+	Username string
+}
+
+type accessTokenClaims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+type TokenConfig struct {
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type tokenService struct {
+	cfg          TokenConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewTokenService(cfg TokenConfig, timeProvider utils.TimeProvider) *tokenService {
+	return &tokenService{cfg: cfg, timeProvider: timeProvider}
+}
+
+// IssueAccessToken returns a signed HS256 JWT for the given user along with
+// its expiration time.
+func (s tokenService) IssueAccessToken(userId uuid.UUID, username string) (string, time.Time, error) {
+	now := s.timeProvider.Now().UTC()
+	expiresAt := now.Add(s.cfg.AccessTokenTTL)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   userId.String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(s.cfg.Secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (s tokenService) ParseAccessToken(accessToken string) (AccessClaims, error) {
+	claims := accessTokenClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, &claims,
+		func(t *jwt.Token) (any, error) {
+			return s.cfg.Secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.timeProvider.Now),
+	)
+	if err != nil {
+		return AccessClaims{}, ErrInvalidToken
+	}
+
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return AccessClaims{}, ErrInvalidToken
+	}
+
+	return AccessClaims{UserId: userId, Username: claims.Username}, nil
+}
+
+// NewRefreshToken returns an opaque random refresh token, the hash under which
+// it must be stored and its expiration time. Only the hash is ever persisted.
+func (s tokenService) NewRefreshToken() (string, string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, HashRefreshToken(token), s.timeProvider.Now().UTC().Add(s.cfg.RefreshTokenTTL), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
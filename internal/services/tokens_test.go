@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTokenConfig = TokenConfig{
+	Secret:          []byte("test-secret"),
+	AccessTokenTTL:  15 * time.Minute,
+	RefreshTokenTTL: 24 * time.Hour,
+}
+
+func Test_tokenService_AccessToken(t *testing.T) {
+	userId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	issuedAt := commonTime
+	issuer := NewTokenService(testTokenConfig, utils.MockClock{NowFunc: func() time.Time { return issuedAt }})
+
+	token, expiresAt, err := issuer.IssueAccessToken(userId, "alice_s")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, issuedAt.Add(testTokenConfig.AccessTokenTTL), expiresAt)
+
+	tests := []struct {
+		name    string
+		cfg     TokenConfig
+		now     time.Time
+		token   string
+		want    AccessClaims
+		wantErr error
+	}{
+		{
+			name:  "Should parse a valid token",
+			cfg:   testTokenConfig,
+			now:   issuedAt.Add(time.Minute),
+			token: token,
+			want:  AccessClaims{UserId: userId, Username: "alice_s"},
+		},
+		{
+			name:    "Should reject an expired token",
+			cfg:     testTokenConfig,
+			now:     expiresAt.Add(time.Second),
+			token:   token,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "Should reject a token signed with another secret",
+			cfg: TokenConfig{
+				Secret:         []byte("other-secret"),
+				AccessTokenTTL: testTokenConfig.AccessTokenTTL,
+			},
+			now:     issuedAt.Add(time.Minute),
+			token:   token,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Should reject garbage",
+			cfg:     testTokenConfig,
+			now:     issuedAt,
+			token:   "not-a-token",
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTokenService(tt.cfg, utils.MockClock{NowFunc: func() time.Time { return tt.now }})
+
+			got, err := s.ParseAccessToken(tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_tokenService_NewRefreshToken(t *testing.T) {
+	s := NewTokenService(testTokenConfig, utils.MockClock{})
+
+	token, tokenHash, expiresAt, err := s.NewRefreshToken()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	other, _, _, err := s.NewRefreshToken()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotEqual(t, token, other)
+	assert.Equal(t, HashRefreshToken(token), tokenHash)
+	assert.NotEqual(t, token, tokenHash)
+	assert.Equal(t, utils.MockClock{}.Now().Add(testTokenConfig.RefreshTokenTTL), expiresAt)
+}
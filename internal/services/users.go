@@ -2,54 +2,189 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log"
+	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/query"
 	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidCredentials = domain.Errorf(domain.ErrUnauthorized, "invalid credentials")
+	ErrInvalidRole        = domain.Errorf(domain.ErrValidation, "invalid role")
+)
+
 type UsersRepository interface {
 	CreateUser(ctx context.Context, user models.User) (uuid.UUID, error)
-	FindAllUsers(ctx context.Context, limit, offset int) ([]models.User, error)
+	FindAllUsers(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, error)
+	CountUsers(ctx context.Context, filters []query.Filter) (int, error)
 	FindUserById(ctx context.Context, id uuid.UUID) (models.User, error)
+	FindUserByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (models.User, error)
 	UpdateUserById(ctx context.Context, id uuid.UUID, user models.User) error
-	DeleteUserById(ctx context.Context, id uuid.UUID) error
+	UpdateUserPasswordById(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	UpdateUserRoleById(ctx context.Context, id uuid.UUID, role models.Role) error
+	DeleteUserById(ctx context.Context, id uuid.UUID, version *time.Time) error
 }
 
 type usersService struct {
-	repo UsersRepository
+	repo   UsersRepository
+	hasher PasswordHasher
+	policy *policy
 }
 
-func NewUsersService(repo UsersRepository) *usersService {
-	return &usersService{repo: repo}
+func NewUsersService(repo UsersRepository, hasher PasswordHasher, policy *policy) *usersService {
+	return &usersService{repo: repo, hasher: hasher, policy: policy}
 }
 
 func (s usersService) CreateUser(ctx context.Context, user models.User) (uuid.UUID, error) {
+	hashed, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	user.HashedPassword = hashed
+	user.Password = ""
+	// Self sign-ups always start as authors, roles are granted afterwards.
+	user.Role = models.RoleAuthor
+
 	return s.repo.CreateUser(ctx, user)
 }
 
-func (s usersService) FindAllUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
-	return s.repo.FindAllUsers(ctx, limit, offset)
+func (s usersService) FindAllUsers(ctx context.Context, page models.Page, filters []query.Filter) ([]models.User, models.PageInfo, error) {
+	return fetchPage(page, func(page models.Page) ([]models.User, error) {
+		return s.repo.FindAllUsers(ctx, page, filters)
+	}, func() (int, error) {
+		return s.repo.CountUsers(ctx, filters)
+	})
 }
 
 func (s usersService) FindUserById(ctx context.Context, id uuid.UUID) (models.User, error) {
 	return s.repo.FindUserById(ctx, id)
 }
 
-func (s usersService) UpdateUserById(ctx context.Context, id uuid.UUID, newUser models.User) error {
+// CanReadPrivateProfile reports whether principal may see the private fields
+// of the user identified by id, which only that user and admins can.
+func (s usersService) CanReadPrivateProfile(principal models.Principal, id uuid.UUID) bool {
+	if principal.UserId != uuid.Nil && principal.UserId == id {
+		return true
+	}
+
+	return s.policy.Can(principal, PermUsersReadPrivateAny)
+}
+
+// UpdateUserById patches the user on behalf of principal, who must be that
+// user or be allowed to update any user. Roles are changed through
+// UpdateUserRoleById only. When version is given the user must still have
+// been last updated at that time. Either way the patch only applies to the
+// user as it was read here.
+func (s usersService) UpdateUserById(ctx context.Context, principal models.Principal, id uuid.UUID, newUser models.User, version *time.Time) error {
+	if principal.UserId != id && !s.policy.Can(principal, PermUsersUpdateAny) {
+		return domain.ErrForbidden
+	}
+
 	user, err := s.repo.FindUserById(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	err = checkVersion(user.UpdatedAt, version)
+	if err != nil {
+		return err
+	}
+
+	newUser.Role = ""
+
+	if newUser.Password != "" {
+		newUser.HashedPassword, err = s.hasher.Hash(newUser.Password)
+		if err != nil {
+			return err
+		}
+		newUser.Password = ""
+	}
+
 	err = utils.PatchStruct(&user, newUser)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.UpdateUserById(ctx, id, user)
+	err = s.repo.UpdateUserById(ctx, id, user)
+	return versionError(err, version)
+}
+
+// DeleteUserById deletes the user on behalf of principal, who must be that
+// user or be allowed to delete any user. When version is given the user must
+// still have been last updated at that time.
+func (s usersService) DeleteUserById(ctx context.Context, principal models.Principal, id uuid.UUID, version *time.Time) error {
+	if principal.UserId != id && !s.policy.Can(principal, PermUsersDeleteAny) {
+		return domain.ErrForbidden
+	}
+
+	err := s.repo.DeleteUserById(ctx, id, version)
+	return versionError(err, version)
 }
 
-func (s usersService) DeleteUserById(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeleteUserById(ctx, id)
+func (s usersService) UpdateUserRoleById(ctx context.Context, id uuid.UUID, role models.Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	return s.repo.UpdateUserRoleById(ctx, id, role)
+}
+
+// VerifyCredentials returns the user identified by usernameOrEmail if password
+// matches, transparently upgrading the stored hash when the hashing parameters
+// have changed since it was written.
+func (s usersService) VerifyCredentials(ctx context.Context, usernameOrEmail, password string) (models.User, error) {
+	user, err := s.repo.FindUserByUsernameOrEmail(ctx, usernameOrEmail)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Spend the same work as a real comparison so response times do not
+		// reveal whether the account exists.
+		s.hasher.Hash(password)
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = s.hasher.Compare(user.HashedPassword, password)
+	if errors.Is(err, ErrPasswordMismatch) {
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if s.hasher.NeedsRehash(user.HashedPassword) {
+		hashed, err := s.rehashPassword(ctx, user.Id, password)
+		if err != nil {
+			// The password matched, so the login goes on with the old hash,
+			// which the next login will try to upgrade again.
+			log.Printf("rehashing password of user %s: %v", user.Id, err)
+			return user, nil
+		}
+
+		user.HashedPassword = hashed
+	}
+
+	return user, nil
+}
+
+// rehashPassword hashes password with the current parameters and stores it as
+// the one of the user.
+func (s usersService) rehashPassword(ctx context.Context, id uuid.UUID, password string) (string, error) {
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.UpdateUserPasswordById(ctx, id, hashed)
+	if err != nil {
+		return "", err
+	}
+
+	return hashed, nil
 }
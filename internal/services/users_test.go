@@ -2,20 +2,26 @@ package services
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/gera9/blog/internal/domain"
 	"github.com/gera9/blog/internal/models"
+	"github.com/gera9/blog/pkg/query"
+	"github.com/gera9/blog/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var commonTime = time.Date(2006, time.January, 02, 0, 0, 0, 0, time.UTC)
 
 func TestNewUsersService(t *testing.T) {
 	type args struct {
-		repo UsersRepository
+		repo   UsersRepository
+		hasher PasswordHasher
 	}
 	tests := []struct {
 		name string
@@ -25,14 +31,15 @@ func TestNewUsersService(t *testing.T) {
 		{
 			name: "Should create a new user service",
 			args: args{
-				repo: NewMockUsersRepository(t),
+				repo:   NewMockUsersRepository(t),
+				hasher: NewMockPasswordHasher(t),
 			},
-			want: NewUsersService(NewMockUsersRepository(t)),
+			want: NewUsersService(NewMockUsersRepository(t), NewMockPasswordHasher(t), nil),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewUsersService(tt.args.repo); !reflect.DeepEqual(got, tt.want) {
+			if got := NewUsersService(tt.args.repo, tt.args.hasher, nil); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewUsersService() = %v, want %v", got, tt.want)
 			}
 		})
@@ -41,7 +48,8 @@ func TestNewUsersService(t *testing.T) {
 
 func Test_usersService_CreateUser(t *testing.T) {
 	type fields struct {
-		repo *MockUsersRepository
+		repo   *MockUsersRepository
+		hasher *MockPasswordHasher
 	}
 	type args struct {
 		ctx  context.Context
@@ -59,18 +67,23 @@ func Test_usersService_CreateUser(t *testing.T) {
 			args: args{
 				ctx: context.TODO(),
 				user: models.User{
-					Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
-					FirstName:      "Alice",
-					LastName:       "Smith",
-					Email:          "alice@example.com",
-					Username:       "alice_s",
-					HashedPassword: "hashed_pwd_1",
-					BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
-					CreatedAt:      commonTime,
-					UpdatedAt:      commonTime,
+					Id:        uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+					FirstName: "Alice",
+					LastName:  "Smith",
+					Email:     "alice@example.com",
+					Username:  "alice_s",
+					Password:  "s3cr3t",
+					BirthDate: time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
+					CreatedAt: commonTime,
+					UpdatedAt: commonTime,
 				},
 			},
 			fields: fields{
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Hash", "s3cr3t").Return("hashed_pwd_1", nil)
+					return h
+				}(),
 				repo: func() *MockUsersRepository {
 					r := NewMockUsersRepository(t)
 					r.On("CreateUser", context.TODO(), models.User{
@@ -80,6 +93,7 @@ func Test_usersService_CreateUser(t *testing.T) {
 						Email:          "alice@example.com",
 						Username:       "alice_s",
 						HashedPassword: "hashed_pwd_1",
+						Role:           models.RoleAuthor,
 						BirthDate:      time.Date(1990, time.April, 12, 0, 0, 0, 0, time.UTC),
 						CreatedAt:      commonTime,
 						UpdatedAt:      commonTime,
@@ -92,11 +106,32 @@ func Test_usersService_CreateUser(t *testing.T) {
 			},
 			want: uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
 		},
+		{
+			name: "Should fail when the password cannot be hashed",
+			args: args{
+				ctx: context.TODO(),
+				user: models.User{
+					Username: "alice_s",
+					Password: "s3cr3t",
+				},
+			},
+			fields: fields{
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Hash", "s3cr3t").Return("", errors.New("hash failure"))
+					return h
+				}(),
+				repo: NewMockUsersRepository(t),
+			},
+			want:    uuid.Nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := usersService{
-				repo: tt.fields.repo,
+				repo:   tt.fields.repo,
+				hasher: tt.fields.hasher,
 			}
 
 			got, err := s.CreateUser(tt.args.ctx, tt.args.user)
@@ -114,9 +149,8 @@ func Test_usersService_FindAllUsers(t *testing.T) {
 		repo *MockUsersRepository
 	}
 	type args struct {
-		ctx    context.Context
-		limit  int
-		offset int
+		ctx  context.Context
+		page models.Page
 	}
 	tests := []struct {
 		name    string
@@ -128,14 +162,13 @@ func Test_usersService_FindAllUsers(t *testing.T) {
 		{
 			name: "Should find all users",
 			args: args{
-				ctx:    context.TODO(),
-				limit:  100,
-				offset: 0,
+				ctx:  context.TODO(),
+				page: models.Page{Limit: 100},
 			},
 			fields: fields{
 				repo: func() *MockUsersRepository {
 					r := NewMockUsersRepository(t)
-					r.On("FindAllUsers", context.TODO(), 100, 0).Return(
+					r.On("FindAllUsers", context.TODO(), models.Page{Limit: 101}, []query.Filter(nil)).Return(
 						[]models.User{
 							{
 								Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
@@ -175,7 +208,7 @@ func Test_usersService_FindAllUsers(t *testing.T) {
 				repo: tt.fields.repo,
 			}
 
-			got, err := s.FindAllUsers(tt.args.ctx, tt.args.limit, tt.args.offset)
+			got, _, err := s.FindAllUsers(tt.args.ctx, tt.args.page, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("usersService.FindAllUsers() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -220,59 +253,467 @@ func Test_usersService_FindUserById(t *testing.T) {
 	}
 }
 
+func Test_usersService_CanReadPrivateProfile(t *testing.T) {
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	tests := []struct {
+		name      string
+		principal models.Principal
+		want      bool
+	}{
+		{name: "Should let users read their own profile", principal: models.Principal{UserId: aliceId, Role: models.RoleAuthor}, want: true},
+		{name: "Should let admins read any profile", principal: models.Principal{UserId: uuid.New(), Role: models.RoleAdmin}, want: true},
+		{name: "Should hide other profiles from editors", principal: models.Principal{UserId: uuid.New(), Role: models.RoleEditor}, want: false},
+		{name: "Should hide profiles from anonymous callers", principal: models.Principal{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUsersService(NewMockUsersRepository(t), NewMockPasswordHasher(t), NewPolicy(DefaultGrants))
+
+			assert.Equal(t, tt.want, s.CanReadPrivateProfile(tt.principal, aliceId))
+		})
+	}
+}
+
 func Test_usersService_UpdateUserById(t *testing.T) {
+	alice := models.User{
+		Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		FirstName:      "Alice",
+		Username:       "alice_s",
+		HashedPassword: "hashed_pwd_1",
+		Role:           models.RoleAuthor,
+	}
+
 	type fields struct {
-		repo UsersRepository
+		repo   *MockUsersRepository
+		hasher *MockPasswordHasher
 	}
 	type args struct {
-		ctx     context.Context
-		id      uuid.UUID
-		newUser models.User
+		ctx       context.Context
+		principal models.Principal
+		id        uuid.UUID
+		newUser   models.User
+		version   *time.Time
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		wantErr bool
+		wantErr error
 	}{
-		// TODO: Add test cases.
+		{
+			name: "Should let users update themselves and hash the new password",
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					updated := alice
+					updated.FirstName = "Alicia"
+					updated.HashedPassword = "hashed_new"
+
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					r.On("UpdateUserById", context.TODO(), alice.Id, updated).Return(nil)
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Hash", "new").Return("hashed_new", nil)
+					return h
+				}(),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: alice.Id, Role: models.RoleAuthor},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia", Password: "new", Role: models.RoleAdmin},
+			},
+		},
+		{
+			name: "Should let admins update any user",
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					updated := alice
+					updated.FirstName = "Alicia"
+
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					r.On("UpdateUserById", context.TODO(), alice.Id, updated).Return(nil)
+					return r
+				}(),
+				hasher: NewMockPasswordHasher(t),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: uuid.New(), Role: models.RoleAdmin},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia"},
+			},
+		},
+		{
+			name: "Should refuse to update a version the client did not see",
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					return r
+				}(),
+				hasher: NewMockPasswordHasher(t),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: alice.Id, Role: models.RoleAuthor},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia"},
+				version:   utils.Ptr(alice.UpdatedAt.Add(-time.Hour)),
+			},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+		{
+			name: "Should report losing a race as a conflict without a version",
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					r.On("UpdateUserById", context.TODO(), alice.Id, mock.Anything).Return(domain.ErrModified)
+					return r
+				}(),
+				hasher: NewMockPasswordHasher(t),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: alice.Id, Role: models.RoleAuthor},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia"},
+			},
+			wantErr: domain.ErrConflict,
+		},
+		{
+			name: "Should report losing a race as a failed precondition with a version",
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserById", context.TODO(), alice.Id).Return(alice, nil)
+					r.On("UpdateUserById", context.TODO(), alice.Id, mock.Anything).Return(domain.ErrModified)
+					return r
+				}(),
+				hasher: NewMockPasswordHasher(t),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: alice.Id, Role: models.RoleAuthor},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia"},
+				version:   utils.Ptr(alice.UpdatedAt),
+			},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+		{
+			name: "Should forbid updating other users",
+			fields: fields{
+				repo:   NewMockUsersRepository(t),
+				hasher: NewMockPasswordHasher(t),
+			},
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: uuid.New(), Role: models.RoleEditor},
+				id:        alice.Id,
+				newUser:   models.User{FirstName: "Alicia"},
+			},
+			wantErr: domain.ErrForbidden,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := usersService{
-				repo: tt.fields.repo,
-			}
-			if err := s.UpdateUserById(tt.args.ctx, tt.args.id, tt.args.newUser); (err != nil) != tt.wantErr {
-				t.Errorf("usersService.UpdateUserById() error = %v, wantErr %v", err, tt.wantErr)
+			s := NewUsersService(tt.fields.repo, tt.fields.hasher, NewPolicy(DefaultGrants))
+
+			err := s.UpdateUserById(tt.args.ctx, tt.args.principal, tt.args.id, tt.args.newUser, tt.args.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
 			}
+
+			assert.NoError(t, err)
 		})
 	}
 }
 
 func Test_usersService_DeleteUserById(t *testing.T) {
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	type args struct {
+		ctx       context.Context
+		principal models.Principal
+		id        uuid.UUID
+		version   *time.Time
+	}
+	tests := []struct {
+		name    string
+		repo    *MockUsersRepository
+		args    args
+		wantErr error
+	}{
+		{
+			name: "Should let users delete themselves",
+			repo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("DeleteUserById", context.TODO(), aliceId, (*time.Time)(nil)).Return(nil)
+				return r
+			}(),
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: aliceId, Role: models.RoleAuthor},
+				id:        aliceId,
+			},
+		},
+		{
+			name: "Should pass the version the client saw on",
+			repo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("DeleteUserById", context.TODO(), aliceId, utils.Ptr(commonTime)).Return(nil)
+				return r
+			}(),
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: aliceId, Role: models.RoleAuthor},
+				id:        aliceId,
+				version:   utils.Ptr(commonTime),
+			},
+		},
+		{
+			name: "Should let admins delete any user",
+			repo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("DeleteUserById", context.TODO(), aliceId, (*time.Time)(nil)).Return(nil)
+				return r
+			}(),
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: uuid.New(), Role: models.RoleAdmin},
+				id:        aliceId,
+			},
+		},
+		{
+			name: "Should forbid deleting other users",
+			repo: NewMockUsersRepository(t),
+			args: args{
+				ctx:       context.TODO(),
+				principal: models.Principal{UserId: uuid.New(), Role: models.RoleEditor},
+				id:        aliceId,
+			},
+			wantErr: domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUsersService(tt.repo, NewMockPasswordHasher(t), NewPolicy(DefaultGrants))
+
+			err := s.DeleteUserById(tt.args.ctx, tt.args.principal, tt.args.id, tt.args.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_usersService_UpdateUserRoleById(t *testing.T) {
+	aliceId := uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4")
+
+	tests := []struct {
+		name    string
+		repo    *MockUsersRepository
+		role    models.Role
+		wantErr error
+	}{
+		{
+			name: "Should update the role",
+			repo: func() *MockUsersRepository {
+				r := NewMockUsersRepository(t)
+				r.On("UpdateUserRoleById", context.TODO(), aliceId, models.RoleEditor).Return(nil)
+				return r
+			}(),
+			role: models.RoleEditor,
+		},
+		{
+			name:    "Should reject unknown roles",
+			repo:    NewMockUsersRepository(t),
+			role:    models.Role("superuser"),
+			wantErr: ErrInvalidRole,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUsersService(tt.repo, NewMockPasswordHasher(t), NewPolicy(DefaultGrants))
+
+			err := s.UpdateUserRoleById(context.TODO(), aliceId, tt.role)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_usersService_VerifyCredentials(t *testing.T) {
+	alice := models.User{
+		Id:             uuid.MustParse("0853f607-2422-4631-8526-832edaa479c4"),
+		Username:       "alice_s",
+		Email:          "alice@example.com",
+		HashedPassword: "hashed_pwd_1",
+	}
+
 	type fields struct {
-		repo UsersRepository
+		repo   *MockUsersRepository
+		hasher *MockPasswordHasher
 	}
 	type args struct {
-		ctx context.Context
-		id  uuid.UUID
+		ctx             context.Context
+		usernameOrEmail string
+		password        string
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		wantErr bool
+		want    models.User
+		wantErr error
 	}{
-		// TODO: Add test cases.
+		{
+			name: "Should return the user when the password matches",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "alice_s",
+				password:        "s3cr3t",
+			},
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserByUsernameOrEmail", context.TODO(), "alice_s").Return(alice, nil)
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Compare", "hashed_pwd_1", "s3cr3t").Return(nil)
+					h.On("NeedsRehash", "hashed_pwd_1").Return(false)
+					return h
+				}(),
+			},
+			want: alice,
+		},
+		{
+			name: "Should rehash the password when the parameters changed",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "alice@example.com",
+				password:        "s3cr3t",
+			},
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserByUsernameOrEmail", context.TODO(), "alice@example.com").Return(alice, nil)
+					r.On("UpdateUserPasswordById", context.TODO(), alice.Id, "rehashed_pwd_1").Return(nil)
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Compare", "hashed_pwd_1", "s3cr3t").Return(nil)
+					h.On("NeedsRehash", "hashed_pwd_1").Return(true)
+					h.On("Hash", "s3cr3t").Return("rehashed_pwd_1", nil)
+					return h
+				}(),
+			},
+			want: models.User{
+				Id:             alice.Id,
+				Username:       alice.Username,
+				Email:          alice.Email,
+				HashedPassword: "rehashed_pwd_1",
+			},
+		},
+		{
+			name: "Should log in even when the rehash cannot be stored",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "alice_s",
+				password:        "s3cr3t",
+			},
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserByUsernameOrEmail", context.TODO(), "alice_s").Return(alice, nil)
+					r.On("UpdateUserPasswordById", context.TODO(), alice.Id, "rehashed_pwd_1").Return(errors.New("connection refused"))
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Compare", "hashed_pwd_1", "s3cr3t").Return(nil)
+					h.On("NeedsRehash", "hashed_pwd_1").Return(true)
+					h.On("Hash", "s3cr3t").Return("rehashed_pwd_1", nil)
+					return h
+				}(),
+			},
+			want: alice,
+		},
+		{
+			name: "Should reject a wrong password",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "alice_s",
+				password:        "wrong",
+			},
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserByUsernameOrEmail", context.TODO(), "alice_s").Return(alice, nil)
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Compare", "hashed_pwd_1", "wrong").Return(ErrPasswordMismatch)
+					return h
+				}(),
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name: "Should reject an unknown user",
+			args: args{
+				ctx:             context.TODO(),
+				usernameOrEmail: "nobody",
+				password:        "s3cr3t",
+			},
+			fields: fields{
+				repo: func() *MockUsersRepository {
+					r := NewMockUsersRepository(t)
+					r.On("FindUserByUsernameOrEmail", context.TODO(), "nobody").Return(models.User{}, domain.ErrNotFound)
+					return r
+				}(),
+				hasher: func() *MockPasswordHasher {
+					h := NewMockPasswordHasher(t)
+					h.On("Hash", "s3cr3t").Return("hashed", nil)
+					return h
+				}(),
+			},
+			wantErr: ErrInvalidCredentials,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := usersService{
-				repo: tt.fields.repo,
+				repo:   tt.fields.repo,
+				hasher: tt.fields.hasher,
 			}
-			if err := s.DeleteUserById(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
-				t.Errorf("usersService.DeleteUserById() error = %v, wantErr %v", err, tt.wantErr)
+
+			got, err := s.VerifyCredentials(tt.args.ctx, tt.args.usernameOrEmail, tt.args.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
 			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gera9/blog/internal/domain"
+)
+
+// checkVersion fails with domain.ErrPreconditionFailed when the caller aimed
+// at version, the update time of a resource as they last saw it, and the
+// resource has been updated since, updatedAt being the time it was last
+// updated at.
+func checkVersion(updatedAt time.Time, version *time.Time) error {
+	if version != nil && !updatedAt.Equal(*version) {
+		return domain.Errorf(domain.ErrPreconditionFailed, "resource was modified in the meantime")
+	}
+
+	return nil
+}
+
+// versionError turns the domain.ErrModified of a write that lost a race into
+// domain.ErrPreconditionFailed when the caller aimed the write at version.
+// Without a version the caller set no precondition, so the race remains a
+// conflict.
+func versionError(err error, version *time.Time) error {
+	if version != nil && errors.Is(err, domain.ErrModified) {
+		return domain.Errorf(domain.ErrPreconditionFailed, "resource was modified in the meantime")
+	}
+
+	return err
+}
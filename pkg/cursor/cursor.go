@@ -0,0 +1,77 @@
+// Package cursor encodes positions in listings ordered by creation time into
+// opaque tokens that clients hand back to get the next or previous page.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalid is returned for tokens that are malformed or were not signed
+// with the secret of the signer.
+var ErrInvalid = errors.New("invalid cursor")
+
+// payloadSize holds the creation time in Unix nanoseconds, the id and the
+// direction.
+const payloadSize = 8 + 16 + 1
+
+// Cursor points at the row of a listing whose neighbours come next.
+type Cursor struct {
+	CreatedAt time.Time
+	Id        uuid.UUID
+	// Backward asks for the rows before the one pointed at rather than
+	// those after it.
+	Backward bool
+}
+
+// Signer encodes cursors into tokens carrying an HMAC-SHA256 signature, so
+// that clients can neither forge nor tamper with them.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+func (s Signer) Encode(c Cursor) string {
+	payload := make([]byte, payloadSize, payloadSize+sha256.Size)
+	binary.BigEndian.PutUint64(payload, uint64(c.CreatedAt.UnixNano()))
+	copy(payload[8:], c.Id[:])
+	if c.Backward {
+		payload[24] = 1
+	}
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.sign(payload)...))
+}
+
+func (s Signer) Decode(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != payloadSize+sha256.Size {
+		return Cursor{}, ErrInvalid
+	}
+
+	payload, signature := raw[:payloadSize], raw[payloadSize:]
+	if !hmac.Equal(signature, s.sign(payload)) || payload[24] > 1 {
+		return Cursor{}, ErrInvalid
+	}
+
+	var c Cursor
+	c.CreatedAt = time.Unix(0, int64(binary.BigEndian.Uint64(payload))).UTC()
+	copy(c.Id[:], payload[8:24])
+	c.Backward = payload[24] == 1
+
+	return c, nil
+}
+
+func (s Signer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package cursor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/cursor"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+
+	for _, c := range []cursor.Cursor{
+		{CreatedAt: time.Date(2006, time.January, 2, 15, 4, 5, 123456000, time.UTC), Id: uuid.New()},
+		{CreatedAt: time.Date(1999, time.December, 31, 23, 59, 59, 0, time.UTC), Id: uuid.New(), Backward: true},
+	} {
+		got, err := signer.Decode(signer.Encode(c))
+		require.NoError(t, err)
+		assert.Equal(t, c, got)
+	}
+}
+
+func TestDecodeRejectsTampering(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+	token := signer.Encode(cursor.Cursor{CreatedAt: time.Now(), Id: uuid.New()})
+
+	tampered := []byte(token)
+	tampered[3] ^= 1
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "truncated", token: token[:len(token)-4]},
+		{name: "tampered", token: string(tampered)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Decode(tt.token)
+			assert.ErrorIs(t, err, cursor.ErrInvalid)
+		})
+	}
+
+	_, err := cursor.NewSigner([]byte("other secret")).Decode(token)
+	assert.ErrorIs(t, err, cursor.ErrInvalid)
+}
@@ -0,0 +1,229 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ContextLines is the number of unchanged lines shown around every change,
+// as diff -u does.
+const ContextLines = 3
+
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is one line of the script turning a text into another.
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines returns a shortest edit script turning a into b, computed with the
+// Myers algorithm.
+func Lines(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	offset := n + m
+
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, slices.Clone(v))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks trace, which holds the furthest reaching paths before
+// every step, from the end of both texts back to their start.
+func backtrack(trace [][]int, a, b []string, offset int) []Edit {
+	x, y := len(a), len(b)
+	edits := make([]Edit, 0, len(a)+len(b))
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Op: Equal, Line: a[x-1]})
+			x--
+			y--
+		}
+
+		if x == prevX {
+			edits = append(edits, Edit{Op: Insert, Line: b[y-1]})
+			y--
+		} else {
+			edits = append(edits, Edit{Op: Delete, Line: a[x-1]})
+			x--
+		}
+	}
+
+	for x > 0 && y > 0 {
+		edits = append(edits, Edit{Op: Equal, Line: a[x-1]})
+		x--
+		y--
+	}
+
+	slices.Reverse(edits)
+
+	return edits
+}
+
+// Unified returns the differences between a and b in the unified format,
+// labelling them fromName and toName. Identical texts yield "".
+func Unified(fromName, toName, a, b string) string {
+	edits := Lines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	for _, h := range hunks(edits) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.fromStart, h.fromCount), hunkRange(h.toStart, h.toCount))
+		for _, edit := range edits[h.start:h.end] {
+			switch edit.Op {
+			case Equal:
+				sb.WriteByte(' ')
+			case Delete:
+				sb.WriteByte('-')
+			case Insert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(edit.Line)
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+type hunk struct {
+	// start and end delimit the edits of the hunk.
+	start, end int
+	// fromStart and toStart are the 0-based lines the hunk starts at.
+	fromStart, fromCount int
+	toStart, toCount     int
+}
+
+// hunks groups the changes of edits along with ContextLines lines of
+// context, merging groups whose context would overlap.
+func hunks(edits []Edit) []hunk {
+	var result []hunk
+
+	fromLine, toLine := 0, 0
+	var current *hunk
+	lastChange := -1
+
+	for i, edit := range edits {
+		if edit.Op != Equal {
+			if current == nil || i-lastChange-1 > 2*ContextLines {
+				if current != nil {
+					result = append(result, closeHunk(edits, *current, lastChange))
+				}
+
+				start := max(0, i-ContextLines)
+				current = &hunk{start: start}
+				current.fromStart, current.toStart = fromLine, toLine
+				for _, e := range edits[start:i] {
+					if e.Op == Equal {
+						current.fromStart--
+						current.toStart--
+					}
+				}
+			}
+			lastChange = i
+		}
+
+		switch edit.Op {
+		case Equal:
+			fromLine++
+			toLine++
+		case Delete:
+			fromLine++
+		case Insert:
+			toLine++
+		}
+	}
+
+	if current != nil {
+		result = append(result, closeHunk(edits, *current, lastChange))
+	}
+
+	return result
+}
+
+func closeHunk(edits []Edit, h hunk, lastChange int) hunk {
+	h.end = min(len(edits), lastChange+1+ContextLines)
+
+	for _, edit := range edits[h.start:h.end] {
+		switch edit.Op {
+		case Equal:
+			h.fromCount++
+			h.toCount++
+		case Delete:
+			h.fromCount++
+		case Insert:
+			h.toCount++
+		}
+	}
+
+	return h
+}
+
+// hunkRange formats a hunk range the way GNU diff does: 1-based, the count
+// omitted when it is 1, and an empty range pointing at the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,88 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gera9/blog/pkg/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	a := strings.Split("a b c a b b a", " ")
+	b := strings.Split("c b a b a c", " ")
+
+	edits := diff.Lines(a, b)
+
+	var from, to []string
+	changes := 0
+	for _, edit := range edits {
+		switch edit.Op {
+		case diff.Equal:
+			from = append(from, edit.Line)
+			to = append(to, edit.Line)
+		case diff.Delete:
+			from = append(from, edit.Line)
+			changes++
+		case diff.Insert:
+			to = append(to, edit.Line)
+			changes++
+		}
+	}
+
+	assert.Equal(t, a, from)
+	assert.Equal(t, b, to)
+	// The shortest edit script of this classic example has 5 edits.
+	assert.Equal(t, 5, changes)
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "Should return nothing for identical texts",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo\n",
+			want: "",
+		},
+		{
+			name: "Should show a change with its context",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "Should split distant changes into hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "Should merge changes whose context overlaps",
+			a:    "1\n2\n3\n4\n5\n6\n",
+			b:    "one\n2\n3\n4\n5\nsix\n",
+			want: "--- a\n+++ b\n@@ -1,6 +1,6 @@\n-1\n+one\n 2\n 3\n 4\n 5\n-6\n+six\n",
+		},
+		{
+			name: "Should diff against an empty text",
+			a:    "",
+			b:    "hello\nworld\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+hello\n+world\n",
+		},
+		{
+			name: "Should show a single removed line",
+			a:    "hello\n",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-hello\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diff.Unified("a", "b", tt.a, tt.b))
+		})
+	}
+}
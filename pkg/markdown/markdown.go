@@ -0,0 +1,233 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/gera9/blog/pkg/slug"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	headingIdRx = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+	classRx     = regexp.MustCompile(`^[a-zA-Z0-9 _-]+$`)
+)
+
+var converter = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		// Code is highlighted with CSS classes rather than inline styles, which
+		// the sanitizer would strip.
+		highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		),
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(headingAnchors{}, 100)),
+	),
+	// Raw HTML is let through here and filtered by the sanitizer instead.
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var sanitizer = newSanitizer()
+
+// newSanitizer allows what user generated content usually needs, plus the
+// attributes produced for headings, highlighted code and task lists.
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(headingIdRx).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(classRx).OnElements("a", "pre", "code", "span")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return p
+}
+
+// Document is the outcome of rendering a Markdown source.
+type Document struct {
+	// HTML is safe to embed in a page.
+	HTML string
+	// Text is the prose of the document without markup, headings or code,
+	// its blocks separated by blank lines.
+	Text string
+	// PlainText is the prose and headings of the document in order, without
+	// markup or code, its blocks separated by blank lines.
+	PlainText string
+	Headings  []Heading
+}
+
+// Heading is a section of a document. Id is the fragment linking to it.
+type Heading struct {
+	Level int
+	Id    string
+	Text  string
+}
+
+// Render converts CommonMark source, with the GitHub Flavored Markdown
+// extensions, into HTML and collects its text and headings.
+func Render(source string) (Document, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(&headingIds{}))
+	root := converter.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := converter.Renderer().Render(&buf, src, root); err != nil {
+		return Document{}, err
+	}
+
+	doc := Document{HTML: sanitizer.Sanitize(buf.String())}
+	doc.Text, doc.PlainText, doc.Headings = collect(root, src)
+
+	return doc, nil
+}
+
+// collect walks the tree of a document gathering its prose, its prose and
+// headings together, and its headings.
+func collect(root ast.Node, src []byte) (string, string, []Heading) {
+	var blocks, plain []string
+	var headings []Heading
+	var block strings.Builder
+
+	flush := func() {
+		if text := strings.Join(strings.Fields(block.String()), " "); text != "" {
+			blocks = append(blocks, text)
+			plain = append(plain, text)
+		}
+		block.Reset()
+	}
+
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := n.(type) {
+		case *ast.Heading:
+			if entering {
+				id, _ := n.AttributeString("id")
+				idBytes, _ := id.([]byte)
+				heading := Heading{
+					Level: n.Level,
+					Id:    string(idBytes),
+					Text:  strings.Join(strings.Fields(inlineText(n, src)), " "),
+				}
+				headings = append(headings, heading)
+				if heading.Text != "" {
+					plain = append(plain, heading.Text)
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				block.Write(n.Segment.Value(src))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					block.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				block.Write(n.Value)
+			}
+		}
+
+		if !entering && n.Type() == ast.TypeBlock {
+			flush()
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.Join(blocks, "\n\n"), strings.Join(plain, "\n\n"), headings
+}
+
+// inlineText returns the text of the inline children of n, leaving out the
+// anchors added to headings.
+func inlineText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Segment.Value(src))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(c.Value)
+		case *ast.Link:
+			if class, ok := c.AttributeString("class"); ok && string(class.([]byte)) == "anchor" {
+				continue
+			}
+			sb.WriteString(inlineText(c, src))
+		default:
+			sb.WriteString(inlineText(c, src))
+		}
+	}
+
+	return sb.String()
+}
+
+// headingIds derives heading ids from their text the way post slugs are
+// derived from titles, numbering repeated headings.
+type headingIds struct {
+	taken []string
+}
+
+func (ids *headingIds) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := slug.Make(string(value))
+	if base == "" {
+		base = "section"
+	}
+
+	id := slug.Unique(base, ids.taken)
+	ids.taken = append(ids.taken, id)
+
+	return []byte(id)
+}
+
+func (ids *headingIds) Put(value []byte) {
+	ids.taken = append(ids.taken, string(value))
+}
+
+// headingAnchors prepends to every heading a link to itself, so readers can
+// share links to sections.
+type headingAnchors struct{}
+
+func (headingAnchors) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		id, ok := heading.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		idBytes, ok := id.([]byte)
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+
+		link := ast.NewLink()
+		link.Destination = append([]byte("#"), idBytes...)
+		link.SetAttributeString("class", []byte("anchor"))
+		link.AppendChild(link, ast.NewString([]byte("#")))
+
+		if first := heading.FirstChild(); first != nil {
+			heading.InsertBefore(heading, first, link)
+		} else {
+			heading.AppendChild(heading, link)
+		}
+
+		return ast.WalkSkipChildren, nil
+	})
+}
@@ -0,0 +1,94 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/pkg/markdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Should render CommonMark",
+			source:   "Some *emphasis* and **strong** text.",
+			contains: []string{"<p>Some <em>emphasis</em> and <strong>strong</strong> text.</p>"},
+		},
+		{
+			name:   "Should render the GFM extensions",
+			source: "| a | b |\n|---|---|\n| 1 | 2 |\n\n- [x] done\n\n~~gone~~ https://example.com",
+			contains: []string{
+				"<table>",
+				`<input checked="" disabled="" type="checkbox"> done`,
+				"<del>gone</del>",
+				`<a href="https://example.com" rel="nofollow">https://example.com</a>`,
+			},
+		},
+		{
+			name:   "Should add anchors to headings",
+			source: "# Hello World\n\n## Привет, мир\n\n## Hello World",
+			contains: []string{
+				`<h1 id="hello-world"><a href="#hello-world" class="anchor" rel="nofollow">#</a>Hello World</h1>`,
+				`<h2 id="privet-mir">`,
+				`<h2 id="hello-world-2">`,
+			},
+		},
+		{
+			name:     "Should highlight code blocks",
+			source:   "```go\nfunc main() {}\n```",
+			contains: []string{`<pre class="chroma">`, `<span class="kd">func</span>`},
+			excludes: []string{"style="},
+		},
+		{
+			name:     "Should keep harmless raw HTML",
+			source:   "<details><summary>More</summary>Hidden</details>",
+			contains: []string{"<details><summary>More</summary>Hidden</details>"},
+		},
+		{
+			name:     "Should strip scripts and event handlers",
+			source:   "<script>alert(1)</script><b onclick=\"alert(1)\">bold</b> <img src=x onerror=alert(1)>",
+			contains: []string{"<b>bold</b>"},
+			excludes: []string{"<script", "alert", "onclick", "onerror"},
+		},
+		{
+			name:     "Should drop dangerous links",
+			source:   "[click](javascript:alert(1))",
+			excludes: []string{"javascript:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := markdown.Render(tt.source)
+			require.NoError(t, err)
+
+			got := doc.HTML
+
+			for _, want := range tt.contains {
+				assert.Contains(t, got, want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, got, unwanted)
+			}
+		})
+	}
+}
+
+func TestRenderCollectsTextAndHeadings(t *testing.T) {
+	source := "# Hello *World*\n\nSome *text* here\nand there.\n\n```go\nfunc main() {}\n```\n\n- one\n- two [links](https://example.com)\n\n## Hello World\n\n> A quote."
+
+	doc, err := markdown.Render(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Some text here and there.\n\none\n\ntwo links\n\nA quote.", doc.Text)
+	assert.Equal(t, "Hello World\n\nSome text here and there.\n\none\n\ntwo links\n\nHello World\n\nA quote.", doc.PlainText)
+	assert.Equal(t, []markdown.Heading{
+		{Level: 1, Id: "hello-world", Text: "Hello World"},
+		{Level: 2, Id: "hello-world-2", Text: "Hello World"},
+	}, doc.Headings)
+}
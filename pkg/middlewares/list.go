@@ -4,27 +4,39 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+
+	"github.com/gera9/blog/pkg/cursor"
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/gera9/blog/pkg/query"
 )
 
 var (
-	ContextKeyLimit  = &ContextKey{"limit"}
-	ContextKeyOffset = &ContextKey{"offset"}
+	ContextKeyLimit      = &ContextKey{"limit"}
+	ContextKeyOffset     = &ContextKey{"offset"}
+	ContextKeyCursor     = &ContextKey{"cursor"}
+	ContextKeyQuery      = &ContextKey{"query"}
+	ContextKeyProjection = &ContextKey{"projection"}
 )
 
+// DefaultLimit is the page size of requests that do not ask for one.
+const DefaultLimit = 10
+
+// List reads the ?limit= and ?offset= of listings, capping the limit at the
+// max page size of the manager.
 func (mm MiddlewareManager) List(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 
-		limitStr := q.Get("limit")
-		if limitStr == "" {
-			limitStr = "10"
-		}
-
-		limit, err := strconv.Atoi(limitStr)
-		if err != nil {
-			http.Error(w, "invalid limit value", http.StatusBadRequest)
-			return
+		limit := DefaultLimit
+		if limitStr := q.Get("limit"); limitStr != "" {
+			var err error
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit < 1 {
+				problem.Write(w, r, http.StatusBadRequest, "invalid limit value")
+				return
+			}
 		}
+		limit = min(limit, mm.maxLimit)
 
 		offsetStr := q.Get("offset")
 		if offsetStr == "" {
@@ -32,8 +44,8 @@ func (mm MiddlewareManager) List(next http.Handler) http.Handler {
 		}
 
 		offset, err := strconv.Atoi(offsetStr)
-		if err != nil {
-			http.Error(w, "invalid offset value", http.StatusBadRequest)
+		if err != nil || offset < 0 {
+			problem.Write(w, r, http.StatusBadRequest, "invalid offset value")
 			return
 		}
 
@@ -45,3 +57,86 @@ func (mm MiddlewareManager) List(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// KeysetList is List for listings that can also be walked with the opaque
+// ?cursor= tokens they hand out, which cannot be combined with an offset.
+func (mm MiddlewareManager) KeysetList(next http.Handler) http.Handler {
+	return mm.List(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		token := q.Get("cursor")
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if q.Has("offset") {
+			problem.Write(w, r, http.StatusBadRequest, "cursor and offset cannot be combined")
+			return
+		}
+
+		c, err := mm.cursors.Decode(token)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, "invalid cursor value")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyCursor, c)))
+	}))
+}
+
+// CursorFromContext returns the cursor read by KeysetList, if any.
+func CursorFromContext(ctx context.Context) (cursor.Cursor, bool) {
+	c, ok := ctx.Value(ContextKeyCursor).(cursor.Cursor)
+	return c, ok
+}
+
+// Query reads the ?sort= and ?filter[field][op]= of listings, as allowed by
+// schema. Cursors only follow the default order, so a sorted listing must
+// be paged by offset; Query goes after KeysetList to enforce it.
+func (mm MiddlewareManager) Query(schema query.Schema) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q, err := query.Parse(r.URL.Query(), schema)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if _, ok := CursorFromContext(r.Context()); ok && len(q.Sort) > 0 {
+				problem.Write(w, r, http.StatusBadRequest, "cursor and sort cannot be combined")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyQuery, q)))
+		})
+	}
+}
+
+// QueryFromContext returns the query read by Query.
+func QueryFromContext(ctx context.Context) query.Query {
+	q, _ := ctx.Value(ContextKeyQuery).(query.Query)
+	return q
+}
+
+// Project reads the ?fields= and ?expand= of requests, which may only name
+// the given fields and relations.
+func (mm MiddlewareManager) Project(fields, relations []string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := query.ParseProjection(r.URL.Query(), fields, relations)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ContextKeyProjection, p)))
+		})
+	}
+}
+
+// ProjectionFromContext returns the projection read by Project.
+func ProjectionFromContext(ctx context.Context) query.Projection {
+	p, _ := ctx.Value(ContextKeyProjection).(query.Projection)
+	return p
+}
@@ -0,0 +1,142 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/cursor"
+	"github.com/gera9/blog/pkg/middlewares"
+	"github.com/gera9/blog/pkg/query"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareManager_KeysetList(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+	mm := middlewares.NewMiddlewareManager(middlewares.ListConfig{MaxLimit: 50, Cursors: signer})
+
+	c := cursor.Cursor{CreatedAt: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC), Id: uuid.New(), Backward: true}
+	token := signer.Encode(c)
+
+	var (
+		gotLimit  int
+		gotOffset int
+		gotCursor *cursor.Cursor
+	)
+	handler := mm.KeysetList(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLimit = r.Context().Value(middlewares.ContextKeyLimit).(int)
+		gotOffset = r.Context().Value(middlewares.ContextKeyOffset).(int)
+		gotCursor = nil
+		if c, ok := middlewares.CursorFromContext(r.Context()); ok {
+			gotCursor = &c
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+		wantCursor *cursor.Cursor
+	}{
+		{name: "Should default the limit", wantStatus: http.StatusNoContent, wantLimit: middlewares.DefaultLimit},
+		{name: "Should read the limit and offset", query: "?limit=20&offset=40", wantStatus: http.StatusNoContent, wantLimit: 20, wantOffset: 40},
+		{name: "Should cap the limit", query: "?limit=1000", wantStatus: http.StatusNoContent, wantLimit: 50},
+		{name: "Should reject non-positive limits", query: "?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "Should reject negative offsets", query: "?offset=-1", wantStatus: http.StatusBadRequest},
+		{name: "Should read the cursor", query: "?limit=5&cursor=" + token, wantStatus: http.StatusNoContent, wantLimit: 5, wantCursor: &c},
+		{name: "Should reject forged cursors", query: "?cursor=abc", wantStatus: http.StatusBadRequest},
+		{name: "Should reject cursors along with offsets", query: "?offset=0&cursor=" + token, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, tt.wantLimit, gotLimit)
+				assert.Equal(t, tt.wantOffset, gotOffset)
+				assert.Equal(t, tt.wantCursor, gotCursor)
+			}
+		})
+	}
+}
+
+func TestMiddlewareManager_Query(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+	mm := middlewares.NewMiddlewareManager(middlewares.ListConfig{MaxLimit: 50, Cursors: signer})
+	token := signer.Encode(cursor.Cursor{CreatedAt: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC), Id: uuid.New()})
+
+	schema := query.Schema{
+		"title":      {Type: query.TypeText, Sortable: true, Filterable: true},
+		"created_at": {Type: query.TypeTime, Sortable: true},
+	}
+
+	var gotQuery query.Query
+	handler := mm.KeysetList(mm.Query(schema)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = middlewares.QueryFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantQuery  query.Query
+	}{
+		{name: "Should pass requests without a query through", wantStatus: http.StatusNoContent},
+		{
+			name:       "Should read sorts and filters",
+			query:      "?sort=-created_at&filter[title][contains]=go",
+			wantStatus: http.StatusNoContent,
+			wantQuery: query.Query{
+				Sort:    []query.Sort{{Field: "created_at", Desc: true}},
+				Filters: []query.Filter{{Field: "title", Op: query.OpContains, Value: "go"}},
+			},
+		},
+		{name: "Should reject fields outside the schema", query: "?sort=email", wantStatus: http.StatusBadRequest},
+		{name: "Should reject filters on sort only fields", query: "?filter[created_at][gt]=2006-01-02", wantStatus: http.StatusBadRequest},
+		{name: "Should keep filters along with cursors", query: "?filter[title]=go&cursor=" + token, wantStatus: http.StatusNoContent, wantQuery: query.Query{Filters: []query.Filter{{Field: "title", Op: query.OpEq, Value: "go"}}}},
+		{name: "Should reject sorts along with cursors", query: "?sort=title&cursor=" + token, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = query.Query{}
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, tt.wantQuery, gotQuery)
+			}
+		})
+	}
+}
+
+func TestMiddlewareManager_Project(t *testing.T) {
+	mm := middlewares.NewMiddlewareManager(middlewares.ListConfig{})
+
+	var gotProjection query.Projection
+	handler := mm.Project([]string{"id", "title"}, []string{"author"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotProjection = middlewares.ProjectionFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?fields=title,id&expand=author", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, query.Projection{Fields: []string{"title", "id"}, Expand: []string{"author"}}, gotProjection)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?fields=content", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
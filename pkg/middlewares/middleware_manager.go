@@ -1,5 +1,9 @@
 package middlewares
 
+import (
+	"github.com/gera9/blog/pkg/cursor"
+)
+
 type ContextKey struct {
 	Name string
 }
@@ -8,5 +12,26 @@ func (ck ContextKey) String() string {
 	return "blog context key value " + ck.Name
 }
 
+// ListConfig sets how listings are paginated.
+type ListConfig struct {
+	// MaxLimit caps the page size clients may ask for.
+	MaxLimit int
+	// Cursors decodes the ?cursor= tokens of keyset listings.
+	Cursors *cursor.Signer
+}
+
+// MiddlewareManager provides the middlewares that need nothing from the
+// application, such as the parsing of list parameters. Authentication and
+// authorization are provided by auth.Middleware instead, since they depend on
+// its principals and permissions.
 type MiddlewareManager struct {
+	maxLimit int
+	cursors  *cursor.Signer
+}
+
+func NewMiddlewareManager(list ListConfig) *MiddlewareManager {
+	return &MiddlewareManager{
+		maxLimit: list.MaxLimit,
+		cursors:  list.Cursors,
+	}
 }
@@ -0,0 +1,44 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Instance holds the ID that
+// middleware.RequestID assigned to the failed request so that responses can
+// be matched with the server logs.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// New returns a problem of the generic about:blank type for status.
+func New(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: middleware.GetReqID(r.Context()),
+	}
+}
+
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Write sends a problem for status with the given detail.
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	New(r, status, detail).Write(w)
+}
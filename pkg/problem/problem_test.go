@@ -0,0 +1,42 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gera9/blog/pkg/problem"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	var requestId string
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId = middleware.GetReqID(r.Context())
+
+		p := problem.New(r, http.StatusUnprocessableEntity, "validation failed")
+		p.Errors = map[string]string{"title": "is required"}
+		p.Write(w)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/posts", nil))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+	var got problem.Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, problem.Problem{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "validation failed",
+		Instance: requestId,
+		Errors:   map[string]string{"title": "is required"},
+	}, got)
+	assert.NotEmpty(t, requestId)
+}
@@ -0,0 +1,231 @@
+// Package query parses the ?sort= and ?filter[field][op]= parameters of
+// listings, and the ?fields= and ?expand= parameters shaping resources,
+// against a whitelist of the fields clients may use.
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Op string
+
+const (
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpGt       Op = "gt"
+	OpGte      Op = "gte"
+	OpLt       Op = "lt"
+	OpLte      Op = "lte"
+	OpContains Op = "contains"
+)
+
+// Type decides how the values of a field are read and which operators
+// apply to it by default.
+type Type int
+
+const (
+	// TypeText compares text, and matches it case-insensitively with
+	// contains.
+	TypeText Type = iota
+	// TypeEnum compares text that only ever takes a few values.
+	TypeEnum
+	TypeTime
+	TypeInt
+)
+
+func (t Type) ops() []Op {
+	switch t {
+	case TypeText:
+		return []Op{OpEq, OpNe, OpContains}
+	case TypeTime, TypeInt:
+		return []Op{OpEq, OpNe, OpGt, OpGte, OpLt, OpLte}
+	}
+
+	return []Op{OpEq, OpNe}
+}
+
+// Field is a field of a listing that clients may use.
+type Field struct {
+	Type Type
+	// Sortable allows sorting the listing by the field.
+	Sortable bool
+	// Filterable allows filtering the listing on the field with the
+	// operators of its type.
+	Filterable bool
+}
+
+// Schema whitelists the fields of a listing by name.
+type Schema map[string]Field
+
+// Sort orders a listing by a field, ascending unless Desc.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter narrows a listing down to the rows whose field compares with
+// Value, which holds a string, a time.Time or an int depending on the type
+// of the field.
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Query is the sorting and filtering asked for by a request.
+type Query struct {
+	Sort    []Sort
+	Filters []Filter
+}
+
+// Error reports a parameter breaking the rules of the schema.
+type Error struct {
+	Param  string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Param, e.Reason)
+}
+
+var filterParam = regexp.MustCompile(`^filter\[(\w+)\](?:\[(\w+)\])?$`)
+
+// Parse reads ?sort=-updated_at,title, a comma separated list of fields each
+// sorting descending when prefixed with a hyphen, and filters such as
+// ?filter[title][contains]=go, where a missing operator stands for eq.
+// Times are RFC 3339 timestamps or dates.
+func Parse(values url.Values, schema Schema) (Query, error) {
+	var q Query
+
+	if sort := values.Get("sort"); sort != "" {
+		for _, name := range strings.Split(sort, ",") {
+			s := Sort{Field: strings.TrimPrefix(name, "-"), Desc: strings.HasPrefix(name, "-")}
+
+			if !schema[s.Field].Sortable {
+				return Query{}, &Error{Param: "sort", Reason: fmt.Sprintf("cannot sort by %q", s.Field)}
+			}
+			if slices.ContainsFunc(q.Sort, func(other Sort) bool { return other.Field == s.Field }) {
+				return Query{}, &Error{Param: "sort", Reason: fmt.Sprintf("%q is repeated", s.Field)}
+			}
+
+			q.Sort = append(q.Sort, s)
+		}
+	}
+
+	params := make([]string, 0, len(values))
+	for param := range values {
+		if strings.HasPrefix(param, "filter[") {
+			params = append(params, param)
+		}
+	}
+	// Sorted so that the filters, and the SQL built from them, do not depend
+	// on the order of map iteration.
+	slices.Sort(params)
+
+	for _, param := range params {
+		match := filterParam.FindStringSubmatch(param)
+		if match == nil {
+			return Query{}, &Error{Param: param, Reason: "expected filter[field] or filter[field][op]"}
+		}
+
+		name, op := match[1], Op(match[2])
+		if op == "" {
+			op = OpEq
+		}
+
+		field, ok := schema[name]
+		if !ok || !field.Filterable {
+			return Query{}, &Error{Param: param, Reason: fmt.Sprintf("cannot filter on %q", name)}
+		}
+		if !slices.Contains(field.Type.ops(), op) {
+			return Query{}, &Error{Param: param, Reason: fmt.Sprintf("%q does not support %s", name, op)}
+		}
+
+		for _, raw := range values[param] {
+			value, err := parseValue(field.Type, raw)
+			if err != nil {
+				return Query{}, &Error{Param: param, Reason: err.Error()}
+			}
+
+			q.Filters = append(q.Filters, Filter{Field: name, Op: op, Value: value})
+		}
+	}
+
+	return q, nil
+}
+
+// Projection is the shape of the resources asked for by a request.
+type Projection struct {
+	// Fields lists the fields to respond with, every field being included
+	// when empty.
+	Fields []string
+	// Expand lists the related resources to embed.
+	Expand []string
+}
+
+// ParseProjection reads ?fields=id,title and ?expand=author, comma separated
+// lists that may only name the given fields and relations respectively.
+func ParseProjection(values url.Values, fields, relations []string) (Projection, error) {
+	var p Projection
+	var err error
+
+	p.Fields, err = parseList(values, "fields", fields)
+	if err != nil {
+		return Projection{}, err
+	}
+
+	p.Expand, err = parseList(values, "expand", relations)
+	if err != nil {
+		return Projection{}, err
+	}
+
+	return p, nil
+}
+
+func parseList(values url.Values, param string, allowed []string) ([]string, error) {
+	raw := values.Get(param)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		if !slices.Contains(allowed, name) {
+			return nil, &Error{Param: param, Reason: fmt.Sprintf("%q is not available", name)}
+		}
+		if slices.Contains(names, name) {
+			return nil, &Error{Param: param, Reason: fmt.Sprintf("%q is repeated", name)}
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func parseValue(t Type, raw string) (any, error) {
+	switch t {
+	case TypeTime:
+		if at, err := time.Parse(time.RFC3339, raw); err == nil {
+			return at, nil
+		}
+		if at, err := time.Parse(time.DateOnly, raw); err == nil {
+			return at, nil
+		}
+		return nil, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a date", raw)
+	case TypeInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return n, nil
+	}
+
+	return raw, nil
+}
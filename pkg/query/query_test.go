@@ -0,0 +1,111 @@
+package query_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+var schema = query.Schema{
+	"title":      {Type: query.TypeText, Sortable: true, Filterable: true},
+	"status":     {Type: query.TypeEnum, Filterable: true},
+	"created_at": {Type: query.TypeTime, Sortable: true, Filterable: true},
+	"word_count": {Type: query.TypeInt, Filterable: true},
+	"content":    {Type: query.TypeText},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    query.Query
+		wantErr string
+	}{
+		{name: "empty", query: "", want: query.Query{}},
+		{
+			name:  "sort",
+			query: "sort=-created_at,title",
+			want:  query.Query{Sort: []query.Sort{{Field: "created_at", Desc: true}, {Field: "title"}}},
+		},
+		{
+			name:  "filters",
+			query: "filter[title][contains]=go&filter[status]=published&filter[created_at][gte]=2006-01-02&filter[word_count][lt]=500",
+			want: query.Query{Filters: []query.Filter{
+				{Field: "created_at", Op: query.OpGte, Value: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)},
+				{Field: "status", Op: query.OpEq, Value: "published"},
+				{Field: "title", Op: query.OpContains, Value: "go"},
+				{Field: "word_count", Op: query.OpLt, Value: 500},
+			}},
+		},
+		{
+			name:  "repeated filters",
+			query: "filter[created_at][gte]=2006-01-02T15:04:05Z&filter[created_at][gte]=2006-01-03T00:00:00%2B02:00",
+			want: query.Query{Filters: []query.Filter{
+				{Field: "created_at", Op: query.OpGte, Value: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)},
+				{Field: "created_at", Op: query.OpGte, Value: time.Date(2006, time.January, 3, 0, 0, 0, 0, time.FixedZone("", 2*60*60))},
+			}},
+		},
+		{name: "unknown sort field", query: "sort=password", wantErr: `invalid sort: cannot sort by "password"`},
+		{name: "unsortable field", query: "sort=status", wantErr: `invalid sort: cannot sort by "status"`},
+		{name: "repeated sort field", query: "sort=title,-title", wantErr: `invalid sort: "title" is repeated`},
+		{name: "unfilterable field", query: "filter[content]=x", wantErr: `invalid filter[content]: cannot filter on "content"`},
+		{name: "unsupported operator", query: "filter[status][contains]=pub", wantErr: `invalid filter[status][contains]: "status" does not support contains`},
+		{name: "unknown operator", query: "filter[title][like]=x", wantErr: `invalid filter[title][like]: "title" does not support like`},
+		{name: "malformed filter", query: "filter[title]x=1", wantErr: `invalid filter[title]x: expected filter[field] or filter[field][op]`},
+		{name: "invalid time", query: "filter[created_at][gt]=yesterday", wantErr: `invalid filter[created_at][gt]: "yesterday" is neither an RFC 3339 timestamp nor a date`},
+		{name: "invalid integer", query: "filter[word_count]=many", wantErr: `invalid filter[word_count]: "many" is not an integer`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			got, err := query.Parse(values, schema)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseProjection(t *testing.T) {
+	fields := []string{"id", "title", "extract", "content"}
+	relations := []string{"author"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    query.Projection
+		wantErr string
+	}{
+		{name: "empty", query: "", want: query.Projection{}},
+		{name: "fields", query: "fields=id,title,extract", want: query.Projection{Fields: []string{"id", "title", "extract"}}},
+		{name: "expand", query: "expand=author", want: query.Projection{Expand: []string{"author"}}},
+		{name: "unknown field", query: "fields=id,password", wantErr: `invalid fields: "password" is not available`},
+		{name: "empty field", query: "fields=id,", wantErr: `invalid fields: "" is not available`},
+		{name: "repeated field", query: "fields=id,id", wantErr: `invalid fields: "id" is repeated`},
+		{name: "unknown relation", query: "expand=comments", wantErr: `invalid expand: "comments" is not available`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			got, err := query.ParseProjection(values, fields, relations)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search query, every term of which must match.
+type Query struct {
+	Terms []Term
+}
+
+// Term is a word, or a phrase when it holds several words that must appear
+// next to each other. A prefix term also matches words starting with its
+// last word.
+type Term struct {
+	Words  []string
+	Prefix bool
+}
+
+// Parse reads a query made of words, "quoted phrases" and prefixes such as
+// comp*. Punctuation only separates words, so it can never reach the query
+// syntax of the database.
+func Parse(q string) Query {
+	var query Query
+
+	for q != "" {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+
+		var token string
+		phrase := q[0] == '"'
+		if phrase {
+			var found bool
+			token, q, found = strings.Cut(q[1:], `"`)
+			if !found {
+				q = ""
+			}
+		} else {
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			if end < 0 {
+				end = len(q)
+			}
+			token, q = q[:end], q[end:]
+		}
+
+		term := Term{Words: words(token)}
+		if len(term.Words) == 0 {
+			continue
+		}
+		term.Prefix = !phrase && strings.HasSuffix(token, "*")
+
+		query.Terms = append(query.Terms, term)
+	}
+
+	return query
+}
+
+func (q Query) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
+
+// TSQuery renders q in the syntax of the Postgres to_tsquery function.
+func (q Query) TSQuery() string {
+	terms := make([]string, len(q.Terms))
+	for i, term := range q.Terms {
+		lexemes := make([]string, len(term.Words))
+		for j, word := range term.Words {
+			lexemes[j] = "'" + word + "'"
+		}
+		if term.Prefix {
+			lexemes[len(lexemes)-1] += ":*"
+		}
+
+		terms[i] = strings.Join(lexemes, " <-> ")
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// String returns the words of q separated by spaces, which suits similarity
+// searches.
+func (q Query) String() string {
+	var all []string
+	for _, term := range q.Terms {
+		all = append(all, term.Words...)
+	}
+
+	return strings.Join(all, " ")
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,34 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/gera9/blog/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       string
+		tsquery string
+		text    string
+	}{
+		{name: "Should require every word", q: "Go  generics", tsquery: "'go' & 'generics'", text: "go generics"},
+		{name: "Should keep phrases together", q: `"hello world" go`, tsquery: "'hello' <-> 'world' & 'go'", text: "hello world go"},
+		{name: "Should match prefixes", q: "gener*", tsquery: "'gener':*", text: "gener"},
+		{name: "Should split punctuated words into phrases", q: "e-mail", tsquery: "'e' <-> 'mail'", text: "e mail"},
+		{name: "Should close unterminated phrases", q: `"hello world`, tsquery: "'hello' <-> 'world'", text: "hello world"},
+		{name: "Should drop the query syntax", q: `a'b & !c | (d):*`, tsquery: "'a' <-> 'b' & 'c' & 'd':*", text: "a b c d"},
+		{name: "Should keep other scripts", q: "Привет мир", tsquery: "'привет' & 'мир'", text: "привет мир"},
+		{name: "Should be empty without words", q: ` "" & * `, tsquery: "", text: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := search.Parse(tt.q)
+			assert.Equal(t, tt.tsquery, query.TSQuery())
+			assert.Equal(t, tt.text, query.String())
+			assert.Equal(t, tt.tsquery == "", query.IsEmpty())
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the size of the posts.slug column.
+const MaxLength = 255
+
+var pattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{Nd}]+(-[\p{Ll}\p{Lo}\p{Nd}]+)*$`)
+
+// transliterations covers the letters that do not decompose into an ASCII
+// base letter plus combining marks.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// Make turns s into a lowercase, hyphen separated slug. Accents are dropped
+// and Latin, Cyrillic and Greek letters are transliterated to ASCII; letters
+// of other scripts are kept as they are. The result is empty when s has no
+// letters or digits.
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		var out string
+		if t, ok := transliterations[r]; ok {
+			out = t
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out = string(unicode.ToLower(r))
+		}
+
+		if out == "" {
+			pendingHyphen = pendingHyphen || !unicode.IsLetter(r)
+			continue
+		}
+
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(out)
+	}
+
+	return truncate(norm.NFC.String(b.String()), MaxLength)
+}
+
+// IsValid reports whether s is already in the form Make produces.
+func IsValid(s string) bool {
+	return utf8.RuneCountInString(s) <= MaxLength && pattern.MatchString(s)
+}
+
+// Unique returns base, or base followed by the lowest "-n" suffix, such that
+// the result is not in taken.
+func Unique(base string, taken []string) string {
+	used := make(map[string]struct{}, len(taken))
+	for _, t := range taken {
+		used[t] = struct{}{}
+	}
+
+	if _, ok := used[base]; !ok {
+		return base
+	}
+
+	for n := 2; ; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		candidate := truncate(base, MaxLength-len(suffix)) + suffix
+		if _, ok := used[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+// truncate cuts s to at most n characters without leaving a trailing hyphen.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:n]), "-")
+}
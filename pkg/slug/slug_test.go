@@ -0,0 +1,63 @@
+package slug_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gera9/blog/pkg/slug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Hello World", want: "hello-world"},
+		{in: "  Hello,   World!!  ", want: "hello-world"},
+		{in: "Crème brûlée à la française", want: "creme-brulee-a-la-francaise"},
+		{in: "Straße & Smørrebrød", want: "strasse-smorrebrod"},
+		{in: "Привет, мир", want: "privet-mir"},
+		{in: "Καλημέρα κόσμε", want: "kalimera-kosme"},
+		{in: "東京 2024", want: "東京-2024"},
+		{in: "ﬁve ½ tips", want: "five-1-2-tips"},
+		{in: "Don't stop", want: "don-t-stop"},
+		{in: "!!!", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := slug.Make(tt.in)
+			assert.Equal(t, tt.want, got)
+			if got != "" {
+				assert.True(t, slug.IsValid(got))
+			}
+		})
+	}
+}
+
+func TestMakeTruncates(t *testing.T) {
+	got := slug.Make(strings.Repeat("ab ", 200))
+
+	assert.LessOrEqual(t, utf8.RuneCountInString(got), slug.MaxLength)
+	assert.True(t, slug.IsValid(got))
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, slug.IsValid("hello-world-2"))
+	assert.False(t, slug.IsValid("Hello-World"))
+	assert.False(t, slug.IsValid("hello--world"))
+	assert.False(t, slug.IsValid("-hello"))
+	assert.False(t, slug.IsValid(""))
+}
+
+func TestUnique(t *testing.T) {
+	assert.Equal(t, "hello", slug.Unique("hello", nil))
+	assert.Equal(t, "hello-2", slug.Unique("hello", []string{"hello"}))
+	assert.Equal(t, "hello-4", slug.Unique("hello", []string{"hello", "hello-2", "hello-3"}))
+
+	long := strings.Repeat("a", slug.MaxLength)
+	got := slug.Unique(long, []string{long})
+	assert.Equal(t, slug.MaxLength, utf8.RuneCountInString(got))
+	assert.True(t, strings.HasSuffix(got, "-2"))
+}
@@ -6,14 +6,46 @@ type TimeProvider interface {
 	Now() time.Time
 }
 
+// Ticker delivers the time on C every interval until stopped.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Clock is a TimeProvider that also paces periodic work, so that tests can
+// drive both.
+type Clock interface {
+	TimeProvider
+	NewTicker(d time.Duration) Ticker
+}
+
 type RealClock struct{}
 
 func (rc RealClock) Now() time.Time {
 	return time.Now()
 }
 
+func (rc RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (rt realTicker) C() <-chan time.Time {
+	return rt.ticker.C
+}
+
+func (rt realTicker) Stop() {
+	rt.ticker.Stop()
+}
+
 type MockClock struct {
 	NowFunc func() time.Time
+	// Ticks feeds every ticker of the clock, whatever its interval. Tickers
+	// never tick when it is nil.
+	Ticks chan time.Time
 }
 
 func (mc MockClock) Now() time.Time {
@@ -23,3 +55,17 @@ func (mc MockClock) Now() time.Time {
 
 	return mc.NowFunc()
 }
+
+func (mc MockClock) NewTicker(time.Duration) Ticker {
+	return mockTicker{mc.Ticks}
+}
+
+type mockTicker struct {
+	ticks chan time.Time
+}
+
+func (mt mockTicker) C() <-chan time.Time {
+	return mt.ticks
+}
+
+func (mt mockTicker) Stop() {}
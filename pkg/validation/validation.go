@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validator collects the errors of every field that fails a check so that
+// they can be reported all at once. Only the first failure of a field is kept.
+type Validator struct {
+	Errors map[string]string
+}
+
+func New() *Validator {
+	return &Validator{Errors: map[string]string{}}
+}
+
+// Check records message for field unless ok holds.
+func (v *Validator) Check(ok bool, field, message string) {
+	if ok {
+		return
+	}
+
+	if _, exists := v.Errors[field]; !exists {
+		v.Errors[field] = message
+	}
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+func NotBlank(s string) bool {
+	return strings.TrimSpace(s) != ""
+}
+
+// MaxChars reports whether s fits in n characters, which is how Postgres
+// measures VARCHAR(n).
+func MaxChars(s string, n int) bool {
+	return utf8.RuneCountInString(s) <= n
+}
+
+func MinChars(s string, n int) bool {
+	return utf8.RuneCountInString(s) >= n
+}
+
+func MaxBytes(s string, n int) bool {
+	return len(s) <= n
+}
+
+func Matches(s string, re *regexp.Regexp) bool {
+	return re.MatchString(s)
+}
+
+// Email reports whether s is a bare address such as jane@example.com, without
+// a display name or angle brackets.
+func Email(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func NotAfter(t, limit time.Time) bool {
+	return !t.After(limit)
+}
@@ -0,0 +1,55 @@
+package validation_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gera9/blog/pkg/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	v := validation.New()
+	assert.True(t, v.Valid())
+
+	v.Check(true, "title", "must not be blank")
+	v.Check(false, "email", "must be a valid email address")
+	v.Check(false, "email", "must be at most 255 characters")
+	v.Check(false, "username", "must be at most 100 characters")
+
+	assert.False(t, v.Valid())
+	assert.Equal(t, map[string]string{
+		"email":    "must be a valid email address",
+		"username": "must be at most 100 characters",
+	}, v.Errors)
+}
+
+func TestRules(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "NotBlank accepts text", got: validation.NotBlank("hello"), want: true},
+		{name: "NotBlank rejects whitespace", got: validation.NotBlank(" \t\n"), want: false},
+		{name: "MaxChars counts runes", got: validation.MaxChars("ñandú", 5), want: true},
+		{name: "MaxChars rejects longer text", got: validation.MaxChars(strings.Repeat("a", 101), 100), want: false},
+		{name: "MinChars rejects shorter text", got: validation.MinChars("short", 8), want: false},
+		{name: "MaxBytes counts bytes", got: validation.MaxBytes("ñandú", 5), want: false},
+		{name: "Matches uses the pattern", got: validation.Matches("jdoe_90", regexp.MustCompile(`^[a-z0-9_]+$`)), want: true},
+		{name: "Email accepts a bare address", got: validation.Email("jane.doe@example.com"), want: true},
+		{name: "Email rejects a display name", got: validation.Email("Jane <jane.doe@example.com>"), want: false},
+		{name: "Email rejects garbage", got: validation.Email("jane.doe"), want: false},
+		{name: "NotAfter accepts the limit", got: validation.NotAfter(now, now), want: true},
+		{name: "NotAfter rejects later times", got: validation.NotAfter(now.Add(time.Second), now), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}